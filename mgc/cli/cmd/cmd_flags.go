@@ -28,6 +28,8 @@ type cmdFlags struct {
 	childFlags     []*flag.Flag
 
 	knownFlags map[flag.NormalizedName]*flag.Flag // all known flags, both existing and schemaFlags
+
+	workspaceDefaults map[string]any // parameters property name -> value, see loadWorkspaceDefaults()
 }
 
 const childFlagSeparator = '.'
@@ -113,7 +115,50 @@ func (cf *cmdFlags) positionalArgsNames() (names []string) {
 	return
 }
 
-func applyPositionalArgs(positionalArgs []*flag.Flag, args []string) (err error) {
+// Parameters with this extension, such as the arguments of 'virtual-machine instances ssh',
+// take the positional arguments after "--" verbatim: they are not parsed as JSON or CSV,
+// so commas, quotes and "help" are kept as given. Set with the struct tag
+// `jsonschema_extras:"x-mgc-verbatim-args=true"`
+const verbatimArgsExtension = "x-mgc-verbatim-args"
+
+func verbatimPositionalArg(f *flag.Flag, value string) string {
+	fv, ok := f.Value.(schema_flags.SchemaFlagValue)
+	if !ok || fv.Desc().Schema.Type == nil {
+		return value
+	}
+
+	s := fv.Desc().Schema
+	if verbatim, _ := s.Extensions[verbatimArgsExtension].(bool); !verbatim {
+		return value
+	}
+
+	switch {
+	case s.Type.Is(openapi3.TypeString):
+		return schema_flags.ValueVerbatimStringPrefix + value
+	case s.Type.Is(openapi3.TypeArray) && (s.Items == nil || s.Items.Value == nil || s.Items.Value.Type == nil || s.Items.Value.Type.Is(openapi3.TypeString)):
+		data, err := json.Marshal([]string{value})
+		if err != nil {
+			return value
+		}
+		return string(data)
+	default:
+		return value
+	}
+}
+
+func setPositionalArg(f *flag.Flag, value string, verbatim bool) error {
+	if verbatim {
+		value = verbatimPositionalArg(f, value)
+	}
+	if err := f.Value.Set(value); err != nil {
+		return fmt.Errorf("invalid argument for %s: %s", f.Name, err.Error())
+	}
+	return nil
+}
+
+// offset is the index of the first of args, argsAtDash is the number of args before "--",
+// or -1 if not given, see cobra.Command.ArgsLenAtDash()
+func applyPositionalArgs(positionalArgs []*flag.Flag, args []string, offset int, argsAtDash int) (err error) {
 	if len(positionalArgs) < len(args) {
 		panic("programming error: len(positionalArgs) < len(args)")
 	}
@@ -121,8 +166,7 @@ func applyPositionalArgs(positionalArgs []*flag.Flag, args []string) (err error)
 	for i, value := range args {
 		f := positionalArgs[i]
 		if f.Value.String() == "" && !f.Changed {
-			if err = f.Value.Set(value); err != nil {
-				err = fmt.Errorf("invalid argument for %s: %s", f.Name, err.Error())
+			if err = setPositionalArg(f, value, argsAtDash >= 0 && offset+i >= argsAtDash); err != nil {
 				return
 			}
 		}
@@ -131,7 +175,7 @@ func applyPositionalArgs(positionalArgs []*flag.Flag, args []string) (err error)
 	return
 }
 
-func (cf *cmdFlags) positionalArgsArrays(toExpand int, args []string) (err error) {
+func (cf *cmdFlags) positionalArgsArrays(toExpand int, args []string, argsAtDash int) (err error) {
 	nArgs := len(args)
 	nPositionalArgs := len(cf.positionalArgs)
 
@@ -144,7 +188,7 @@ func (cf *cmdFlags) positionalArgsArrays(toExpand int, args []string) (err error
 		endPre = nArgs
 	}
 	if endPre > 0 {
-		if err = applyPositionalArgs(cf.positionalArgs[:endPre], args[:endPre]); err != nil {
+		if err = applyPositionalArgs(cf.positionalArgs[:endPre], args[:endPre], 0, argsAtDash); err != nil {
 			return
 		}
 	}
@@ -157,15 +201,14 @@ func (cf *cmdFlags) positionalArgsArrays(toExpand int, args []string) (err error
 	nPost := nPositionalArgs - toExpand - 1
 	startPost := nArgs - nPost
 	if toExpand <= startPost {
-		if err = applyPositionalArgs(cf.positionalArgs[toExpand+1:], args[startPost:]); err != nil {
+		if err = applyPositionalArgs(cf.positionalArgs[toExpand+1:], args[startPost:], startPost, argsAtDash); err != nil {
 			return
 		}
 
 		// actual array to handle
 		f := cf.positionalArgs[toExpand]
-		for _, value := range args[toExpand:startPost] {
-			if err = f.Value.Set(value); err != nil {
-				err = fmt.Errorf("invalid argument for %s: %s", f.Name, err.Error())
+		for i, value := range args[toExpand:startPost] {
+			if err = setPositionalArg(f, value, argsAtDash >= 0 && toExpand+i >= argsAtDash); err != nil {
 				return
 			}
 		}
@@ -174,9 +217,16 @@ func (cf *cmdFlags) positionalArgsArrays(toExpand int, args []string) (err error
 	return
 }
 
-func (cf *cmdFlags) positionalArgsFunction(cmd *cobra.Command, args []string) (err error) {
+func (cf *cmdFlags) positionalArgsFunction(cmd *cobra.Command, args []string) error {
+	if err := cf.setPositionalArgs(args, cmd.ArgsLenAtDash()); err != nil {
+		return core.UsageError{Err: err}
+	}
+	return nil
+}
+
+func (cf *cmdFlags) setPositionalArgs(args []string, argsAtDash int) (err error) {
 	if toExpand := cf.positionalArgsArrayToExpand(); toExpand >= 0 {
-		return cf.positionalArgsArrays(toExpand, args)
+		return cf.positionalArgsArrays(toExpand, args, argsAtDash)
 	}
 
 	numArgs := len(args)
@@ -199,7 +249,7 @@ func (cf *cmdFlags) positionalArgsFunction(cmd *cobra.Command, args []string) (e
 		return fmt.Errorf("this command has one or more invalid positional arguments, given: %s", strings.Join(args, ", "))
 	}
 
-	return applyPositionalArgs(cf.positionalArgs[:len(args)], args)
+	return applyPositionalArgs(cf.positionalArgs[:len(args)], args, 0, argsAtDash)
 }
 
 func completeEnum(f *flag.Flag, toComplete string, completions []string) []string {
@@ -391,7 +441,7 @@ func (cf *cmdFlags) getValues(config *mgcSdk.Config, argValues []string) (core.P
 
 	for _, f := range cf.schemaFlags {
 		var value any
-		value, err := schema_flags.GetFlagValue(f, config)
+		value, err := cf.getFlagValue(f, config)
 		logger().Debugw("parsed flag", "flag", f.Name, "desc", f.Value.(schema_flags.SchemaFlagValue).Desc(), "value", value, "error", err)
 		if err == schema_flags.ErrNoFlagValue {
 			continue
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"slices"
 
 	"github.com/MagaluCloud/magalu/mgc/cli/cmd/schema_flags"
+	"github.com/MagaluCloud/magalu/mgc/core"
 	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/config"
 
 	"github.com/spf13/cobra"
 )
@@ -328,3 +331,70 @@ func Test_cmdFlags_positionalArray(t *testing.T) {
 		})
 	}
 }
+
+func Test_cmdFlags_positionalArgsAfterDash(t *testing.T) {
+	type parameters struct {
+		Other string   `json:"other"`
+		Array []string `json:"array" jsonschema_extras:"x-mgc-verbatim-args=true"`
+	}
+
+	schema, err := mgcSchemaPkg.SchemaFromType[parameters]()
+	checkError(t, "SchemaFromType", nil, err)
+
+	flags, err := newCmdFlags(&cobra.Command{}, schema, &mgcSchemaPkg.Schema{}, []string{"other", "array"}, nil)
+	checkError(t, "newCmdFlags", nil, err)
+	cmd := &cobra.Command{Use: "testing", Args: flags.positionalArgsFunction}
+	flags.addFlags(cmd)
+
+	err = cmd.ParseFlags([]string{"otherValue", "x,y", "--", "echo a,b", `"quoted"`, "help", "-v"})
+	checkError(t, "ParseFlags", nil, err)
+	err = cmd.Args(cmd, cmd.Flags().Args())
+	checkError(t, "cmd.Args", nil, err)
+
+	v, err := flags.knownFlags["array"].Value.(schema_flags.SchemaFlagValue).Parse()
+	checkError(t, "parse array", nil, err)
+
+	var array []string
+	for _, item := range v.([]any) {
+		array = append(array, item.(string))
+	}
+
+	// only the arguments after "--" are verbatim
+	checkExpectedArray(t, "array", []string{"x", "y", "echo a,b", `"quoted"`, "help", "-v"}, array)
+	checkExpectedString(t, "other", "otherValue", flags.knownFlags["other"].Value.String())
+}
+
+func Test_cmdFlags_positionalArgsAfterDashParsed(t *testing.T) {
+	set, err := config.GetGroup().GetChildByName("set")
+	checkError(t, "GetChildByName", nil, err)
+	exec, ok := set.(core.Executor)
+	if !ok {
+		t.Fatalf("expected executor, got %T", set)
+	}
+
+	parentCmd := &cobra.Command{}
+	parentCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+	flags, err := newExecutorCmdFlags(parentCmd, exec)
+	checkError(t, "newExecutorCmdFlags", nil, err)
+	cmd := &cobra.Command{Use: "set", Args: flags.positionalArgsFunction}
+	flags.addFlags(cmd)
+
+	// without the verbatim extension, values after "--" are still parsed as JSON
+	err = cmd.ParseFlags([]string{"--", `"defaults"`, `{"virtual-machine **": {"availability-zone": "br-se1-a"}}`})
+	checkError(t, "ParseFlags", nil, err)
+	err = cmd.Args(cmd, cmd.Flags().Args())
+	checkError(t, "cmd.Args", nil, err)
+
+	v, err := flags.knownFlags["value"].Value.(schema_flags.SchemaFlagValue).Parse()
+	checkError(t, "parse value", nil, err)
+	expected := map[string]any{"virtual-machine **": map[string]any{"availability-zone": "br-se1-a"}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %v, got %#v", expected, v)
+	}
+
+	key, err := flags.knownFlags["key"].Value.(schema_flags.SchemaFlagValue).Parse()
+	checkError(t, "parse key", nil, err)
+	if key != "defaults" {
+		t.Errorf("expected the JSON string to be decoded, got %#v", key)
+	}
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
+	"github.com/MagaluCloud/magalu/mgc/sdk/openapi"
+	"github.com/spf13/cobra"
+)
+
+const (
+	dockerCredentialCmdName = "docker-credential"
+	// Docker looks for "docker-credential-<helper>" executables in $PATH
+	dockerCredentialHelperName    = "mgc"
+	dockerCredentialHelperProgram = "docker-credential-" + dockerCredentialHelperName
+	// Docker protocol requires this exact message so it knows there are no credentials
+	dockerCredentialsNotFound = "credentials not found in native keychain"
+	dockerConfigEnvVar        = "DOCKER_CONFIG"
+	dockerRegistryFlag        = "registry"
+	dockerConfigFlag          = "docker-config"
+)
+
+var (
+	dockerRegistryHostRe = regexp.MustCompile(`^container-registry\.([a-z0-9-]+)\.magalu\.cloud$`)
+	// Regions with a container registry, used when no registry is given to configure-docker
+	dockerRegistryRegions = []string{"br-se1", "br-ne1"}
+	// Path in the SDK tree of the operation that returns the registry credentials
+	dockerCredentialsExecPath = []string{"container-registry", "credentials", "get"}
+)
+
+// The symlink "docker-credential-mgc" -> "mgc" behaves as "mgc docker-credential"
+func isDockerCredentialHelperProgram(programPath string) bool {
+	name := strings.TrimSuffix(filepath.Base(programPath), ".exe")
+	return name == dockerCredentialHelperProgram
+}
+
+func dockerRegistryHost(region string) string {
+	return fmt.Sprintf("container-registry.%s.magalu.cloud", region)
+}
+
+// Docker may send just the host or a full URL, returns the region of Magalu registry hosts
+func dockerRegistryRegion(serverURL string) (host string, region string, ok bool) {
+	host = strings.TrimSpace(serverURL)
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host, _, _ = strings.Cut(host, "/")
+	host = strings.ToLower(host)
+
+	matches := dockerRegistryHostRe.FindStringSubmatch(host)
+	if matches == nil {
+		return host, "", false
+	}
+	return host, matches[1], true
+}
+
+func newDockerCredentialCmd(sdk *mgcSdk.Sdk) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   dockerCredentialCmdName,
+		Short: "Docker credential helper for Magalu Cloud container registries",
+		Long: `Implements the Docker credential helper protocol, so Docker uses the container registry
+credentials of the current workspace authentication, without 'docker login'.
+
+Docker runs "` + dockerCredentialHelperProgram + `" from $PATH, create it as a symlink to this program,
+for instance:
+
+    ln -s "$(command -v mgc)" /usr/local/bin/` + dockerCredentialHelperProgram + `
+
+Then run 'mgc ` + dockerCredentialCmdName + ` configure-docker' to use it for Magalu Cloud registries.`,
+		GroupID: "other",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get",
+		Short: "Print the credentials for the registry server URL read from stdin",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dockerCredentialGet(sdk, cmd, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	})
+
+	// Credentials are managed by Magalu Cloud, storing or erasing them is a no-op,
+	// but the input must be consumed as per the protocol
+	for _, action := range []string{"store", "erase"} {
+		cmd.AddCommand(&cobra.Command{
+			Use:   action,
+			Short: "No-op, credentials are managed by Magalu Cloud",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				_, err := io.Copy(io.Discard, cmd.InOrStdin())
+				return err
+			},
+		})
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the registries handled by this helper",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Usernames are only known after asking the API, which Docker doesn't require for list
+			hosts := map[string]string{}
+			for _, region := range dockerRegistryRegions {
+				hosts[dockerRegistryHost(region)] = ""
+			}
+			return json.NewEncoder(cmd.OutOrStdout()).Encode(hosts)
+		},
+	})
+
+	cmd.AddCommand(newDockerConfigureCmd())
+
+	return cmd
+}
+
+type dockerCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+func dockerCredentialGet(sdk *mgcSdk.Sdk, cmd *cobra.Command, stdin io.Reader, stdout io.Writer) error {
+	input, err := io.ReadAll(stdin)
+	if err != nil {
+		return err
+	}
+
+	serverURL := strings.TrimSpace(string(input))
+	host, region, ok := dockerRegistryRegion(serverURL)
+	if !ok {
+		logger().Debugw("not a Magalu Cloud registry", "serverURL", serverURL)
+		fmt.Fprintln(stdout, dockerCredentialsNotFound)
+		return errors.New(dockerCredentialsNotFound)
+	}
+
+	credentials, err := fetchDockerCredentials(sdk, cmd, region)
+	if err != nil {
+		return fmt.Errorf("unable to get credentials for %q: %w", host, err)
+	}
+
+	return json.NewEncoder(stdout).Encode(dockerCredentials{
+		ServerURL: serverURL,
+		Username:  credentials.Username,
+		Secret:    credentials.Password,
+	})
+}
+
+type containerRegistryCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func fetchDockerCredentials(sdk *mgcSdk.Sdk, cmd *cobra.Command, region string) (*containerRegistryCredentials, error) {
+	var child core.Descriptor = sdk.Group()
+	for _, name := range dockerCredentialsExecPath {
+		grouper, ok := child.(core.Grouper)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a group", child.Name())
+		}
+		var err error
+		if child, err = findChildByNameOrAliases(grouper, name); err != nil {
+			return nil, err
+		}
+	}
+	exec, ok := child.(core.Executor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an executor", child.Name())
+	}
+
+	configs := core.Configs{"region": region}
+	var env string
+	if err := sdk.Config().Get("env", &env); err == nil && env != "" {
+		configs["env"] = env
+	}
+
+	setApiKey(cmd, sdk)
+
+	// stdout is reserved for the protocol, no spinners or other messages
+	ctx := openapi.WithRawOutputFlag(sdk.NewContext(), true)
+	result, err := handleExecutorPre(ctx, sdk, cmd, exec, core.Parameters{}, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	resultWithValue, ok := core.ResultAs[core.ResultWithValue](result)
+	if !ok {
+		return nil, fmt.Errorf("credentials returned no value")
+	}
+
+	return utils.DecodeNewValue[containerRegistryCredentials](resultWithValue.Value())
+}
+
+func newDockerConfigureCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "configure-docker",
+		Short: "Configure Docker to use this credential helper for Magalu Cloud registries",
+		Long: `Sets "credHelpers" of the Docker configuration file, so Docker uses "` + dockerCredentialHelperProgram + `"
+for the given registries. Other settings of the file are kept.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registries, err := cmd.Flags().GetStringSlice(dockerRegistryFlag)
+			if err != nil {
+				return err
+			}
+			file, err := cmd.Flags().GetString(dockerConfigFlag)
+			if err != nil {
+				return err
+			}
+			if file == "" {
+				if file, err = defaultDockerConfigFile(); err != nil {
+					return err
+				}
+			}
+
+			if err = configureDockerCredHelpers(file, registries); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Configured %s to use %q for: %s\n", file, dockerCredentialHelperProgram, strings.Join(registries, ", "))
+			if _, err := exec.LookPath(dockerCredentialHelperProgram); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %q was not found in $PATH, see 'mgc %s --help'\n", dockerCredentialHelperProgram, dockerCredentialCmdName)
+			}
+			return nil
+		},
+	}
+
+	defaultRegistries := make([]string, 0, len(dockerRegistryRegions))
+	for _, region := range dockerRegistryRegions {
+		defaultRegistries = append(defaultRegistries, dockerRegistryHost(region))
+	}
+	cmd.Flags().StringSlice(dockerRegistryFlag, defaultRegistries, "Registry hosts to configure")
+	cmd.Flags().String(dockerConfigFlag, "", "Docker configuration file. Defaults to $DOCKER_CONFIG/config.json or ~/.docker/config.json")
+
+	return cmd
+}
+
+func defaultDockerConfigFile() (string, error) {
+	if dir := os.Getenv(dockerConfigEnvVar); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".docker", "config.json"), nil
+}
+
+func configureDockerCredHelpers(file string, registries []string) error {
+	config := map[string]any{}
+
+	data, err := os.ReadFile(file)
+	if err == nil {
+		if err = json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("invalid Docker configuration %q: %w", file, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	credHelpers := map[string]any{}
+	if existing, ok := config["credHelpers"].(map[string]any); ok {
+		credHelpers = maps.Clone(existing)
+	}
+	for _, registry := range registries {
+		host, _, _ := dockerRegistryRegion(registry)
+		credHelpers[host] = dockerCredentialHelperName
+	}
+	config["credHelpers"] = credHelpers
+
+	logger().Debugw("configuring Docker credential helpers", "file", file, "credHelpers", credHelpers)
+
+	data, err = json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0600)
+}
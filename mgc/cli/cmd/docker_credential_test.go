@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_dockerRegistryRegion(t *testing.T) {
+	tests := []struct {
+		input  string
+		host   string
+		region string
+		ok     bool
+	}{
+		{"container-registry.br-se1.magalu.cloud", "container-registry.br-se1.magalu.cloud", "br-se1", true},
+		{"https://container-registry.br-ne1.magalu.cloud/v2/", "container-registry.br-ne1.magalu.cloud", "br-ne1", true},
+		{"container-registry.br-se1.magalu.cloud/my-registry/image", "container-registry.br-se1.magalu.cloud", "br-se1", true},
+		{"https://index.docker.io/v1/", "index.docker.io", "", false},
+		{"magalu.cloud", "magalu.cloud", "", false},
+	}
+	for _, tc := range tests {
+		host, region, ok := dockerRegistryRegion(tc.input)
+		checkExpectedString(t, tc.input, tc.host, host)
+		checkExpectedString(t, tc.input, tc.region, region)
+		if ok != tc.ok {
+			t.Errorf("%s: expected ok=%v, got %v", tc.input, tc.ok, ok)
+		}
+	}
+}
+
+func Test_isDockerCredentialHelperProgram(t *testing.T) {
+	if !isDockerCredentialHelperProgram("/usr/local/bin/docker-credential-mgc") {
+		t.Errorf("expected symlink name to be detected")
+	}
+	if !isDockerCredentialHelperProgram(`docker-credential-mgc.exe`) {
+		t.Errorf("expected windows executable name to be detected")
+	}
+	if isDockerCredentialHelperProgram("/usr/local/bin/mgc") {
+		t.Errorf("expected regular program name to be ignored")
+	}
+}
+
+func Test_configureDockerCredHelpers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(file, []byte(`{"auths":{"docker.io":{}},"credHelpers":{"gcr.io":"gcloud"}}`), 0600)
+	checkError(t, "WriteFile", nil, err)
+
+	err = configureDockerCredHelpers(file, []string{"https://container-registry.br-se1.magalu.cloud"})
+	checkError(t, "configureDockerCredHelpers", nil, err)
+
+	data, err := os.ReadFile(file)
+	checkError(t, "ReadFile", nil, err)
+
+	var config struct {
+		Auths       map[string]any    `json:"auths"`
+		CredHelpers map[string]string `json:"credHelpers"`
+	}
+	err = json.Unmarshal(data, &config)
+	checkError(t, "Unmarshal", nil, err)
+
+	if _, ok := config.Auths["docker.io"]; !ok {
+		t.Errorf("expected other settings to be kept, got %s", data)
+	}
+	checkExpectedString(t, "gcr.io", "gcloud", config.CredHelpers["gcr.io"])
+	checkExpectedString(t, "magalu", "mgc", config.CredHelpers["container-registry.br-se1.magalu.cloud"])
+}
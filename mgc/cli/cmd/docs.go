@@ -0,0 +1,461 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/cli/cmd/schema_flags"
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	docsFormatMan        = "man"
+	docsFormatMarkdown   = "markdown"
+	docsFormatJSONSchema = "json-schema"
+)
+
+var docsFormats = []string{docsFormatMan, docsFormatMarkdown, docsFormatJSONSchema}
+
+type docsFlag struct {
+	Name        string       `json:"name"`
+	Type        string       `json:"type"`
+	Description string       `json:"description,omitempty"`
+	Constraints string       `json:"constraints,omitempty"`
+	Default     string       `json:"default,omitempty"`
+	Example     string       `json:"example,omitempty"`
+	Required    bool         `json:"required"`
+	Config      bool         `json:"config"`
+	Positional  bool         `json:"positional"`
+	Schema      *core.Schema `json:"schema"`
+}
+
+type docsLink struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Usage       string `json:"usage"`
+}
+
+type docsCommand struct {
+	Command      string       `json:"command"`
+	Path         []string     `json:"path"`
+	Usage        string       `json:"usage"`
+	Aliases      []string     `json:"aliases,omitempty"`
+	Summary      string       `json:"summary,omitempty"`
+	Description  string       `json:"description,omitempty"`
+	Version      string       `json:"version,omitempty"`
+	Scopes       core.Scopes  `json:"scopes,omitempty"`
+	Observations string       `json:"observations,omitempty"`
+	Example      string       `json:"example,omitempty"`
+	Flags        []docsFlag   `json:"flags"`
+	Links        []docsLink   `json:"links,omitempty"`
+	Parameters   *core.Schema `json:"parameters"`
+	Configs      *core.Schema `json:"configs"`
+	Result       *core.Schema `json:"result"`
+}
+
+type docsCatalogue struct {
+	Program  string        `json:"program"`
+	Version  string        `json:"version"`
+	Commands []docsCommand `json:"commands"`
+}
+
+func newDocsFlag(f *flag.Flag, positional bool) (d docsFlag, ok bool) {
+	fv, ok := f.Value.(schema_flags.SchemaFlagValue)
+	if !ok {
+		return
+	}
+	desc := fv.Desc()
+	if desc.IsHidden {
+		return d, false
+	}
+
+	d = docsFlag{
+		Name:        f.Name,
+		Type:        desc.FlagType(),
+		Description: desc.Description(),
+		Default:     desc.RawDefaultValue(),
+		Example:     getFlagFormattedExample(f),
+		Required:    desc.IsRequired,
+		Config:      desc.IsConfig,
+		Positional:  positional,
+		Schema:      getCleanJSONSchema(desc.Schema),
+	}
+	if constraints := desc.HumanReadableConstraints(); constraints != nil {
+		d.Constraints = strings.TrimRight(getConstraintsFormatted(constraints, "", false), "\n")
+	}
+	return d, true
+}
+
+// Builds the flags the same way addAction() does, but attached to a temporary parent so the
+// actual command tree is not changed
+func newDocsCommand(root *cobra.Command, exec core.Executor, path []string) (d docsCommand, err error) {
+	parentCmd := &cobra.Command{Use: "docs-parent"}
+	root.AddCommand(parentCmd)
+	defer root.RemoveCommand(parentCmd)
+
+	flags, err := newExecutorCmdFlags(parentCmd, exec)
+	if err != nil {
+		return d, fmt.Errorf("%s: %w", strings.Join(path, " "), err)
+	}
+
+	names := make([]string, len(path))
+	for i, p := range path {
+		names[i], _ = getCommandNameAndAliases(p)
+	}
+	name, aliases := getCommandNameAndAliases(exec.Name())
+	names[len(names)-1] = name
+	cmdPath := root.Name() + " " + strings.Join(names, " ")
+
+	d = docsCommand{
+		Command:      cmdPath,
+		Path:         names,
+		Usage:        root.Name() + " " + strings.Join(names[:len(names)-1], " ") + " " + buildUse(name, flags.positionalArgsNames()),
+		Aliases:      aliases,
+		Summary:      exec.Summary(),
+		Description:  exec.Description(),
+		Version:      exec.DescriptorSpec().Version,
+		Scopes:       exec.Scopes(),
+		Observations: exec.DescriptorSpec().Observations,
+		Example:      strings.TrimSpace(flags.example(cmdPath)),
+		Flags:        []docsFlag{},
+		Parameters:   mgcSchemaPkg.InlineRefs(exec.ParametersSchema()),
+		Configs:      mgcSchemaPkg.InlineRefs(exec.ConfigsSchema()),
+		Result:       mgcSchemaPkg.InlineRefs(exec.ResultSchema()),
+	}
+	d.Usage = strings.Join(strings.Fields(d.Usage), " ")
+
+	for _, f := range slices.Concat(flags.schemaFlags, flags.childFlags) {
+		if df, ok := newDocsFlag(f, slices.Contains(flags.positionalArgs, f)); ok {
+			d.Flags = append(d.Flags, df)
+		}
+	}
+	slices.SortFunc(d.Flags, func(a, b docsFlag) int {
+		if a.Config != b.Config {
+			if a.Config {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for linkName, link := range exec.Links() {
+		if link.IsInternal() {
+			continue
+		}
+		d.Links = append(d.Links, docsLink{
+			Name:        linkName,
+			Description: link.Description(),
+			Usage:       fmt.Sprintf("%s ... ! %s", cmdPath, linkName),
+		})
+	}
+	slices.SortFunc(d.Links, func(a, b docsLink) int { return strings.Compare(a.Name, b.Name) })
+
+	return d, nil
+}
+
+func collectDocsCatalogue(root *cobra.Command, group core.Grouper, version string, includeInternal bool) (*docsCatalogue, error) {
+	catalogue := &docsCatalogue{Program: root.Name(), Version: version, Commands: []docsCommand{}}
+
+	_, err := core.VisitAllExecutors(group, []string{}, includeInternal, func(exec core.Executor, path []string) (bool, error) {
+		d, err := newDocsCommand(root, exec, path)
+		if err != nil {
+			return false, err
+		}
+		catalogue.Commands = append(catalogue.Commands, d)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(catalogue.Commands, func(a, b docsCommand) int { return strings.Compare(a.Command, b.Command) })
+	return catalogue, nil
+}
+
+func writeDocsFile(dir, name, contents string) error {
+	return os.WriteFile(filepath.Join(dir, name), []byte(contents), utils.FILE_PERMISSION)
+}
+
+// Files are named after the command path, such as "mgc-virtual-machine-instances-create.1"
+func docsFileName(d docsCommand, separator, extension string) string {
+	return strings.ReplaceAll(d.Command, " ", separator) + extension
+}
+
+var manEscaper = strings.NewReplacer(`\`, `\e`, "-", `\-`)
+
+// Escapes text and the lines starting with control characters
+func manEscape(s string) string {
+	lines := strings.Split(manEscaper.Replace(s), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func manPreformatted(s string) string {
+	return ".nf\n" + manEscape(s) + "\n.fi\n"
+}
+
+func renderManPage(catalogue *docsCatalogue, d docsCommand) string {
+	var b strings.Builder
+	title := strings.ToUpper(docsFileName(d, "-", ""))
+	fmt.Fprintf(&b, ".TH \"%s\" \"1\" \"\" \"%s %s\" \"Magalu Cloud CLI\"\n", manEscape(title), catalogue.Program, manEscape(catalogue.Version))
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s \\- %s\n", manEscape(docsFileName(d, "-", "")), manEscape(d.Summary))
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, "\\fB%s\\fR [flags]\n", manEscape(d.Usage))
+
+	if d.Description != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+		b.WriteString(manEscape(d.Description) + "\n")
+	}
+
+	writeFlags := func(title string, config bool) {
+		header := false
+		for _, f := range d.Flags {
+			if f.Config != config {
+				continue
+			}
+			if !header {
+				b.WriteString(".SH " + title + "\n")
+				header = true
+			}
+			b.WriteString(".TP\n")
+			fmt.Fprintf(&b, "\\fB\\-\\-%s\\fR=\\fI%s\\fR", manEscape(f.Name), manEscape(f.Type))
+			if f.Required {
+				b.WriteString(" (required)")
+			}
+			b.WriteString("\n")
+			if f.Description != "" {
+				b.WriteString(manEscape(f.Description) + "\n")
+			}
+			if f.Constraints != "" {
+				b.WriteString(".RS\n" + manPreformatted(f.Constraints) + ".RE\n")
+			}
+		}
+	}
+	writeFlags("FLAGS", false)
+	writeFlags("CONFIGURATION FLAGS", true)
+
+	if d.Example != "" {
+		b.WriteString(".SH EXAMPLES\n")
+		b.WriteString(manPreformatted(d.Example))
+	}
+
+	if len(d.Links) > 0 {
+		b.WriteString(".SH LINKS\n")
+		b.WriteString("Commands that can be chained to operate on the result, separated by \\fB!\\fR.\n")
+		for _, l := range d.Links {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n", manEscape(l.Usage))
+			if l.Description != "" {
+				b.WriteString(manEscape(l.Description) + "\n")
+			}
+		}
+	}
+
+	if d.Observations != "" {
+		b.WriteString(".SH NOTES\n")
+		b.WriteString(manEscape(d.Observations) + "\n")
+	}
+
+	if len(d.Scopes) > 0 {
+		b.WriteString(".SH SCOPES\n")
+		scopes := make([]string, len(d.Scopes))
+		for i, s := range d.Scopes {
+			scopes[i] = string(s)
+		}
+		b.WriteString(manEscape(strings.Join(scopes, ", ")) + "\n")
+	}
+
+	return b.String()
+}
+
+func markdownIndent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
+
+func renderMarkdownPage(d docsCommand) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", d.Command)
+	if d.Summary != "" {
+		fmt.Fprintf(&b, "%s\n\n", d.Summary)
+	}
+
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s [flags]\n```\n\n", d.Usage)
+
+	if d.Description != "" && d.Description != d.Summary {
+		fmt.Fprintf(&b, "## Description\n\n%s\n\n", d.Description)
+	}
+
+	writeFlags := func(title string, config bool) {
+		header := false
+		for _, f := range d.Flags {
+			if f.Config != config {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(&b, "## %s\n\n", title)
+				header = true
+			}
+			fmt.Fprintf(&b, "- `--%s=%s`", f.Name, f.Type)
+			if f.Required {
+				b.WriteString(" (required)")
+			}
+			if f.Description != "" {
+				b.WriteString(": " + strings.ReplaceAll(f.Description, "\n", " "))
+			}
+			b.WriteString("\n")
+			if f.Constraints != "" {
+				fmt.Fprintf(&b, "\n%s\n\n", markdownIndent(f.Constraints, "      "))
+			}
+		}
+		if header {
+			b.WriteString("\n")
+		}
+	}
+	writeFlags("Flags", false)
+	writeFlags("Configuration flags", true)
+
+	if d.Example != "" {
+		fmt.Fprintf(&b, "## Examples\n\n```\n%s\n```\n\n", d.Example)
+	}
+
+	if len(d.Links) > 0 {
+		b.WriteString("## Links\n\nCommands that can be chained to operate on the result, separated by `!`:\n\n")
+		for _, l := range d.Links {
+			fmt.Fprintf(&b, "- `%s`", l.Usage)
+			if l.Description != "" {
+				b.WriteString(": " + strings.ReplaceAll(l.Description, "\n", " "))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if d.Observations != "" {
+		fmt.Fprintf(&b, "## Notes\n\n%s\n\n", d.Observations)
+	}
+
+	if len(d.Scopes) > 0 {
+		scopes := make([]string, len(d.Scopes))
+		for i, s := range d.Scopes {
+			scopes[i] = "`" + string(s) + "`"
+		}
+		fmt.Fprintf(&b, "## Scopes\n\n%s\n", strings.Join(scopes, ", "))
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func renderMarkdownIndex(catalogue *docsCatalogue) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s commands\n\n", catalogue.Program)
+	for _, d := range catalogue.Commands {
+		fmt.Fprintf(&b, "- [%s](%s)", d.Command, docsFileName(d, "_", ".md"))
+		if d.Summary != "" {
+			b.WriteString(": " + strings.ReplaceAll(d.Summary, "\n", " "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func generateDocs(catalogue *docsCatalogue, format, dir string) error {
+	if format == docsFormatJSONSchema && dir == "" {
+		data, err := json.MarshalIndent(catalogue, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Println(string(data))
+		return err
+	}
+
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, utils.DIR_PERMISSION); err != nil {
+		return err
+	}
+
+	switch format {
+	case docsFormatJSONSchema:
+		data, err := json.MarshalIndent(catalogue, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeDocsFile(dir, catalogue.Program+".json", string(data)+"\n")
+
+	case docsFormatMan:
+		for _, d := range catalogue.Commands {
+			if err := writeDocsFile(dir, docsFileName(d, "-", ".1"), renderManPage(catalogue, d)); err != nil {
+				return err
+			}
+		}
+
+	case docsFormatMarkdown:
+		for _, d := range catalogue.Commands {
+			if err := writeDocsFile(dir, docsFileName(d, "_", ".md"), renderMarkdownPage(d)); err != nil {
+				return err
+			}
+		}
+		return writeDocsFile(dir, "README.md", renderMarkdownIndex(catalogue))
+
+	default:
+		return core.UsageError{Err: fmt.Errorf("unknown format %q, use one of: %s", format, strings.Join(docsFormats, ", "))}
+	}
+	return nil
+}
+
+func newDocsCmd(sdk *mgcSdk.Sdk) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "docs",
+		Short:   "Generate the reference of the commands",
+		GroupID: "other",
+	}
+
+	var format, dir string
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate man pages, markdown or a JSON catalogue of all commands",
+		Long: `Walk through all commands and generate their reference, with the flags, their constraints,
+examples and links to other commands:
+
+  - ` + docsFormatMan + `: one man page per command, such as "mgc-virtual-machine-instances-create.1";
+  - ` + docsFormatMarkdown + `: one page per command and a README.md index;
+  - ` + docsFormatJSONSchema + `: a catalogue of all commands with the JSON Schema of their
+    parameters, configs and results, to be consumed by editors and other tools. Printed
+    to the standard output unless --dir is given.`,
+		Example: "mgc docs generate --format man --dir /usr/local/share/man/man1",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(docsFormats, format) {
+				return core.UsageError{Err: fmt.Errorf("unknown format %q, use one of: %s", format, strings.Join(docsFormats, ", "))}
+			}
+
+			catalogue, err := collectDocsCatalogue(cmd.Root(), sdk.Group(), sdk.GetVersion(), getShowInternalFlag(cmd))
+			if err != nil {
+				return err
+			}
+			return generateDocs(catalogue, format, dir)
+		},
+	}
+	generateCmd.Flags().StringVar(&format, "format", docsFormatMarkdown, "One of: "+strings.Join(docsFormats, ", "))
+	generateCmd.Flags().StringVar(&dir, "dir", "", "Directory to write the files to, defaults to the current directory")
+
+	cmd.AddCommand(generateCmd)
+	return cmd
+}
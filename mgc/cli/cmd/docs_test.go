@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/spf13/cobra"
+)
+
+func newDocsTestGroup() core.Grouper {
+	type params struct {
+		Name  string `json:"name" jsonschema:"description=Name of the bucket,minLength=3,maxLength=63" mapstructure:"name"`
+		Limit int    `json:"_limit,omitempty" jsonschema:"description=Maximum number of results,minimum=1,maximum=100" mapstructure:"_limit"`
+		Class string `json:"class,omitempty" jsonschema:"enum=standard,enum=cold" mapstructure:"class"`
+	}
+	type result struct {
+		Name string `json:"name"`
+	}
+
+	create := core.NewStaticExecute(
+		core.DescriptorSpec{Name: "create", Summary: "Create a bucket", Description: "Create a bucket.\n.hidden roff request"},
+		func(ctx context.Context, p params, _ struct{}) (*result, error) { return nil, nil },
+	)
+	buckets := core.NewSimpleGrouper(
+		core.DescriptorSpec{Name: "buckets", Description: "Buckets"},
+		func() ([]core.Descriptor, error) { return []core.Descriptor{create}, nil },
+	)
+	module := core.NewSimpleGrouper(
+		core.DescriptorSpec{Name: "object-storage", Description: "Object storage"},
+		func() ([]core.Descriptor, error) { return []core.Descriptor{buckets}, nil },
+	)
+	return core.NewSimpleGrouper(
+		core.DescriptorSpec{Name: "root", Description: "Root"},
+		func() ([]core.Descriptor, error) { return []core.Descriptor{module}, nil },
+	)
+}
+
+func newDocsTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "mgc"}
+	root.SetGlobalNormalizationFunc(normalizeFlagName)
+	addOutputFlag(root)
+	return root
+}
+
+func TestCollectDocsCatalogue(t *testing.T) {
+	catalogue, err := collectDocsCatalogue(newDocsTestRoot(), newDocsTestGroup(), "v1.0.0", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(catalogue.Commands) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(catalogue.Commands))
+	}
+
+	d := catalogue.Commands[0]
+	checkExpectedString(t, "command", "mgc object-storage buckets create", d.Command)
+	checkExpectedArray(t, "path", []string{"object-storage", "buckets", "create"}, d.Path)
+	if d.Parameters == nil || d.Result == nil {
+		t.Errorf("expected parameters and result schemas")
+	}
+
+	names := []string{}
+	for _, f := range d.Flags {
+		names = append(names, f.Name)
+	}
+	checkExpectedArray(t, "flags", []string{"class", "control.limit", "name"}, names)
+
+	class := d.Flags[0]
+	if !strings.Contains(class.Constraints, `"cold"`) || !strings.Contains(class.Constraints, `"standard"`) {
+		t.Errorf("expected enum constraints, got %q", class.Constraints)
+	}
+	name := d.Flags[2]
+	if !name.Required || !strings.Contains(name.Constraints, "between 3 and 63 characters") {
+		t.Errorf("expected required name with length constraints, got %#v", name)
+	}
+}
+
+func TestGenerateDocs(t *testing.T) {
+	catalogue, err := collectDocsCatalogue(newDocsTestRoot(), newDocsTestGroup(), "v1.0.0", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dir := t.TempDir()
+	for _, format := range docsFormats {
+		if err := generateDocs(catalogue, format, dir); err != nil {
+			t.Fatalf("%s: unexpected error: %s", format, err)
+		}
+	}
+
+	man, err := os.ReadFile(filepath.Join(dir, "mgc-object-storage-buckets-create.1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, expected := range []string{
+		`.TH "MGC\-OBJECT\-STORAGE\-BUCKETS\-CREATE" "1"`,
+		`\fB\-\-name\fR=\fIstring\fR (required)`,
+		"\n\\&.hidden roff request\n",
+	} {
+		if !strings.Contains(string(man), expected) {
+			t.Errorf("man page: expected %q in:\n%s", expected, man)
+		}
+	}
+
+	markdown, err := os.ReadFile(filepath.Join(dir, "mgc_object-storage_buckets_create.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(markdown), "- `--name=string` (required)") {
+		t.Errorf("markdown: expected the name flag in:\n%s", markdown)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(index), "[mgc object-storage buckets create](mgc_object-storage_buckets_create.md)") {
+		t.Errorf("index: expected the command link in:\n%s", index)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "mgc.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var decoded docsCatalogue
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(decoded.Commands) != 1 || decoded.Commands[0].Parameters == nil {
+		t.Errorf("expected the command with its schemas, got %s", data)
+	}
+
+	if err := generateDocs(catalogue, "pdf", dir); ExitCode(err) != ExitCodeUsage {
+		t.Errorf("expected a usage error for an unknown format, got %v", err)
+	}
+}
+
+func TestCollectDocsCatalogueInlinesRefs(t *testing.T) {
+	getSchema := core.NewStaticExecuteSimple(
+		core.DescriptorSpec{Name: "get-schema", Summary: "Get the schema of a config", Description: "Get the schema of a config"},
+		func(ctx context.Context) (*core.Schema, error) { return nil, nil },
+	)
+	group := core.NewSimpleGrouper(
+		core.DescriptorSpec{Name: "root", Description: "Root"},
+		func() ([]core.Descriptor, error) { return []core.Descriptor{getSchema}, nil },
+	)
+
+	catalogue, err := collectDocsCatalogue(newDocsTestRoot(), group, "v1.0.0", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data, err := json.Marshal(catalogue)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Contains(string(data), `"$ref"`) {
+		t.Errorf("expected schemas without references, got %s", data)
+	}
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/auth"
+	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes by kind of error, so scripts can branch on them. See README.md
+const (
+	ExitCodeError           = 1
+	ExitCodeUsage           = 2
+	ExitCodeAuth            = 3
+	ExitCodeNotFound        = 4
+	ExitCodeConflict        = 5
+	ExitCodeServer          = 6
+	ExitCodeTimeout         = 7
+	ExitCodeWaitTermination = 8
+)
+
+const (
+	errorKindError           = "error"
+	errorKindUsage           = "usage"
+	errorKindAuth            = "auth"
+	errorKindNotFound        = "not_found"
+	errorKindConflict        = "conflict"
+	errorKindServer          = "server"
+	errorKindTimeout         = "timeout"
+	errorKindWaitTermination = "wait_termination"
+)
+
+var exitCodeByErrorKind = map[string]int{
+	errorKindError:           ExitCodeError,
+	errorKindUsage:           ExitCodeUsage,
+	errorKindAuth:            ExitCodeAuth,
+	errorKindNotFound:        ExitCodeNotFound,
+	errorKindConflict:        ExitCodeConflict,
+	errorKindServer:          ExitCodeServer,
+	errorKindTimeout:         ExitCodeTimeout,
+	errorKindWaitTermination: ExitCodeWaitTermination,
+}
+
+// Not logged in or missing the scopes of the operation
+type authError struct {
+	Err error
+}
+
+func (e authError) Unwrap() error {
+	return e.Err
+}
+
+func (e authError) Error() string {
+	return e.Err.Error()
+}
+
+// The error was already written as a structured document, it must not be printed again
+type reportedError struct {
+	Err error
+}
+
+func (e reportedError) Unwrap() error {
+	return e.Err
+}
+
+func (e reportedError) Error() string {
+	return e.Err.Error()
+}
+
+func IsErrorReported(err error) bool {
+	return errors.As(err, new(reportedError))
+}
+
+func httpErrorKind(code int) string {
+	switch {
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return errorKindAuth
+	case code == http.StatusNotFound:
+		return errorKindNotFound
+	case code == http.StatusConflict, code == http.StatusPreconditionFailed:
+		return errorKindConflict
+	case code == http.StatusRequestTimeout, code == http.StatusGatewayTimeout:
+		return errorKindTimeout
+	case code == http.StatusBadRequest, code == http.StatusUnprocessableEntity:
+		return errorKindUsage
+	case code >= 500:
+		return errorKindServer
+	default:
+		return errorKindError
+	}
+}
+
+func getErrorKind(err error) string {
+	var timeoutErr interface{ Timeout() bool }
+	var httpErr *mgcHttpPkg.HttpError
+
+	switch {
+	case errors.As(err, new(core.FailedTerminationError)):
+		return errorKindWaitTermination
+	case errors.As(err, new(core.UsageError)):
+		return errorKindUsage
+	case errors.As(err, new(authError)), errors.Is(err, auth.ErrNoRefreshToken), errors.As(err, new(auth.FailedRefreshAccessToken)):
+		return errorKindAuth
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &timeoutErr) && timeoutErr.Timeout():
+		return errorKindTimeout
+	case errors.As(err, &httpErr):
+		return httpErrorKind(httpErr.Code)
+	default:
+		return errorKindError
+	}
+}
+
+// Flags are parsed by cobra before the command runs, so its errors are wrapped here.
+// See cobra.Command.SetFlagErrorFunc()
+func flagUsageError(cmd *cobra.Command, err error) error {
+	return core.UsageError{Err: err}
+}
+
+func getExitCode(err error, kind string) int {
+	var programErr core.ProgramExitError
+	if errors.As(err, &programErr) && programErr.Code > 0 {
+		return programErr.Code
+	}
+	return exitCodeByErrorKind[kind]
+}
+
+// Exit code for the error returned by Execute(). Programs run by the command, such as ssh,
+// exit the CLI with their own code
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return getExitCode(err, getErrorKind(err))
+}
+
+type errorDetail struct {
+	Flag    string `json:"flag,omitempty" yaml:"flag,omitempty"`
+	Field   string `json:"field,omitempty" yaml:"field,omitempty"`
+	Message string `json:"message" yaml:"message"`
+}
+
+type errorInfo struct {
+	Kind      string        `json:"kind" yaml:"kind"`
+	ExitCode  int           `json:"exit_code" yaml:"exit_code"`
+	Message   string        `json:"message" yaml:"message"`
+	Status    int           `json:"status,omitempty" yaml:"status,omitempty"`
+	Slug      string        `json:"slug,omitempty" yaml:"slug,omitempty"`
+	RequestID string        `json:"request_id,omitempty" yaml:"request_id,omitempty"`
+	TraceID   string        `json:"trace_id,omitempty" yaml:"trace_id,omitempty"`
+	Details   []errorDetail `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+type errorDocument struct {
+	Error errorInfo `json:"error" yaml:"error"`
+}
+
+// Validation details of usage errors, such as the invalid flags
+func getUsageErrorDetails(err error) (details []errorDetail) {
+	var multiErr utils.MultiError
+	var schemaMultiErr openapi3.MultiError
+	var flagErr *flagError
+	var requiredErr requiredFlagsError
+	var schemaErr *openapi3.SchemaError
+
+	switch {
+	case errors.As(err, &multiErr):
+		for _, e := range multiErr {
+			details = append(details, getUsageErrorDetails(e)...)
+		}
+	case errors.As(err, &schemaMultiErr):
+		for _, e := range schemaMultiErr {
+			details = append(details, getUsageErrorDetails(e)...)
+		}
+	case errors.As(err, &requiredErr):
+		for _, f := range requiredErr {
+			details = append(details, errorDetail{Flag: "--" + f.Name, Message: "missing required flag"})
+		}
+	case errors.As(err, &flagErr):
+		details = append(details, errorDetail{Flag: "--" + flagErr.Flag.Name, Message: flagErr.Err.Error()})
+	case errors.As(err, &schemaErr):
+		details = append(details, errorDetail{Field: strings.Join(schemaErr.JSONPointer(), "."), Message: schemaErr.Reason})
+	default:
+		details = append(details, errorDetail{Message: err.Error()})
+	}
+	return
+}
+
+func newErrorDocument(err error) errorDocument {
+	kind := getErrorKind(err)
+	info := errorInfo{
+		Kind:     kind,
+		ExitCode: getExitCode(err, kind),
+		Message:  err.Error(),
+	}
+
+	var httpErr *mgcHttpPkg.HttpError
+	if errors.As(err, &httpErr) {
+		info.Status = httpErr.Code
+		info.Slug = httpErr.Slug
+		info.Message = httpErr.Message
+	}
+
+	var identifiableErr *mgcHttpPkg.IdentifiableHttpError
+	if errors.As(err, &identifiableErr) {
+		info.RequestID = identifiableErr.RequestID
+		info.TraceID = identifiableErr.TraceID
+	}
+
+	var usageErr core.UsageError
+	if errors.As(err, &usageErr) {
+		info.Details = getUsageErrorDetails(usageErr.Err)
+	}
+
+	return errorDocument{Error: info}
+}
+
+// Whether errors are written as JSON or YAML to stderr, so it must have nothing else
+func isStructuredErrorOutput(cmd *cobra.Command) bool {
+	name, _ := parseOutputFormatter(getOutputFlag(cmd))
+	return name == "json" || name == "yaml"
+}
+
+// If the output is JSON or YAML, errors are also written as such to stderr, then they're
+// wrapped in reportedError so they aren't printed again
+func reportStructuredError(cmd *cobra.Command, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	name, options := parseOutputFormatter(getOutputFlag(cmd))
+
+	var data []byte
+	var encodeErr error
+	switch name {
+	case "json":
+		if options == "compact" {
+			data, encodeErr = json.Marshal(newErrorDocument(err))
+		} else {
+			data, encodeErr = json.MarshalIndent(newErrorDocument(err), "", " ")
+		}
+	case "yaml":
+		data, encodeErr = yaml.Marshal(newErrorDocument(err))
+	default:
+		return err
+	}
+
+	if encodeErr != nil {
+		logger().Debugw("unable to encode error", "error", encodeErr)
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, strings.TrimSuffix(string(data), "\n"))
+	return reportedError{Err: err}
+}
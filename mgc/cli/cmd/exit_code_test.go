@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+func newTestHttpError(code int) error {
+	return &mgcHttpPkg.IdentifiableHttpError{
+		HttpError: &mgcHttpPkg.HttpError{
+			Code:    code,
+			Status:  fmt.Sprintf("%d %s", code, http.StatusText(code)),
+			Message: "some message",
+			Slug:    "some-slug",
+		},
+		RequestID: "request-id",
+		TraceID:   "trace-id",
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, 0},
+		{errors.New("other"), ExitCodeError},
+		{core.UsageError{Err: errors.New("bad flag")}, ExitCodeUsage},
+		{authError{Err: errors.New("not logged in")}, ExitCodeAuth},
+		{newTestHttpError(http.StatusForbidden), ExitCodeAuth},
+		{newTestHttpError(http.StatusNotFound), ExitCodeNotFound},
+		{fmt.Errorf("wrapped: %w", newTestHttpError(http.StatusConflict)), ExitCodeConflict},
+		{newTestHttpError(http.StatusBadGateway), ExitCodeServer},
+		{newTestHttpError(http.StatusTooManyRequests), ExitCodeError},
+		{fmt.Errorf("request: %w", context.DeadlineExceeded), ExitCodeTimeout},
+		{core.FailedTerminationError{Message: "max retries"}, ExitCodeWaitTermination},
+		{reportedError{Err: newTestHttpError(http.StatusNotFound)}, ExitCodeNotFound},
+		{core.ProgramExitError{Program: "ssh", Code: 255}, 255},
+	}
+
+	for _, tc := range tests {
+		if got := ExitCode(tc.err); got != tc.expected {
+			t.Errorf("ExitCode(%v): expected %d, got %d", tc.err, tc.expected, got)
+		}
+	}
+}
+
+func TestNewErrorDocument(t *testing.T) {
+	doc := newErrorDocument(fmt.Errorf("wrapped: %w", newTestHttpError(http.StatusNotFound)))
+	expected := errorInfo{
+		Kind:      "not_found",
+		ExitCode:  ExitCodeNotFound,
+		Message:   "some message",
+		Status:    http.StatusNotFound,
+		Slug:      "some-slug",
+		RequestID: "request-id",
+		TraceID:   "trace-id",
+	}
+	if !reflect.DeepEqual(doc.Error, expected) {
+		t.Errorf("expected %#v, got %#v", expected, doc.Error)
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("name", "", "")
+	flags.String("size", "", "")
+	usageErr := core.UsageError{Err: utils.MultiError{
+		&flagError{Flag: flags.Lookup("size"), Err: errors.New("must be a number")},
+		requiredFlagsError{flags.Lookup("name")},
+	}}
+
+	doc = newErrorDocument(usageErr)
+	expectedDetails := []errorDetail{
+		{Flag: "--size", Message: "must be a number"},
+		{Flag: "--name", Message: "missing required flag"},
+	}
+	if doc.Error.Kind != "usage" || !reflect.DeepEqual(doc.Error.Details, expectedDetails) {
+		t.Errorf("expected usage with %#v, got %#v", expectedDetails, doc.Error)
+	}
+}
+
+func TestFlagsAndArgsExitCode(t *testing.T) {
+	type parameters struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	schema, err := mgcSchemaPkg.SchemaFromType[parameters]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"--unknown", "x"}},
+		{"invalid flag value", []string{"--count", "abc"}},
+		{"too many positional arguments", []string{"a", "b"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			flags, err := newCmdFlags(&cobra.Command{}, schema, &mgcSchemaPkg.Schema{}, []string{"name"}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootCmd := &cobra.Command{Use: "mgc", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.SetFlagErrorFunc(flagUsageError)
+			cmd := &cobra.Command{
+				Use:  "testing",
+				Args: flags.positionalArgsFunction,
+				RunE: func(cmd *cobra.Command, args []string) error {
+					_, _, err := flags.getValues(nil, args)
+					return err
+				},
+			}
+			flags.addFlags(cmd)
+			rootCmd.AddCommand(cmd)
+
+			rootCmd.SetArgs(append([]string{"testing"}, tc.args...))
+			err = rootCmd.Execute()
+			if got := ExitCode(err); got != ExitCodeUsage {
+				t.Errorf("expected exit code %d, got %d (%v)", ExitCodeUsage, got, err)
+			}
+		})
+	}
+}
+
+func TestShowHelpForStructuredError(t *testing.T) {
+	tests := []struct {
+		output       string
+		expectsUsage bool
+	}{
+		{"", true},
+		{"table", true},
+		{"json", false},
+		{"json=compact", false},
+		{"yaml", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.output, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "mgc"}
+			rootCmd.AddCommand(&cobra.Command{Use: "testing"})
+			addOutputFlag(rootCmd)
+			setOutputFlag(rootCmd, tc.output)
+
+			var stderr bytes.Buffer
+			rootCmd.SetErr(&stderr)
+			err := core.UsageError{Err: errors.New("some usage error")}
+			if got := showHelpForError(rootCmd, []string{"testing"}, err); got != err {
+				t.Errorf("expected the error to be kept, got %v", got)
+			}
+			if shown := stderr.Len() > 0; shown != tc.expectsUsage {
+				t.Errorf("expected usage shown %v, got %q", tc.expectsUsage, stderr.String())
+			}
+		})
+	}
+}
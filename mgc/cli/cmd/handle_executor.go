@@ -5,14 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/MagaluCloud/magalu/mgc/cli/ui"
-	"github.com/MagaluCloud/magalu/mgc/cli/ui/progress_bar"
 	"github.com/MagaluCloud/magalu/mgc/core"
 	"github.com/MagaluCloud/magalu/mgc/core/auth"
 	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
 	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
 	"github.com/MagaluCloud/magalu/mgc/sdk/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/cobra"
 )
 
@@ -36,7 +38,7 @@ func checkScopes(sdk *mgcSdk.Sdk, exec core.Executor) error {
 
 	currentScopes, err := a.CurrentScopes()
 	if err != nil {
-		return fmt.Errorf("unable to get current scopes: %w", err)
+		return authError{Err: fmt.Errorf("unable to get current scopes: %w", err)}
 	}
 
 	var missing core.Scopes
@@ -52,16 +54,42 @@ func checkScopes(sdk *mgcSdk.Sdk, exec core.Executor) error {
 	}
 
 	if k, s := a.AccessKeyPair(); (k == "" || s == "") && len(missing) > 0 {
-		return fmt.Errorf("you are not logged in. To authenticate, please run 'mgc auth login'")
+		return authError{Err: fmt.Errorf("you are not logged in. To authenticate, please run 'mgc auth login'")}
 	}
 
 	if len(missing) > 0 {
-		return fmt.Errorf("you are missing the following scopes for this operation: %v", missing)
+		return authError{Err: fmt.Errorf("you are missing the following scopes for this operation: %v", missing)}
 	}
 
 	return nil
 }
 
+// Such as "virtual-machine instances list". Helpers may run other executors than the
+// command's own, such as the list used to select a value, then its name is appended
+func executorCommandPath(cmd *cobra.Command, exec core.Executor) string {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if name, _ := getCommandNameAndAliases(exec.Name()); name != cmd.Name() {
+		path += " " + name
+	}
+	return path
+}
+
+func confirmPrompt(msg string) error {
+	if msg == "" {
+		return nil
+	}
+
+	run, err := ui.Confirm(msg)
+	if err != nil {
+		return err
+	}
+
+	if !run {
+		return core.UserDeniedConfirmationError{Prompt: msg}
+	}
+	return nil
+}
+
 func handleExecutorPre(
 	ctx context.Context,
 	sdk *mgcSdk.Sdk,
@@ -88,15 +116,17 @@ func handleExecutorPre(
 
 	if cExec, ok := core.ExecutorAs[core.ConfirmableExecutor](exec); ok && !getBypassConfirmationFlag(cmd) {
 		msg := cExec.ConfirmPrompt(parameters, configs)
-		if msg != "" {
-			run, err := ui.Confirm(msg)
-			if err != nil {
-				return nil, err
-			}
-
-			if !run {
-				return nil, core.UserDeniedConfirmationError{Prompt: msg}
-			}
+		if err := confirmPrompt(msg); err != nil {
+			return nil, err
+		}
+	}
+	if cExec, ok := core.ExecutorAs[core.ContextConfirmableExecutor](exec); ok && !getBypassConfirmationFlag(cmd) {
+		msg, err := cExec.ConfirmPromptWithContext(ctx, parameters, configs)
+		if err != nil {
+			return nil, err
+		}
+		if err := confirmPrompt(msg); err != nil {
+			return nil, err
 		}
 	}
 	if pExec, ok := core.ExecutorAs[core.PromptInputExecutor](exec); ok && !getBypassConfirmationFlag(cmd) {
@@ -136,7 +166,9 @@ func handleExecutorPre(
 		return nil, err
 	}
 
+	ctx, span := telemetry.StartExecutorSpan(ctx, executorCommandPath(cmd, exec), parameters, (*openapi3.Schema)(exec.ParametersSchema()))
 	result, err := retry.Run(ctx, cb)
+	telemetry.EndSpan(span, err)
 
 	if pb != nil {
 		pb.Flush()
@@ -145,23 +177,18 @@ func handleExecutorPre(
 	return result, err
 }
 
-func handleExecutor(
-	ctx context.Context,
-	sdk *mgcSdk.Sdk,
-	cmd *cobra.Command,
-	exec core.Executor,
-	parameters core.Parameters,
-	configs core.Configs,
-) (core.Result, error) {
+// Common to every execution, such as logger, progress and authentication setup.
+// The returned function must be called once the execution is done
+func prepareExecution(ctx context.Context, sdk *mgcSdk.Sdk, cmd *cobra.Command) (context.Context, func(), error) {
 	ctx = openapi.WithRawOutputFlag(ctx, getRawOutputFlag(cmd))
 
 	err := cmd.ParseFlags(argParser.MainArgs())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err = initLogger(sdk, getLogFilterFlag(cmd)); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if !getRawOutputFlag(cmd) {
@@ -173,16 +200,32 @@ func handleExecutor(
 			CheckVersion(sdk.GetVersion(), argParser.MainArgs()...)
 	}
 
-	if !getRawOutputFlag(cmd) {
-		pb = progress_bar.New()
-		go pb.Render()
-		defer pb.Finalize()
+	ctx, finishProgress, err := setupProgressReport(ctx, cmd)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	setDefaultRegion(sdk)
 	setApiKey(cmd, sdk)
 	setKeyPair(sdk)
 
+	return ctx, finishProgress, nil
+}
+
+func handleExecutor(
+	ctx context.Context,
+	sdk *mgcSdk.Sdk,
+	cmd *cobra.Command,
+	exec core.Executor,
+	parameters core.Parameters,
+	configs core.Configs,
+) (core.Result, error) {
+	ctx, finish, err := prepareExecution(ctx, sdk, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
 	result, err := handleExecutorPre(ctx, sdk, cmd, exec, parameters, configs)
 	err = handleExecutorResult(ctx, sdk, cmd, result, err)
 	if err != nil {
@@ -0,0 +1,581 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/cli/cmd/schema_flags"
+	"github.com/MagaluCloud/magalu/mgc/cli/ui"
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+const interactiveFlag = "cli.interactive"
+
+func addInteractiveFlag(cmd *cobra.Command) {
+	cmd.Root().PersistentFlags().Bool(
+		interactiveFlag,
+		false,
+		`Interactively prompt for every missing required parameter. Enums and values that can be listed
+are offered as a selection. At the end, the equivalent non-interactive command line is printed`,
+	)
+}
+
+func getInteractiveFlag(cmd *cobra.Command) bool {
+	v, err := cmd.Root().PersistentFlags().GetBool(interactiveFlag)
+	if err != nil {
+		return false
+	}
+	return v
+}
+
+// used as SelectionChoice.Value to let the user type a value not in the list
+type interactiveTypeValue struct{}
+
+type interactivePrompter struct {
+	sdk           *mgcSdk.Sdk
+	cmd           *cobra.Command
+	flags         *cmdFlags
+	listExecutors []core.Executor // siblings of the executor, used to offer existing values
+}
+
+func newInteractivePrompter(sdk *mgcSdk.Sdk, cmd *cobra.Command, flags *cmdFlags) *interactivePrompter {
+	p := &interactivePrompter{sdk: sdk, cmd: cmd, flags: flags}
+
+	grouper, err := findCommandGrouper(sdk, cmd.Parent())
+	if err != nil {
+		logger().Debugw("could not find command grouper, list selections are disabled", "cmd", cmd.CommandPath(), "error", err)
+		return p
+	}
+
+	_, _ = grouper.VisitChildren(func(child core.Descriptor) (bool, error) {
+		if exec, ok := child.(core.Executor); ok && strings.HasPrefix(exec.Name(), listExecNamePrefix) {
+			p.listExecutors = append(p.listExecutors, exec)
+		}
+		return true, nil
+	})
+
+	return p
+}
+
+// Walk the SDK tree using the command path, the root command maps to the SDK root group
+func findCommandGrouper(sdk *mgcSdk.Sdk, cmd *cobra.Command) (grouper core.Grouper, err error) {
+	var names []string
+	for c := cmd; c != nil && c.HasParent(); c = c.Parent() {
+		names = append(names, c.Name())
+	}
+	slices.Reverse(names)
+
+	grouper = sdk.Group()
+	for _, name := range names {
+		var child core.Descriptor
+		child, err = findChildByNameOrAliases(grouper, name)
+		if err != nil {
+			return
+		}
+
+		var ok bool
+		if grouper, ok = child.(core.Grouper); !ok {
+			err = fmt.Errorf("command %q is not a group", name)
+			return
+		}
+	}
+
+	return
+}
+
+// Prompt for every required flag that has no value, then set the flag with the user input.
+//
+// The returned bool is true if any flag was prompted.
+func (p *interactivePrompter) promptMissingRequired() (prompted bool, err error) {
+	config := p.sdk.Config()
+
+	for _, f := range p.flags.sortedSchemaFlags() {
+		if _, err := p.flags.getFlagValue(f, config); err != schema_flags.ErrRequiredFlag {
+			continue
+		}
+
+		desc := f.Value.(schema_flags.SchemaFlagValue).Desc()
+
+		var rawValue string
+		rawValue, err = p.promptFlagRawValue(desc)
+		if err != nil {
+			return
+		}
+
+		if err = f.Value.Set(rawValue); err != nil {
+			err = &flagError{Flag: f, Err: err}
+			return
+		}
+		prompted = true
+	}
+
+	return
+}
+
+func (p *interactivePrompter) promptFlagRawValue(desc schema_flags.SchemaFlagValueDesc) (rawValue string, err error) {
+	if !isEnumSchema(desc.Schema) && !isObjectWithPropertiesSchema(desc.Schema) {
+		var ok bool
+		rawValue, ok, err = p.selectFromList(desc)
+		if err != nil || ok {
+			return
+		}
+	}
+
+	value, _, err := promptSchemaValue(desc, true)
+	if err != nil {
+		return
+	}
+
+	return formatRawFlagValue(value)
+}
+
+func (p *interactivePrompter) selectFromList(desc schema_flags.SchemaFlagValueDesc) (rawValue string, ok bool, err error) {
+	listExec, itemSchema := p.findListExecutorForProp(desc)
+	if listExec == nil {
+		return
+	}
+
+	items, err := p.listItems(listExec)
+	if err != nil {
+		logger().Debugw("list for interactive selection failed, ask for input", "list", listExec.Name(), "error", err)
+		err = nil
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	var humanFields []string
+	if humanResultExec, ok := core.ExecutorAs[core.HumanIdentifiableFieldsExecutor](listExec); ok {
+		humanFields = humanResultExec.HumanIdentifiableFields()
+	}
+
+	choices := make([]*ui.SelectionChoice, 0, len(items)+1)
+	for _, item := range items {
+		m, isMap := item.(map[string]any)
+		if !isMap {
+			continue
+		}
+		value, hasValue := m[desc.PropName]
+		if !hasValue || value == nil {
+			continue
+		}
+		choices = append(choices, &ui.SelectionChoice{Value: value, Label: getSelectLabel(item, humanFields)})
+	}
+	if len(choices) == 0 {
+		return
+	}
+	choices = append(choices, &ui.SelectionChoice{Value: interactiveTypeValue{}, Label: "Type another value..."})
+
+	logger().Debugw("interactive selection from list", "prop", desc.PropName, "list", listExec.Name(), "itemSchema", itemSchema)
+
+	value, err := ui.SelectionPrompt[any](
+		fmt.Sprintf("Select %s (from %q):", promptLabel(desc), listExec.Name()),
+		choices,
+	)
+	if err != nil {
+		return
+	}
+	if _, isTypeValue := value.(interactiveTypeValue); isTypeValue {
+		return
+	}
+
+	rawValue, err = formatRawFlagValue(value)
+	if err != nil {
+		return
+	}
+	if _, err = schema_flags.ParseRawValue(desc, rawValue); err != nil {
+		return
+	}
+
+	ok = true
+	return
+}
+
+// The list executor must not require any parameters and it must return
+// items with a property matching the flag's name and schema type
+func (p *interactivePrompter) findListExecutorForProp(desc schema_flags.SchemaFlagValueDesc) (listExec core.Executor, itemSchema *mgcSchemaPkg.Schema) {
+	for _, exec := range p.listExecutors {
+		if len(exec.ParametersSchema().Required) > 0 {
+			continue
+		}
+
+		itemSchema, err := findListItemSchema(exec.ResultSchema())
+		if err != nil {
+			continue
+		}
+
+		propRef := itemSchema.Properties[desc.PropName]
+		if propRef == nil || propRef.Value == nil {
+			continue
+		}
+
+		if mgcSchemaPkg.CheckSimilarJsonSchemas(desc.Schema, (*mgcSchemaPkg.Schema)(propRef.Value)) {
+			return exec, itemSchema
+		}
+	}
+
+	return nil, nil
+}
+
+func (p *interactivePrompter) listItems(listExec core.Executor) (items []any, err error) {
+	config := p.sdk.Config()
+	parameters := core.Parameters{}
+	configs := core.Configs{}
+
+	for _, f := range p.flags.schemaFlags {
+		desc := f.Value.(schema_flags.SchemaFlagValue).Desc()
+		if !desc.IsConfig || listExec.ConfigsSchema().Properties[desc.PropName] == nil {
+			continue
+		}
+		if value, err := schema_flags.GetFlagValue(f, config); err == nil {
+			configs[desc.PropName] = value
+		}
+	}
+
+	setDefaultRegion(p.sdk)
+	setApiKey(p.cmd, p.sdk)
+
+	ctx := p.sdk.NewContext()
+	result, err := handleExecutorPre(ctx, p.sdk, p.cmd, listExec, parameters, configs)
+	if err != nil {
+		return
+	}
+
+	resultWithValue, ok := core.ResultAs[core.ResultWithValue](result)
+	if !ok {
+		err = fmt.Errorf("list returned no value")
+		return
+	}
+
+	return findListItems(resultWithValue.Value())
+}
+
+// Like findListSchema(), but objects may contain other non-array properties, such as "meta"
+func findListItemSchema(schema *mgcSchemaPkg.Schema) (itemSchema *mgcSchemaPkg.Schema, err error) {
+	if schema == nil {
+		err = fmt.Errorf("missing schema")
+		return
+	}
+
+	if schema.Items != nil {
+		itemSchema = (*mgcSchemaPkg.Schema)(schema.Items.Value)
+		return
+	}
+
+	for _, propRef := range schema.Properties {
+		if propRef.Value == nil || propRef.Value.Items == nil {
+			continue
+		}
+		if itemSchema != nil {
+			err = fmt.Errorf("list result schema has multiple arrays")
+			return
+		}
+		itemSchema = (*mgcSchemaPkg.Schema)(propRef.Value.Items.Value)
+	}
+
+	if itemSchema == nil {
+		err = fmt.Errorf("unable to find resource schema from list result schema")
+	}
+	return
+}
+
+func findListItems(value any) (items []any, err error) {
+	switch v := value.(type) {
+	case []any:
+		return v, nil
+
+	case map[string]any:
+		for _, propValue := range v {
+			if array, ok := propValue.([]any); ok {
+				if items != nil {
+					return nil, fmt.Errorf("list result has multiple arrays")
+				}
+				items = array
+			}
+		}
+		if items != nil {
+			return
+		}
+	}
+
+	return nil, fmt.Errorf("list expected to return array, got %T instead: %#v", value, value)
+}
+
+func isEnumSchema(schema *mgcSchemaPkg.Schema) bool {
+	return len(schema.Enum) > 0
+}
+
+func isObjectWithPropertiesSchema(schema *mgcSchemaPkg.Schema) bool {
+	return schema.Type != nil && schema.Type.Includes(openapi3.TypeObject) && len(schema.Properties) > 0
+}
+
+func isBooleanSchema(schema *mgcSchemaPkg.Schema) bool {
+	return schema.Type != nil && schema.Type.Is(openapi3.TypeBoolean)
+}
+
+func promptLabel(desc schema_flags.SchemaFlagValueDesc) string {
+	label := "--" + string(desc.FlagName)
+	if description := desc.Description(); description != "" {
+		label += " (" + description + ")"
+	}
+	return label
+}
+
+// Prompt the user for a value matching desc.Schema. If the value is optional, the user may skip it.
+func promptSchemaValue(desc schema_flags.SchemaFlagValueDesc, isRequired bool) (value any, ok bool, err error) {
+	switch {
+	case isEnumSchema(desc.Schema):
+		return promptEnumValue(desc, isRequired)
+	case isBooleanSchema(desc.Schema):
+		return promptBooleanValue(desc, isRequired)
+	case isObjectWithPropertiesSchema(desc.Schema):
+		return promptObjectValue(desc, isRequired)
+	default:
+		return promptTextValue(desc, isRequired)
+	}
+}
+
+func appendSkipChoice(choices []*ui.SelectionChoice, isRequired bool) []*ui.SelectionChoice {
+	if isRequired {
+		return choices
+	}
+	return append(choices, &ui.SelectionChoice{Value: interactiveTypeValue{}, Label: "(skip)"})
+}
+
+func promptEnumValue(desc schema_flags.SchemaFlagValueDesc, isRequired bool) (value any, ok bool, err error) {
+	choices := make([]*ui.SelectionChoice, 0, len(desc.Schema.Enum)+1)
+	for _, v := range desc.Schema.Enum {
+		choices = append(choices, &ui.SelectionChoice{
+			Value:      v,
+			IsSelected: utils.IsSameValueOrPointer(v, desc.Schema.Default),
+		})
+	}
+	choices = appendSkipChoice(choices, isRequired)
+
+	value, err = ui.SelectionPrompt[any](fmt.Sprintf("Select %s:", promptLabel(desc)), choices)
+	if err != nil {
+		return
+	}
+	if _, skipped := value.(interactiveTypeValue); skipped {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+func promptBooleanValue(desc schema_flags.SchemaFlagValueDesc, isRequired bool) (value any, ok bool, err error) {
+	choices := []*ui.SelectionChoice{
+		{Value: true, IsSelected: desc.Schema.Default == true},
+		{Value: false, IsSelected: desc.Schema.Default == false},
+	}
+	choices = appendSkipChoice(choices, isRequired)
+
+	value, err = ui.SelectionPrompt[any](fmt.Sprintf("Select %s:", promptLabel(desc)), choices)
+	if err != nil {
+		return
+	}
+	if _, skipped := value.(interactiveTypeValue); skipped {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+func promptObjectValue(desc schema_flags.SchemaFlagValueDesc, isRequired bool) (value any, ok bool, err error) {
+	propNames := make([]string, 0, len(desc.Schema.Properties))
+	for propName := range desc.Schema.Properties {
+		propNames = append(propNames, propName)
+	}
+	// required properties first, then alphabetically
+	slices.SortFunc(propNames, func(a, b string) int {
+		aRequired, bRequired := slices.Contains(desc.Schema.Required, a), slices.Contains(desc.Schema.Required, b)
+		if aRequired != bRequired {
+			if aRequired {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	for {
+		fmt.Fprintf(os.Stderr, "Enter the properties of %s\n", promptLabel(desc))
+
+		obj := map[string]any{}
+		for _, propName := range propNames {
+			propDesc := schema_flags.SchemaFlagValueDesc{
+				Container: desc.Schema,
+				Schema:    (*mgcSchemaPkg.Schema)(desc.Schema.Properties[propName].Value),
+				PropName:  propName,
+				FlagName:  desc.FlagName + flag.NormalizedName(childFlagSeparator) + flag.NormalizedName(propName),
+				IsConfig:  desc.IsConfig,
+			}
+
+			var propValue any
+			var propOk bool
+			propValue, propOk, err = promptSchemaValue(propDesc, slices.Contains(desc.Schema.Required, propName))
+			if err != nil {
+				return
+			}
+			if propOk {
+				obj[propName] = propValue
+			}
+		}
+
+		if len(obj) == 0 && !isRequired {
+			return nil, false, nil
+		}
+
+		validateErr := desc.Schema.VisitJSON(obj, openapi3.MultiErrors())
+		if validateErr == nil {
+			return obj, true, nil
+		}
+
+		fmt.Fprintf(os.Stderr, "Invalid value for %s: %s\n", promptLabel(desc), validateErr.Error())
+	}
+}
+
+func promptTextValue(desc schema_flags.SchemaFlagValueDesc, isRequired bool) (value any, ok bool, err error) {
+	validate := func(input string) error {
+		if input == "" {
+			if isRequired {
+				return errors.New("value is required")
+			}
+			return nil
+		}
+		_, err := schema_flags.ParseRawValue(desc, input)
+		return err
+	}
+
+	message := fmt.Sprintf("Enter %s:", promptLabel(desc))
+	if !isRequired {
+		message = fmt.Sprintf("Enter %s (leave empty to skip):", promptLabel(desc))
+	}
+
+	input, err := ui.RunTextInputPrompt(message, ui.TextInputOptions{
+		InitialValue: desc.RawDefaultValue(),
+		Placeholder:  desc.FlagType(),
+		Hidden:       desc.Schema.Format == "password",
+		Validate:     validate,
+	})
+	if err != nil || input == "" {
+		return
+	}
+
+	value, err = schema_flags.ParseRawValue(desc, input)
+	ok = err == nil
+	return
+}
+
+// Strings are given verbatim, unless they would be interpreted by the flag parsers
+// (JSON, file loading or help); everything else is given as JSON
+func formatRawFlagValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		if s == schema_flags.ValueHelpIsRequired ||
+			strings.HasPrefix(s, schema_flags.ValueLoadJSONFromFilePrefix) ||
+			strings.HasPrefix(s, schema_flags.ValueLoadVerbatimFromFilePrefix) ||
+			strings.HasPrefix(s, schema_flags.ValueVerbatimStringPrefix) ||
+			strings.HasPrefix(s, `"`) {
+			return schema_flags.ValueVerbatimStringPrefix + s, nil
+		}
+		return s, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Positional arguments first (in order), then the remaining flags sorted by name
+func (cf *cmdFlags) sortedSchemaFlags() []*flag.Flag {
+	sorted := slices.Clone(cf.schemaFlags)
+	slices.SortFunc(sorted, func(a, b *flag.Flag) int {
+		aPos, bPos := slices.Index(cf.positionalArgs, a), slices.Index(cf.positionalArgs, b)
+		switch {
+		case aPos >= 0 && bPos >= 0:
+			return aPos - bPos
+		case aPos >= 0:
+			return -1
+		case bPos >= 0:
+			return 1
+		default:
+			return strings.Compare(a.Name, b.Name)
+		}
+	})
+	return sorted
+}
+
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	isSafe := strings.IndexFunc(s, func(r rune) bool {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return false
+		case strings.ContainsRune("-_.,:/=@%+", r):
+			return false
+		default:
+			return true
+		}
+	}) < 0
+	if isSafe {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Build the command line that would result in the current flag values, without any prompts.
+//
+// Positional arguments are given as flags, as they were applied to the flags already.
+func (cf *cmdFlags) formatCommandLine(cmd *cobra.Command) string {
+	parts := strings.Fields(cmd.CommandPath())
+
+	cmd.Flags().VisitAll(func(f *flag.Flag) {
+		if f.Name == interactiveFlag || slices.Contains(cf.childFlags, f) {
+			return
+		}
+
+		if fv, ok := f.Value.(schema_flags.SchemaFlagValue); ok {
+			if !fv.Changed() {
+				return
+			}
+		} else if !f.Changed {
+			return
+		}
+
+		if f.Value.Type() == "bool" && f.Value.String() == "true" {
+			parts = append(parts, "--"+f.Name)
+			return
+		}
+		parts = append(parts, "--"+f.Name+"="+shellQuote(f.Value.String()))
+	})
+
+	return strings.Join(parts, " ")
+}
+
+func (cf *cmdFlags) promptMissingRequired(sdk *mgcSdk.Sdk, cmd *cobra.Command) error {
+	prompted, err := newInteractivePrompter(sdk, cmd, cf).promptMissingRequired()
+	if err != nil {
+		return err
+	}
+
+	if prompted {
+		cmd.PrintErrf("Equivalent command line:\n  %s\n\n", cf.formatCommandLine(cmd))
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/spf13/cobra"
+)
+
+func Test_shellQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "''"},
+		{"simple", "simple"},
+		{"br-se1", "br-se1"},
+		{"a b", "'a b'"},
+		{`{"name":"x"}`, `'{"name":"x"}'`},
+		{"it's", `'it'\''s'`},
+	}
+	for _, tc := range tests {
+		checkExpectedString(t, tc.input, tc.expected, shellQuote(tc.input))
+	}
+}
+
+func Test_formatRawFlagValue(t *testing.T) {
+	tests := []struct {
+		input    any
+		expected string
+	}{
+		{"value", "value"},
+		{"help", "#help"},
+		{"@file", "#@file"},
+		{`"quoted"`, `#"quoted"`},
+		{float64(10), "10"},
+		{true, "true"},
+		{map[string]any{"name": "x"}, `{"name":"x"}`},
+	}
+	for _, tc := range tests {
+		got, err := formatRawFlagValue(tc.input)
+		checkError(t, tc.expected, nil, err)
+		checkExpectedString(t, tc.expected, tc.expected, got)
+	}
+}
+
+func Test_findListItems(t *testing.T) {
+	items, err := findListItems([]any{"a"})
+	checkError(t, "array", nil, err)
+	if len(items) != 1 {
+		t.Errorf("array: expected 1 item, got %v", items)
+	}
+
+	items, err = findListItems(map[string]any{"meta": map[string]any{}, "instances": []any{"a", "b"}})
+	checkError(t, "object", nil, err)
+	if len(items) != 2 {
+		t.Errorf("object: expected 2 items, got %v", items)
+	}
+
+	_, err = findListItems(map[string]any{"a": []any{}, "b": []any{}})
+	if err == nil {
+		t.Errorf("multiple arrays: expected error")
+	}
+}
+
+func Test_cmdFlags_formatCommandLine(t *testing.T) {
+	type parameters struct {
+		Id    string         `json:"id"`
+		Name  string         `json:"name"`
+		Image map[string]any `json:"image"`
+	}
+
+	schema, err := mgcSchemaPkg.SchemaFromType[parameters]()
+	checkError(t, "SchemaFromType", nil, err)
+
+	root := &cobra.Command{Use: "mgc"}
+	addInteractiveFlag(root)
+	parent := &cobra.Command{Use: "group"}
+	root.AddCommand(parent)
+
+	flags, err := newCmdFlags(parent, schema, &mgcSchemaPkg.Schema{}, []string{"id"}, nil)
+	checkError(t, "newCmdFlags", nil, err)
+
+	cmd := &cobra.Command{Use: "testing"}
+	parent.AddCommand(cmd)
+	flags.addFlags(cmd)
+
+	err = cmd.ParseFlags([]string{"--cli.interactive", "--name=my vm"})
+	checkError(t, "ParseFlags", nil, err)
+	err = flags.knownFlags["id"].Value.Set("123")
+	checkError(t, "Set id", nil, err)
+
+	checkExpectedString(t, "formatCommandLine", "mgc group testing --id=123 --name='my vm'", flags.formatCommandLine(cmd))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"slices"
+	"strings"
 
 	"github.com/MagaluCloud/magalu/mgc/core"
 	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
@@ -275,6 +276,9 @@ func addAction(
 	name, aliases := getCommandNameAndAliases(exec.Name())
 	cmdPath := fmt.Sprintf("%s %s", parentCmd.CommandPath(), name)
 
+	// without the program name
+	flags.loadWorkspaceDefaults(sdk.Config(), strings.Fields(cmdPath)[1:])
+
 	links := newCmdLinks(sdk, exec.Links(), cmdPath)
 	if links != nil {
 		flags.addExtraFlag(links.listLinksFlag)
@@ -302,6 +306,12 @@ func addAction(
 				return err
 			}
 
+			if getInteractiveFlag(cmd) {
+				if err := flags.promptMissingRequired(sdk, cmd); err != nil {
+					return err
+				}
+			}
+
 			config := sdk.Config()
 			parameters, configs, err := flags.getValues(config, args)
 			if err != nil {
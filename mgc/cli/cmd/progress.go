@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/MagaluCloud/magalu/mgc/cli/ui/progress_bar"
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
+	"github.com/spf13/cobra"
+)
+
+const (
+	progressFlag = "cli.progress"
+
+	progressModeBar  = "bar"
+	progressModeJSON = "json"
+	progressModeNone = "none"
+)
+
+var progressModes = []string{progressModeBar, progressModeJSON, progressModeNone}
+
+func addProgressFlag(cmd *cobra.Command) {
+	cmd.Root().PersistentFlags().String(
+		progressFlag,
+		progressModeBar,
+		fmt.Sprintf(`How to report the progress of transfers and waits. One of: %v.
+"json" writes one event per line to stderr: start, progress (done, total and rate), item_done, item_failed
+and finish. It's also used with --raw, which disables the "bar"`, progressModes),
+	)
+}
+
+func getProgressFlag(cmd *cobra.Command) (string, error) {
+	mode, err := cmd.Root().PersistentFlags().GetString(progressFlag)
+	if err != nil || mode == "" {
+		return progressModeBar, nil
+	}
+	if !slices.Contains(progressModes, mode) {
+		return "", core.UsageError{Err: fmt.Errorf("invalid --%s %q, expected one of %v", progressFlag, mode, progressModes)}
+	}
+	return mode, nil
+}
+
+// Sets up the progress reporting given by the flags. The returned function must be called when the execution is done
+func setupProgressReport(ctx context.Context, cmd *cobra.Command) (context.Context, func(), error) {
+	mode, err := getProgressFlag(cmd)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+
+	switch {
+	case mode == progressModeJSON:
+		jsonReporter := progress_report.NewJSONReporter(os.Stderr)
+		ctx = progress_report.NewContext(ctx, jsonReporter.ReportProgress)
+		ctx = progress_report.NewItemContext(ctx, jsonReporter.ReportItem)
+	case mode == progressModeBar && !getRawOutputFlag(cmd):
+		pb = progress_bar.New()
+		go pb.Render()
+		return ctx, pb.Finalize, nil
+	}
+
+	return ctx, func() {}, nil
+}
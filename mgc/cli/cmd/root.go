@@ -61,6 +61,7 @@ It allows you to interact with the Magalu Cloud to manage your resources.
 		},
 	}
 	rootCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+	rootCmd.SetFlagErrorFunc(flagUsageError)
 
 	rootCmd.AddGroup(&cobra.Group{
 		ID:    "catalog",
@@ -84,6 +85,8 @@ It allows you to interact with the Magalu Cloud to manage your resources.
 	addWaitTerminationFlag(rootCmd)
 	addRetryUntilFlag(rootCmd)
 	addBypassConfirmationFlag(rootCmd)
+	addInteractiveFlag(rootCmd)
+	addProgressFlag(rootCmd)
 	addShowInternalFlag(rootCmd)
 	addShowHiddenFlag(rootCmd)
 	addRawOutputFlag(rootCmd)
@@ -99,9 +102,25 @@ It allows you to interact with the Magalu Cloud to manage your resources.
 		return err
 	}
 
+	for _, err := range sdk.Config().FileLayerErrors() {
+		logger().Warnw("ignored config file", "error", err)
+	}
+
+	shutdownTelemetry := setupTelemetry(sdk)
+	defer shutdownTelemetry()
+
 	rootCmd.AddCommand(newDumpTreeCmd(sdk))
+	rootCmd.AddCommand(newDocsCmd(sdk))
+	rootCmd.AddCommand(newDockerCredentialCmd(sdk))
+	rootCmd.AddCommand(newSpecsCmd(sdk))
 
 	mainArgs := argParser.MainArgs()
+	if isDockerCredentialHelperProgram(argParser.FullProgramPath()) {
+		mainArgs = append([]string{dockerCredentialCmdName}, mainArgs...)
+	}
+
+	userAliases := addUserCommands(sdk, rootCmd)
+	mainArgs = expandUserAlias(userAliases, mainArgs)
 
 	loadErr := loadSdkCommandTree(sdk, rootCmd, mainArgs)
 	if loadErr != nil {
@@ -119,6 +138,7 @@ It allows you to interact with the Magalu Cloud to manage your resources.
 	}
 	// looking for flags like raw, debug, api-key, etc... ? see: mgc/cli/cmd/handle_executor.go - tip: there is a nice point to put an breakpoint
 	err = showHelpForError(rootCmd, mainArgs, err) // since we SilenceUsage and SilenceErrors
+	err = reportStructuredError(rootCmd, err)
 	return err
 }
 
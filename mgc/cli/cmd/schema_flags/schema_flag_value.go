@@ -136,8 +136,11 @@ func GetFlagValue(f *flag.Flag, cfg *config.Config) (value any, err error) {
 	if desc.Schema.OneOf != nil {
 		for _, oneOf := range desc.Schema.OneOf {
 			typeOfValue := reflect.TypeOf(value).String()
-			if typeOfValue == "float64" {
+			switch typeOfValue {
+			case "float64":
 				typeOfValue = "integer"
+			case "map[string]interface {}":
+				typeOfValue = "object"
 			}
 			if typeOfValue == oneOf.Value.Type.Slice()[0] {
 				desc.Schema.Type = oneOf.Value.Type
@@ -150,3 +153,24 @@ func GetFlagValue(f *flag.Flag, cfg *config.Config) (value any, err error) {
 
 	return
 }
+
+// Parse and validate rawValue as if it was given to a flag described by desc,
+// without changing any existing flag
+func ParseRawValue(desc SchemaFlagValueDesc, rawValue string) (value any, err error) {
+	fv := newSchemaFlagValue(desc)
+	if err = fv.Set(rawValue); err != nil {
+		return
+	}
+
+	if value, err = fv.Parse(); err != nil {
+		return
+	}
+
+	if value == nil && !desc.Schema.Nullable {
+		err = ErrRequiredFlag
+		return
+	}
+
+	err = desc.Schema.VisitJSON(value, openapi3.MultiErrors())
+	return
+}
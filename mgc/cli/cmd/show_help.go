@@ -54,7 +54,8 @@ func showHelpForError(cmd *cobra.Command, args []string, err error) error {
 	case err == schema_flags.ErrWantHelp:
 		return nil
 
-	case errors.As(err, new(core.UsageError)):
+	// structured errors must be alone in stderr to be parsed, their details tell what's wrong
+	case errors.As(err, new(core.UsageError)) && !isStructuredErrorOutput(cmd):
 		// we can't call UsageString() on the root, we need to find the actual leaf command that failed:
 		subCmd, _, _ := cmd.Find(args)
 		cmd.PrintErrln(subCmd.UsageString())
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+
+	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
+	"github.com/MagaluCloud/magalu/mgc/sdk/openapi"
+	"github.com/spf13/cobra"
+)
+
+func newSpecsCmd(sdk *mgcSdk.Sdk) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "specs",
+		Short: "Manage the OpenAPI specs the commands are generated from",
+		Long: `The commands of the products are generated from OpenAPI specs, loaded from layers,
+the last layer that has a spec wins:
+
+  - ` + openapi.LayerEmbedded + `: specs built into this program;
+  - ` + openapi.LayerRemote + `: specs downloaded from the module URL with 'specs refresh',
+    cached in $MGC_SDK_OPENAPI_CACHE_DIR or the user cache directory;
+  - ` + openapi.LayerOverride + `: specs in $MGC_SDK_OPENAPI_DIR or ./openapis, to add an spec the
+    index.openapi.yaml listing it must be provided as well.`,
+		GroupID: "other",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show which layer each spec is loaded from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := openapi.GetSpecsStatus(sdk.OpenApiLoader())
+			if err != nil {
+				return err
+			}
+			return formatSpecsOutput(cmd, status, "table=MODULE:$[*].module,VERSION:$[*].version,LAYER:$[*].layer,AVAILABLE:$[*].available,FETCHED:$[*].fetched_at,ERRORS:$[*].errors")
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "refresh [module...]",
+		Short: "Download the specs from the module URLs",
+		Long: `Download the specs of the given modules, or of all modules, from the URLs listed in the index.
+Cached specs are revalidated with their ETag and only downloaded again if they changed.
+Downloaded specs are verified with their checksum every time they are loaded.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			transportConfig, err := sdk.Config().TransportConfig()
+			if err != nil {
+				return err
+			}
+			transport, err := mgcHttpPkg.NewTransport(transportConfig)
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Transport: transport}
+			results, err := openapi.RefreshRemoteSpecs(context.Background(), sdk.OpenApiLoader(), client, args)
+			if len(results) > 0 {
+				if fmtErr := formatSpecsOutput(cmd, results, "table=MODULE:$[*].module,CHANGED:$[*].changed,ERROR:$[*].error"); fmtErr != nil {
+					return fmtErr
+				}
+			}
+			return err
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reset [module...]",
+		Short: "Remove the downloaded specs, so the embedded or override specs are used again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return openapi.ResetRemoteSpecs(sdk.OpenApiLoader(), args)
+		},
+	})
+
+	return cmd
+}
+
+func formatSpecsOutput(cmd *cobra.Command, value any, defaultOutput string) error {
+	output := getOutputFlag(cmd)
+	if output == "" {
+		output = defaultOutput
+	}
+	name, options := parseOutputFormatter(output)
+	formatter, err := getOutputFormatter(name, options)
+	if err != nil {
+		return err
+	}
+
+	simplified, err := utils.SimplifyAny(value)
+	if err != nil {
+		return err
+	}
+	return formatter.Format(simplified, options, getRawOutputFlag(cmd))
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
+	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
+)
+
+const telemetryShutdownTimeout = 5 * time.Second
+
+// The returned function exports the pending spans and metrics, it must be called before exiting
+func setupTelemetry(sdk *mgcSdk.Sdk) func() {
+	var cfg telemetry.Config
+	if err := sdk.Config().Get(telemetry.ConfigKey, &cfg); err != nil {
+		logger().Warnw("unable to get telemetry configuration", "error", err)
+		return func() {}
+	}
+
+	shutdown, err := telemetry.Setup(context.Background(), cfg, sdk.GetVersion())
+	if err != nil {
+		logger().Warnw("unable to setup telemetry", "error", err)
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logger().Debugw("unable to export telemetry", "error", err)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	mgcSdk "github.com/MagaluCloud/magalu/mgc/sdk"
+	"github.com/spf13/cobra"
+)
+
+// User defined aliases and macros, from the workspace config. See config.UserAliasesKey
+
+const userCommandsGroupID = "user"
+
+// Whether the name is already used by the SDK tree, its aliases or the CLI commands
+func isBuiltInCommandName(sdk *mgcSdk.Sdk, rootCmd *cobra.Command, name string) bool {
+	if isExistingCommand(rootCmd, name) || slices.Contains(builtInCommands, name) || slices.Contains(keepLoadingCommands, name) {
+		return true
+	}
+	_, err := findChildByNameOrAliases(sdk.Group(), name)
+	return err == nil
+}
+
+// Add the user aliases and macros to the root command, returning the aliases to be expanded
+// by expandUserAlias(). Those that would shadow built-in commands are ignored.
+func addUserCommands(sdk *mgcSdk.Sdk, rootCmd *cobra.Command) map[string]config.UserAlias {
+	aliases, err := sdk.Config().UserAliases()
+	if err != nil {
+		logger().Warnw("ignored user aliases", "error", err)
+	}
+	macros, err := sdk.Config().UserMacros()
+	if err != nil {
+		logger().Warnw("ignored user macros", "error", err)
+	}
+	if len(aliases) == 0 && len(macros) == 0 {
+		return nil
+	}
+
+	rootCmd.AddGroup(&cobra.Group{
+		ID:    userCommandsGroupID,
+		Title: "Aliases and macros:",
+	})
+
+	validAliases := make(map[string]config.UserAlias, len(aliases))
+	for _, pair := range utils.SortedMapIterator(aliases) {
+		name, alias := pair.Key, pair.Value
+		if isBuiltInCommandName(sdk, rootCmd, name) {
+			logger().Warnw("ignored user alias, it would shadow a built-in command", "name", name)
+			continue
+		}
+		rootCmd.AddCommand(newUserAliasCmd(name, alias))
+		validAliases[name] = alias
+	}
+
+	for _, pair := range utils.SortedMapIterator(macros) {
+		name, macro := pair.Key, pair.Value
+		if isBuiltInCommandName(sdk, rootCmd, name) {
+			logger().Warnw("ignored user macro, it would shadow a built-in or alias command", "name", name)
+			continue
+		}
+		rootCmd.AddCommand(newUserMacroCmd(sdk, name, macro))
+	}
+
+	return validAliases
+}
+
+// Values are given as-is if they are strings, otherwise as JSON, which is accepted by every flag
+func formatUserFlagValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// Flags sorted by name, so the resulting command line is always the same
+func userFlagsArgs(flags map[string]any) ([]string, error) {
+	args := make([]string, 0, len(flags))
+	for _, pair := range utils.SortedMapIterator(flags) {
+		value, err := formatUserFlagValue(pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of flag %q: %w", pair.Key, err)
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", pair.Key, value))
+	}
+	return args, nil
+}
+
+// As it would be typed, for the help
+func userCommandLine(command string, flags map[string]any) string {
+	line := "mgc " + command
+	for _, pair := range utils.SortedMapIterator(flags) {
+		value, err := formatUserFlagValue(pair.Value)
+		if err != nil {
+			continue
+		}
+		line += " --" + pair.Key + "=" + shellQuote(value)
+	}
+	return line
+}
+
+func userAliasArgs(alias config.UserAlias) ([]string, error) {
+	flagsArgs, err := userFlagsArgs(alias.Flags)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(strings.Fields(alias.Command), flagsArgs), nil
+}
+
+// Replace the alias, if it's the first argument, by its command and preset flags. The remaining
+// arguments are kept after them, so the given flags take precedence.
+//
+// Completion arguments are expanded as well, unless the alias is the word being completed.
+func expandUserAlias(aliases map[string]config.UserAlias, args []string) []string {
+	first := 0
+	if len(args) > 2 && args[0] == cobra.ShellCompRequestCmd {
+		first = 1
+	}
+	if len(args) <= first {
+		return args
+	}
+
+	name := args[first]
+	alias, ok := aliases[name]
+	if !ok {
+		return args
+	}
+
+	aliasArgs, err := userAliasArgs(alias)
+	if err != nil {
+		logger().Warnw("ignored user alias", "name", name, "error", err)
+		return args
+	}
+
+	logger().Debugw("expanded user alias", "name", name, "args", aliasArgs)
+	return slices.Concat(args[:first], aliasArgs, args[first+1:])
+}
+
+func newUserAliasCmd(name string, alias config.UserAlias) *cobra.Command {
+	expansion := userCommandLine(alias.Command, alias.Flags)
+
+	short := alias.Description
+	if short == "" {
+		short = "Alias for " + expansion
+	}
+
+	return &cobra.Command{
+		Use:                name,
+		Short:              short,
+		Long:               fmt.Sprintf("%s\n\nAlias for:\n  %s", short, expansion),
+		GroupID:            userCommandsGroupID,
+		DisableFlagParsing: true,
+		// Only reached if the alias was not expanded, such as by "mgc help"
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+}
+
+var macroReferenceRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+func evalMacroReference(expr string, document map[string]any) (any, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		expr = "$." + expr
+	}
+	value, err := utils.GetJsonPath(expr, document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %q: %w", expr, err)
+	}
+	return value, nil
+}
+
+// Replace the ${jsonpath} references of the string values by their values in the document.
+// Values that are a single reference keep their type, such as objects and numbers.
+func expandMacroValue(value any, document map[string]any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if m := macroReferenceRe.FindStringSubmatchIndex(v); m != nil && m[0] == 0 && m[1] == len(v) {
+			return evalMacroReference(v[m[2]:m[3]], document)
+		}
+
+		var err error
+		result := macroReferenceRe.ReplaceAllStringFunc(v, func(ref string) string {
+			if err != nil {
+				return ""
+			}
+			var refValue any
+			if refValue, err = evalMacroReference(ref[2:len(ref)-1], document); err != nil {
+				return ""
+			}
+			var s string
+			s, err = formatUserFlagValue(refValue)
+			return s
+		})
+		return result, err
+
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			expanded, err := expandMacroValue(item, document)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = expanded
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := expandMacroValue(item, document)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = expanded
+		}
+		return result, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// Walk the SDK tree by the command words, the remaining ones are positional arguments
+func findUserCommandExecutor(sdk *mgcSdk.Sdk, command string) (exec core.Executor, path []string, args []string, err error) {
+	words := strings.Fields(command)
+	var desc core.Descriptor = sdk.Group()
+	for i, word := range words {
+		grouper, ok := desc.(core.Grouper)
+		if !ok {
+			args = words[i:]
+			break
+		}
+		if desc, err = findChildByNameOrAliases(grouper, word); err != nil {
+			return nil, nil, nil, core.UsageError{Err: fmt.Errorf("invalid command %q: %w", command, err)}
+		}
+		name, _ := getCommandNameAndAliases(desc.Name())
+		path = append(path, name)
+	}
+
+	exec, ok := desc.(core.Executor)
+	if !ok {
+		return nil, nil, nil, core.UsageError{Err: fmt.Errorf("invalid command %q: not an action", command)}
+	}
+	return exec, path, args, nil
+}
+
+// Parse the step flags as done by the actual command
+func getUserStepValues(
+	sdk *mgcSdk.Sdk,
+	rootCmd *cobra.Command,
+	step config.UserMacroStep,
+	document map[string]any,
+) (exec core.Executor, parameters core.Parameters, configs core.Configs, err error) {
+	exec, path, positionalArgs, err := findUserCommandExecutor(sdk, step.Command)
+	if err != nil {
+		return
+	}
+
+	stepFlags := make(map[string]any, len(step.Flags))
+	for name, value := range step.Flags {
+		if stepFlags[name], err = expandMacroValue(value, document); err != nil {
+			err = core.UsageError{Err: fmt.Errorf("flag %q: %w", name, err)}
+			return
+		}
+	}
+	flagsArgs, err := userFlagsArgs(stepFlags)
+	if err != nil {
+		err = core.UsageError{Err: err}
+		return
+	}
+
+	flags, err := newExecutorCmdFlags(rootCmd, exec)
+	if err != nil {
+		return
+	}
+	flags.loadWorkspaceDefaults(sdk.Config(), path)
+
+	stepCmd := &cobra.Command{Use: path[len(path)-1]}
+	stepCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+	flags.addFlags(stepCmd)
+
+	if err = stepCmd.ParseFlags(flagsArgs); err != nil {
+		err = core.UsageError{Err: err}
+		return
+	}
+
+	argsAtDash := stepCmd.ArgsLenAtDash()
+	if argsAtDash >= 0 {
+		argsAtDash += len(positionalArgs)
+	}
+	positionalArgs = append(positionalArgs, stepCmd.Flags().Args()...)
+	if err = flags.setPositionalArgs(positionalArgs, argsAtDash); err != nil {
+		err = core.UsageError{Err: err}
+		return
+	}
+
+	parameters, configs, err = flags.getValues(sdk.Config(), positionalArgs)
+	return
+}
+
+// Steps are run in order, with the global flags of the macro, such as --cli.wait-termination.
+// Only the result of the last step is shown
+func runUserMacro(sdk *mgcSdk.Sdk, cmd *cobra.Command, macro config.UserMacro) error {
+	params := map[string]any{}
+	for name := range macro.Parameters {
+		value, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return err
+		}
+		params[name] = value
+	}
+
+	ctx, finish, err := prepareExecution(sdk.NewContext(), sdk, cmd)
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	ctx, span := telemetry.StartExecutorSpan(ctx, cmd.Name(), params, nil)
+	result, err := runUserMacroSteps(ctx, sdk, cmd, macro, params)
+	telemetry.EndSpan(span, err)
+	if err != nil {
+		return err
+	}
+
+	return handleExecutorResult(ctx, sdk, cmd, result, nil)
+}
+
+// The result is the one of the last step
+func runUserMacroSteps(
+	ctx context.Context,
+	sdk *mgcSdk.Sdk,
+	cmd *cobra.Command,
+	macro config.UserMacro,
+	params map[string]any,
+) (core.Result, error) {
+	stepsResults := map[string]any{}
+	document := map[string]any{"params": params, "steps": stepsResults}
+
+	var result core.Result
+	for i, step := range macro.Steps {
+		exec, parameters, configs, err := getUserStepValues(sdk, cmd.Root(), step, document)
+		if err == nil {
+			logger().Debugw("running macro step", "step", i+1, "command", step.Command, "parameters", parameters, "configs", configs)
+			result, err = handleExecutorPre(ctx, sdk, cmd, exec, parameters, configs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, step.Command, err)
+		}
+
+		if step.Name == "" {
+			continue
+		}
+		if resultWithValue, ok := core.ResultAs[core.ResultWithValue](result); ok {
+			stepsResults[step.Name] = resultWithValue.Value()
+		} else {
+			stepsResults[step.Name] = nil
+		}
+	}
+	return result, nil
+}
+
+func newUserMacroCmd(sdk *mgcSdk.Sdk, name string, macro config.UserMacro) *cobra.Command {
+	short := macro.Description
+	if short == "" {
+		short = fmt.Sprintf("Macro with %d steps", len(macro.Steps))
+	}
+
+	long := short + "\n\nSteps:"
+	for i, step := range macro.Steps {
+		long += fmt.Sprintf("\n  %d. %s", i+1, userCommandLine(step.Command, step.Flags))
+	}
+
+	cmd := &cobra.Command{
+		Use:     name,
+		Short:   short,
+		Long:    long,
+		GroupID: userCommandsGroupID,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserMacro(sdk, cmd, macro)
+		},
+	}
+
+	for _, pair := range utils.SortedMapIterator(macro.Parameters) {
+		usage := pair.Value.Description
+		if pair.Value.Required {
+			usage += " (required)"
+		}
+		cmd.Flags().String(pair.Key, pair.Value.Default, strings.TrimSpace(usage))
+		if pair.Value.Required {
+			_ = cmd.MarkFlagRequired(pair.Key)
+		}
+	}
+
+	return cmd
+}
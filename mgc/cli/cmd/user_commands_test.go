@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+)
+
+func Test_expandUserAlias(t *testing.T) {
+	aliases := map[string]config.UserAlias{
+		"vml": {
+			Command: "virtual-machine instances list",
+			Flags:   map[string]any{"control.limit": 10.0, "expand": []any{"image"}},
+		},
+		"vmget": {
+			Command: "virtual-machine instances get",
+		},
+	}
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"vml", "--control.limit=5", "-o", "json"},
+			expected: []string{"virtual-machine", "instances", "list", "--control.limit=10", `--expand=["image"]`, "--control.limit=5", "-o", "json"},
+		},
+		{
+			args:     []string{"vmget", "some-id"},
+			expected: []string{"virtual-machine", "instances", "get", "some-id"},
+		},
+		{
+			args:     []string{"virtual-machine", "instances", "list"},
+			expected: []string{"virtual-machine", "instances", "list"},
+		},
+		{
+			args:     []string{"__complete", "vml", "--control.o"},
+			expected: []string{"__complete", "virtual-machine", "instances", "list", "--control.limit=10", `--expand=["image"]`, "--control.o"},
+		},
+		{
+			// the alias is the word being completed
+			args:     []string{"__complete", "vml"},
+			expected: []string{"__complete", "vml"},
+		},
+		{
+			args:     []string{},
+			expected: []string{},
+		},
+	}
+	for _, tc := range tests {
+		got := expandUserAlias(aliases, tc.args)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%v: expected %v, got %v", tc.args, tc.expected, got)
+		}
+	}
+}
+
+func Test_expandMacroValue(t *testing.T) {
+	document := map[string]any{
+		"params": map[string]any{"name": "my-vm", "size": "10"},
+		"steps": map[string]any{
+			"vm": map[string]any{
+				"id":     "vm-id",
+				"labels": []any{"a", "b"},
+			},
+		},
+	}
+
+	tests := []struct {
+		value    any
+		expected any
+	}{
+		{"${params.name}", "my-vm"},
+		{"${ $.params.name }", "my-vm"},
+		{"${params.name}-data", "my-vm-data"},
+		{"${params.name}: ${steps.vm.id}", "my-vm: vm-id"},
+		{"${steps.vm.labels}", []any{"a", "b"}},
+		{"labels: ${steps.vm.labels}", `labels: ["a","b"]`},
+		{"no references", "no references"},
+		{10.0, 10.0},
+		{
+			map[string]any{"id": "${steps.vm.id}", "tags": []any{"${params.name}", true}},
+			map[string]any{"id": "vm-id", "tags": []any{"my-vm", true}},
+		},
+	}
+	for _, tc := range tests {
+		got, err := expandMacroValue(tc.value, document)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%v: expected %#v, got %#v", tc.value, tc.expected, got)
+		}
+	}
+
+	for _, value := range []string{"${steps.missing.id}", "prefix-${params.missing}"} {
+		if _, err := expandMacroValue(value, document); err == nil {
+			t.Errorf("%v: expected error", value)
+		}
+	}
+}
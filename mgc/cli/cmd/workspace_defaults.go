@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/cli/cmd/schema_flags"
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	"github.com/getkin/kin-openapi/openapi3"
+	flag "github.com/spf13/pflag"
+)
+
+const workspaceDefaultUsage = "default from workspace"
+
+// Load the parameter defaults of the command from the workspace config, see config.ParameterDefaults.
+//
+// Values are keyed by parameter or flag name, including child flags such as "machine-type.name".
+// The flags usage is updated to show them.
+func (cf *cmdFlags) loadWorkspaceDefaults(cfg *config.Config, commandPath []string) {
+	defaults, err := cfg.ParameterDefaults(commandPath)
+	if err != nil {
+		logger().Warnw("ignored workspace parameter defaults", "error", err)
+		return
+	}
+	if len(defaults) == 0 {
+		return
+	}
+
+	cf.workspaceDefaults = map[string]any{}
+	var childKeys []string
+	for key, value := range defaults {
+		f := cf.findParameterFlag(key)
+		if f == nil {
+			childKeys = append(childKeys, key)
+			continue
+		}
+
+		desc := f.Value.(schema_flags.SchemaFlagValue).Desc()
+		cf.workspaceDefaults[desc.PropName] = value
+		setWorkspaceDefaultUsage(f, value)
+	}
+
+	// child flags are more specific, so they're merged after the whole objects
+	slices.Sort(childKeys)
+	for _, key := range childKeys {
+		f, propPath := cf.findChildParameterFlag(key)
+		if f == nil {
+			logger().Debugw("ignored workspace default of unknown parameter", "command", commandPath, "key", key)
+			continue
+		}
+
+		value := defaults[key]
+		setWorkspaceDefaultUsage(cf.knownFlags[flag.NormalizedName(key)], value)
+		for i := len(propPath) - 1; i >= 0; i-- {
+			value = map[string]any{propPath[i]: value}
+		}
+
+		desc := f.Value.(schema_flags.SchemaFlagValue).Desc()
+		cf.workspaceDefaults[desc.PropName] = mergeWorkspaceDefault(cf.workspaceDefaults[desc.PropName], value)
+		f.DefValue = ""
+	}
+}
+
+func setWorkspaceDefaultUsage(f *flag.Flag, value any) {
+	// the schema default is no longer used
+	f.DefValue = ""
+	if data, err := json.Marshal(value); err == nil {
+		f.Usage += fmt.Sprintf(" (%s: %s)", workspaceDefaultUsage, data)
+	} else {
+		f.Usage += fmt.Sprintf(" (%s)", workspaceDefaultUsage)
+	}
+}
+
+// Parameter flags only, by property or flag name. Config flags have their own values in the config
+func (cf *cmdFlags) findParameterFlag(key string) *flag.Flag {
+	for _, f := range cf.schemaFlags {
+		desc := f.Value.(schema_flags.SchemaFlagValue).Desc()
+		if desc.IsConfig {
+			continue
+		}
+		if desc.PropName == key || f.Name == key {
+			return f
+		}
+	}
+	return nil
+}
+
+// The parameter flag of a child flag name, such as "machine-type" of "machine-type.name",
+// and the property names from it to the child, such as ["name"]
+func (cf *cmdFlags) findChildParameterFlag(key string) (*flag.Flag, []string) {
+	child, ok := cf.knownFlags[flag.NormalizedName(key)]
+	if !ok || !slices.Contains(cf.childFlags, child) {
+		return nil, nil
+	}
+
+	for _, f := range cf.schemaFlags {
+		if f.Value.(schema_flags.SchemaFlagValue).Desc().IsConfig || !strings.HasPrefix(key, f.Name+string(childFlagSeparator)) {
+			continue
+		}
+
+		// each level is a child flag of the previous one
+		var propPath []string
+		name := f.Name
+		for name != key {
+			i := strings.IndexRune(key[len(name)+1:], childFlagSeparator)
+			next := key
+			if i >= 0 {
+				next = key[:len(name)+1+i]
+			}
+			c, ok := cf.knownFlags[flag.NormalizedName(next)]
+			if !ok {
+				break
+			}
+			propPath = append(propPath, c.Value.(schema_flags.SchemaFlagValue).Desc().PropName)
+			name = next
+		}
+		if name == key {
+			return f, propPath
+		}
+	}
+	return nil, nil
+}
+
+// Explicit keys take precedence, objects are merged recursively
+func mergeWorkspaceDefault(defaultValue, value any) any {
+	defaultMap, ok := defaultValue.(map[string]any)
+	if !ok {
+		return value
+	}
+	valueMap, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	result := maps.Clone(defaultMap)
+	for k, v := range valueMap {
+		result[k] = mergeWorkspaceDefault(defaultMap[k], v)
+	}
+	return result
+}
+
+// Same as schema_flags.GetFlagValue(), using the workspace default if the flag wasn't given.
+// If it was given, and both are objects, the default properties not given are used
+func (cf *cmdFlags) getFlagValue(f *flag.Flag, cfg *config.Config) (value any, err error) {
+	fv := f.Value.(schema_flags.SchemaFlagValue)
+	desc := fv.Desc()
+
+	defaultValue, hasDefault := cf.workspaceDefaults[desc.PropName]
+	if !hasDefault || desc.IsConfig {
+		return schema_flags.GetFlagValue(f, cfg)
+	}
+
+	if fv.Changed() {
+		if value, err = schema_flags.GetFlagValue(f, cfg); err != nil {
+			return
+		}
+		value = mergeWorkspaceDefault(defaultValue, value)
+	} else {
+		value = defaultValue
+	}
+
+	if err = desc.Schema.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		err = fmt.Errorf("%s: %w", workspaceDefaultUsage, err)
+	}
+	return
+}
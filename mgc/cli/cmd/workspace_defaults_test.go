@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	"github.com/MagaluCloud/magalu/mgc/core/profile_manager"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/spf13/cobra"
+)
+
+func TestLoadWorkspaceDefaults(t *testing.T) {
+	reference := func() *mgcSchemaPkg.Schema {
+		return mgcSchemaPkg.NewObjectSchema(map[string]*mgcSchemaPkg.Schema{
+			"id":   mgcSchemaPkg.NewStringSchema(),
+			"name": mgcSchemaPkg.NewStringSchema(),
+		}, nil)
+	}
+	schema := mgcSchemaPkg.NewObjectSchema(map[string]*mgcSchemaPkg.Schema{
+		"name":         mgcSchemaPkg.NewStringSchema(),
+		"machine_type": reference(),
+		"image":        reference(),
+	}, nil)
+
+	m, _ := profile_manager.NewInMemoryProfileManager()
+	cfg := config.New(m)
+	err := cfg.Set(config.ParameterDefaultsKey, map[string]any{
+		"virtual-machine instances create": map[string]any{
+			"machine-type.name": "BV1-1-10",
+			"image":             map[string]any{"id": "some-id", "name": "other"},
+			"image.name":        "cloud-ubuntu-24.04 LTS",
+			"unknown.name":      "ignored",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parentCmd := &cobra.Command{}
+	parentCmd.SetGlobalNormalizationFunc(normalizeFlagName)
+	flags, err := newCmdFlags(parentCmd, schema, &mgcSchemaPkg.Schema{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flags.loadWorkspaceDefaults(cfg, []string{"virtual-machine", "instances", "create"})
+
+	values, _, err := flags.getValues(cfg, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]any{
+		"machine_type": map[string]any{"name": "BV1-1-10"},
+		"image":        map[string]any{"id": "some-id", "name": "cloud-ubuntu-24.04 LTS"},
+	}
+	if !reflect.DeepEqual(map[string]any(values), expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
@@ -51,8 +51,10 @@ func main() {
 
 	err := cmd.Execute(Version)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-		os.Exit(1)
+		if !cmd.IsErrorReported(err) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		}
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"github.com/erikgeiser/promptkit/textinput"
+)
+
+type TextInputOptions struct {
+	InitialValue string
+	Placeholder  string
+	Hidden       bool
+	// Called on every change, the prompt can't be submitted while it returns an error.
+	// If nil, empty inputs are refused
+	Validate func(input string) error
+}
+
+func RunTextInputPrompt(message string, options TextInputOptions) (string, error) {
+	input := textinput.New(message)
+	input.InitialValue = options.InitialValue
+	input.Placeholder = options.Placeholder
+	input.Hidden = options.Hidden
+	if options.Validate != nil {
+		input.Validate = options.Validate
+	}
+
+	return input.RunPrompt()
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -63,11 +65,19 @@ type validationResult struct {
 }
 
 type ConfigResult struct {
-	AccessToken     string `json:"access_token"`
-	RefreshToken    string `json:"refresh_token"`
+	AccessToken     string             `json:"access_token"`
+	RefreshToken    string             `json:"refresh_token"`
+	AccessKeyId     string             `json:"access_key_id"`
+	SecretAccessKey string             `json:"secret_access_key"`
+	CurrentEnv      string             `json:"current_environment"`
+	KeyPairs        map[string]KeyPair `json:"key_pairs,omitempty"`
+	BucketKeyPairs  map[string]string  `json:"bucket_key_pairs,omitempty"`
+}
+
+// Object storage key pair stored under a name, besides the default one
+type KeyPair struct {
 	AccessKeyId     string `json:"access_key_id"`
 	SecretAccessKey string `json:"secret_access_key"`
-	CurrentEnv      string `json:"current_environment"`
 }
 
 type Config struct {
@@ -131,6 +141,8 @@ type Auth struct {
 	apiKey                string
 	currentSecurityMethod string
 	xTenantID             string
+	keyPairs              map[string]KeyPair
+	bucketKeyPairs        map[string]string
 }
 
 type Tenant struct {
@@ -189,6 +201,9 @@ func (e FailedRefreshAccessToken) Error() string {
 	return e.Message
 }
 
+// Not logged in, there is no token to refresh
+var ErrNoRefreshToken = errors.New("RefreshToken is not set")
+
 var authKey contextKey = "github.com/MagaluCloud/magalu/mgc/core/Authentication"
 
 func NewContext(parentCtx context.Context, auth *Auth) context.Context {
@@ -377,6 +392,68 @@ func (o *Auth) AccessKeyPair() (accessKeyId, secretAccessKey string) {
 	return o.accessKeyId, o.secretAccessKey
 }
 
+// Key pair to sign requests to the bucket: the named one if given, else the one
+// set for the bucket, else the default AccessKeyPair
+func (o *Auth) AccessKeyPairFor(name, bucket string) (accessKeyId, secretAccessKey string, err error) {
+	if name == "" {
+		name = o.bucketKeyPairs[bucket]
+	}
+	if name == "" {
+		accessKeyId, secretAccessKey = o.AccessKeyPair()
+		return
+	}
+
+	pair, ok := o.keyPairs[name]
+	if !ok {
+		return "", "", fmt.Errorf("key pair %q not found", name)
+	}
+	return pair.AccessKeyId, pair.SecretAccessKey, nil
+}
+
+func (o *Auth) KeyPairs() map[string]KeyPair {
+	return maps.Clone(o.keyPairs)
+}
+
+func (o *Auth) SetKeyPair(name string, pair KeyPair) error {
+	if o.keyPairs == nil {
+		o.keyPairs = map[string]KeyPair{}
+	}
+	o.keyPairs[name] = pair
+	return o.writeCurrentConfig()
+}
+
+// Also removes the buckets set to use it
+func (o *Auth) UnsetKeyPair(name string) error {
+	if _, ok := o.keyPairs[name]; !ok {
+		return fmt.Errorf("key pair %q not found", name)
+	}
+	delete(o.keyPairs, name)
+	maps.DeleteFunc(o.bucketKeyPairs, func(_ string, pairName string) bool {
+		return pairName == name
+	})
+	return o.writeCurrentConfig()
+}
+
+func (o *Auth) BucketKeyPairs() map[string]string {
+	return maps.Clone(o.bucketKeyPairs)
+}
+
+func (o *Auth) SetBucketKeyPair(bucket, name string) error {
+	if _, ok := o.keyPairs[name]; !ok {
+		return fmt.Errorf("key pair %q not found", name)
+	}
+	if o.bucketKeyPairs == nil {
+		o.bucketKeyPairs = map[string]string{}
+	}
+	o.bucketKeyPairs[bucket] = name
+	return o.writeCurrentConfig()
+}
+
+func (o *Auth) UnsetBucketKeyPair(bucket string) error {
+	delete(o.bucketKeyPairs, bucket)
+	return o.writeCurrentConfig()
+}
+
 func (o *Auth) CurrentSecurityMethod() string {
 	return o.currentSecurityMethod
 }
@@ -446,6 +523,8 @@ func (o *Auth) writeCurrentConfig() error {
 	authResult.RefreshToken = o.refreshToken
 	authResult.AccessKeyId = o.accessKeyId
 	authResult.SecretAccessKey = o.secretAccessKey
+	authResult.KeyPairs = o.keyPairs
+	authResult.BucketKeyPairs = o.bucketKeyPairs
 	return o.writeConfigFile(authResult)
 }
 
@@ -456,6 +535,8 @@ func (o *Auth) InitTokensFromFile() {
 		o.refreshToken = authResult.RefreshToken
 		o.accessKeyId = authResult.AccessKeyId
 		o.secretAccessKey = authResult.SecretAccessKey
+		o.keyPairs = authResult.KeyPairs
+		o.bucketKeyPairs = authResult.BucketKeyPairs
 	}
 
 	if envVal := os.Getenv("MGC_ACCESS_TOKEN"); envVal != "" {
@@ -625,7 +706,7 @@ func (o *Auth) doRefreshAccessToken(ctx context.Context) (string, error) {
 
 func (o *Auth) newRefreshAccessTokenRequest(ctx context.Context) (*http.Request, error) {
 	if o.refreshToken == "" {
-		return nil, fmt.Errorf("RefreshToken is not set")
+		return nil, ErrNoRefreshToken
 	}
 
 	config := o.GetConfig()
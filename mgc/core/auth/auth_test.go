@@ -111,6 +111,49 @@ func setAccessKey(name string, expected_error error, acess_key_id string, secret
 	}
 }
 
+func setBucketKeyPair(name string, pairName string, pair KeyPair, bucket string, provided []utils.TestFsEntry, expected []utils.TestFsEntry) testCaseAuth {
+	provided = utils.AutoMkdirAll(provided)
+	expected = utils.AutoMkdirAll(expected)
+	return testCaseAuth{
+		name:       fmt.Sprintf("Auth.SetBucketKeyPair(%q)", name),
+		providedFs: provided,
+		expectedFs: expected,
+		run: func(auth *Auth) error {
+			if err := auth.SetBucketKeyPair(bucket, pairName); err == nil {
+				return fmt.Errorf("expected error setting an unknown key pair")
+			}
+			if err := auth.SetKeyPair(pairName, pair); err != nil {
+				return err
+			}
+			if err := auth.SetBucketKeyPair(bucket, pairName); err != nil {
+				return err
+			}
+
+			id, secret, err := auth.AccessKeyPairFor("", bucket)
+			if err != nil || id != pair.AccessKeyId || secret != pair.SecretAccessKey {
+				return fmt.Errorf("expected the bucket key pair, found: %q %q %v", id, secret, err)
+			}
+			id, _, err = auth.AccessKeyPairFor("", "other-bucket")
+			if err != nil || id != "" {
+				return fmt.Errorf("expected the default key pair, found: %q %v", id, err)
+			}
+			if _, _, err = auth.AccessKeyPairFor("unknown", bucket); err == nil {
+				return fmt.Errorf("expected error selecting an unknown key pair")
+			}
+
+			// named key pairs are only removed explicitly
+			if err := auth.Logout(); err != nil {
+				return err
+			}
+			id, _, err = auth.AccessKeyPairFor("", bucket)
+			if err != nil || id != pair.AccessKeyId {
+				return fmt.Errorf("expected the bucket key pair after logout, found: %q %v", id, err)
+			}
+			return nil
+		},
+	}
+}
+
 func requestAuthTokenWithAuthorizationCode(name string, transport mockTransport, verifier *codeVerifier, expectedErr bool, provided []utils.TestFsEntry, expected []utils.TestFsEntry) testCaseAuth {
 	provided = utils.AutoMkdirAll(provided)
 	expected = utils.AutoMkdirAll(expected)
@@ -343,6 +386,36 @@ access_token: ""
 current_environment: ""
 refresh_token: ""
 secret_access_key: MySecretAccessKeyTeste
+`),
+				},
+				{
+					Path: "/default/cli.yaml",
+					Mode: utils.FILE_PERMISSION,
+					Data: []byte(`env: temp
+`),
+				},
+			}),
+		setBucketKeyPair("Valid key pair", "tenant-b", KeyPair{AccessKeyId: "TenantBKeyId", SecretAccessKey: "TenantBSecret"}, "bucket-b",
+			[]utils.TestFsEntry{
+				{
+					Path: "/default/auth.yaml",
+					Mode: utils.FILE_PERMISSION,
+				},
+			}, []utils.TestFsEntry{
+				{
+					Path: "/default/auth.yaml",
+					Mode: utils.FILE_PERMISSION,
+					Data: []byte(`access_key_id: ""
+access_token: ""
+bucket_key_pairs:
+    bucket-b: tenant-b
+current_environment: ""
+key_pairs:
+    tenant-b:
+        access_key_id: TenantBKeyId
+        secret_access_key: TenantBSecret
+refresh_token: ""
+secret_access_key: ""
 `),
 				},
 				{
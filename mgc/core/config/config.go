@@ -9,9 +9,10 @@ import (
 
 	"github.com/MagaluCloud/magalu/mgc/core"
 	"github.com/MagaluCloud/magalu/mgc/core/profile_manager"
+	"github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
 
 	"github.com/invopop/yaml"
-	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
@@ -25,9 +26,12 @@ type tempConfig struct {
 }
 
 type Config struct {
-	pm         *profile_manager.ProfileManager
-	viper      *viper.Viper
-	tempConfig *tempConfig
+	pm              *profile_manager.ProfileManager
+	viper           *viper.Viper
+	tempConfig      *tempConfig
+	systemLayer     *fileLayer
+	projectLayer    *fileLayer
+	fileLayerErrors []error
 }
 
 const (
@@ -65,6 +69,7 @@ func (c *Config) init() {
 
 	c.viper = v
 	_ = c.readFromFile()
+	c.loadFileLayers(defaultSystemConfigFile(), findProjectConfigFile())
 }
 
 func (c *Config) FilePath() string {
@@ -77,57 +82,55 @@ func (c *Config) BuiltInConfigs() (map[string]*core.Schema, error) {
 		return nil, fmt.Errorf("unable to get logger config schema: %w", err)
 	}
 
+	telemetrySchema, err := schema.SchemaFromType[telemetry.Config]()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get telemetry config schema: %w", err)
+	}
+	telemetrySchema.Description = "OpenTelemetry traces and metrics of the executed commands and their HTTP requests"
+
 	logfilterSchema := logfilterSchema()
 	defaultOutputSchema := defaultOutputSchema()
 
 	configMap := map[string]*core.Schema{
-		"logging":       loggerConfigSchema,
-		"logfilter":     logfilterSchema,
-		"defaultOutput": defaultOutputSchema,
+		"logging":            loggerConfigSchema,
+		"logfilter":          logfilterSchema,
+		"defaultOutput":      defaultOutputSchema,
+		ParameterDefaultsKey: parameterDefaultsSchema(),
+		UserAliasesKey:       userAliasesSchema(),
+		UserMacrosKey:        userMacrosSchema(),
+		telemetry.ConfigKey:  telemetrySchema,
+	}
+	for key, s := range transportConfigSchemas() {
+		configMap[key] = s
 	}
 
 	return configMap, nil
 }
 
+// The value is looked up in the temporary configs, environment variables, project, workspace and
+// system files, in this order. See PROJECT_CONFIG_FILE
 func (c *Config) Get(key string, out any) error {
 	val := reflect.ValueOf(out)
 	if val.Kind() == reflect.Pointer && !val.Elem().IsValid() {
 		return fmt.Errorf("result should not be nil pointer")
 	}
 
-	decodeHookFunc := mapstructure.ComposeDecodeHookFunc(
-		mapstructure.StringToTimeDurationHookFunc(),
-		mapstructure.StringToSliceHookFunc(","),
-		mapstructure.TextUnmarshallerHookFunc(),
-		stringUnmarshalHook,
-	)
-
-	if c.tempConfig != nil {
-		if outVal, found := c.tempConfig.configMap[key]; found {
-
-			decodeConfig := mapstructure.DecoderConfig{
-				DecodeHook: decodeHookFunc,
-				Result:     out,
-			}
-
-			decoder, err := mapstructure.NewDecoder(&decodeConfig)
-			if err != nil {
-				return fmt.Errorf("fail to create a config decoder: %s ", err.Error())
-			}
-
-			err = decoder.Decode(outVal)
-			if err != nil {
-				return fmt.Errorf("fail to run config decoder: %s", err.Error())
-			}
-			return nil
-		}
+	value, origin, found := c.lookup(key)
+	switch {
+	case !found:
+		return nil
+	case origin.Layer == OriginTemp:
+		return decodeValue(value, out, false)
+	case origin.Layer == OriginWorkspace:
+		return c.viper.UnmarshalKey(
+			key,
+			out,
+			viper.DecodeHook(decodeHookFunc),
+		)
+	default:
+		// like viper.UnmarshalKey(), environment variables are strings
+		return decodeValue(value, out, true)
 	}
-
-	return c.viper.UnmarshalKey(
-		key,
-		out,
-		viper.DecodeHook(decodeHookFunc),
-	)
 }
 
 func stringUnmarshalHook(f reflect.Value, t reflect.Value) (interface{}, error) {
@@ -151,10 +154,11 @@ func stringUnmarshalHook(f reflect.Value, t reflect.Value) (interface{}, error)
 
 		return target, err
 	case reflect.Map:
-		target := t.Interface()
-		err := yaml.Unmarshal([]byte(str), target)
+		// the target map may be nil, decode into a new one
+		target := reflect.New(dereferenced.Type())
+		err := yaml.Unmarshal([]byte(str), target.Interface())
 
-		return target, err
+		return target.Elem().Interface(), err
 	case reflect.Invalid:
 		// Try to decode string to any, it may work. If not, just return the value as-is
 		target := t.Interface()
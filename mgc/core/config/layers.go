@@ -0,0 +1,242 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// Values are looked up in the following layers, the first one to have the key wins:
+//   - temporary configs, see SetTempConfig()
+//   - $MGC_CONFIG_<KEY> environment variables, or the older $MGC_<KEY>
+//   - the project file, PROJECT_CONFIG_FILE found by walking up from the current directory, only
+//     for the keys in projectConfigKeys
+//   - the workspace file, the only one changed by Set() and Delete()
+//   - the system file, SYSTEM_CONFIG_FILE_ENV or the OS default
+//
+// The CLI flags of the executor configs take precedence over all of them.
+
+const (
+	PROJECT_CONFIG_FILE    = ".mgc.yaml"
+	SYSTEM_CONFIG_FILE_ENV = "MGC_SYSTEM_CONFIG_FILE"
+	CONFIG_ENV_PREFIX      = ENV_PREFIX + "_CONFIG_"
+)
+
+const (
+	OriginTemp      = "temp"
+	OriginEnv       = "env"
+	OriginProject   = "project"
+	OriginWorkspace = "workspace"
+	OriginSystem    = "system"
+)
+
+// Where a config value comes from, such as the environment variable or file
+type Origin struct {
+	Layer  string `json:"layer"`
+	Source string `json:"source,omitempty"`
+}
+
+func (o Origin) String() string {
+	if o.Source == "" {
+		return o.Layer
+	}
+	return fmt.Sprintf("%s (%s)", o.Layer, o.Source)
+}
+
+// The project file comes with whatever directory the CLI is run from, such as a cloned repository,
+// so it may only change how the CLI behaves, never where requests and credentials are sent to
+var projectConfigKeys = []string{
+	"region",
+	"defaultOutput",
+	"chunkSize",
+	"workers",
+	"maxBandwidth",
+	"maxInFlight",
+}
+
+func isProjectConfigKey(key string) bool {
+	return slices.ContainsFunc(projectConfigKeys, func(k string) bool { return strings.EqualFold(k, key) })
+}
+
+type fileLayer struct {
+	path  string
+	viper *viper.Viper
+}
+
+func defaultSystemConfigFile() string {
+	if path := os.Getenv(SYSTEM_CONFIG_FILE_ENV); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "mgc", CONFIG_FILE)
+	}
+	return filepath.Join("/etc", "mgc", CONFIG_FILE)
+}
+
+// Closest PROJECT_CONFIG_FILE from the current directory up to the root, if any
+func findProjectConfigFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, PROJECT_CONFIG_FILE)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Missing files are not an error, the layer is just skipped
+func readFileLayer(path string) (*fileLayer, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetConfigType(CONFIG_FILE_TYPE)
+	if err := v.ReadConfig(bytes.NewBuffer(data)); err != nil {
+		return nil, fmt.Errorf("invalid config file %q: %w", path, err)
+	}
+	return &fileLayer{path: path, viper: v}, nil
+}
+
+// Invalid files are ignored, as the logger is configured later, see FileLayerErrors()
+func (c *Config) loadFileLayers(systemFile, projectFile string) {
+	var err error
+	c.fileLayerErrors = nil
+	if c.systemLayer, err = readFileLayer(systemFile); err != nil {
+		c.fileLayerErrors = append(c.fileLayerErrors, err)
+	}
+	if c.projectLayer, err = readFileLayer(projectFile); err != nil {
+		c.fileLayerErrors = append(c.fileLayerErrors, err)
+	}
+	if c.projectLayer != nil {
+		for key := range c.projectLayer.viper.AllSettings() {
+			if !isProjectConfigKey(key) {
+				c.fileLayerErrors = append(c.fileLayerErrors, fmt.Errorf(
+					"%q is not allowed in the project file %q, only %s. Set it in the workspace instead",
+					key, projectFile, strings.Join(projectConfigKeys, ", "),
+				))
+			}
+		}
+	}
+}
+
+// Errors reading the system and project files, and keys of the project file, which were ignored
+func (c *Config) FileLayerErrors() []error {
+	return c.fileLayerErrors
+}
+
+// "chunkSize" is CHUNK_SIZE. Keys are case insensitive, so CHUNKSIZE is also accepted
+func envKeyNames(key string) []string {
+	var snake strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) && i > 0 {
+			snake.WriteRune('_')
+		}
+		if r == '.' || r == '-' {
+			r = '_'
+		}
+		snake.WriteRune(unicode.ToUpper(r))
+	}
+
+	upper := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	names := []string{CONFIG_ENV_PREFIX + snake.String()}
+	if upper != snake.String() {
+		names = append(names, CONFIG_ENV_PREFIX+upper)
+	}
+	// kept for compatibility, it's what viper.AutomaticEnv() looks for
+	return append(names, ENV_PREFIX+"_"+upper)
+}
+
+func lookupEnvKey(key string) (value string, name string, found bool) {
+	for _, name := range envKeyNames(key) {
+		if value, found := os.LookupEnv(name); found {
+			return value, name, true
+		}
+	}
+	return "", "", false
+}
+
+// Raw value of the key and its origin, following the precedence of the layers
+func (c *Config) lookup(key string) (value any, origin Origin, found bool) {
+	if c.tempConfig != nil {
+		if value, found := c.tempConfig.configMap[key]; found {
+			return value, Origin{Layer: OriginTemp}, true
+		}
+	}
+
+	if value, name, found := lookupEnvKey(key); found {
+		return value, Origin{Layer: OriginEnv, Source: name}, true
+	}
+
+	if c.projectLayer != nil && isProjectConfigKey(key) && c.projectLayer.viper.InConfig(key) {
+		return c.projectLayer.viper.Get(key), Origin{Layer: OriginProject, Source: c.projectLayer.path}, true
+	}
+
+	if c.viper.IsSet(key) {
+		return c.viper.Get(key), Origin{Layer: OriginWorkspace, Source: filepath.Join(c.FilePath(), CONFIG_FILE)}, true
+	}
+
+	if c.systemLayer != nil && c.systemLayer.viper.InConfig(key) {
+		return c.systemLayer.viper.Get(key), Origin{Layer: OriginSystem, Source: c.systemLayer.path}, true
+	}
+
+	return nil, Origin{}, false
+}
+
+// Where the current value of the key comes from. See Config.Get() for the precedence
+func (c *Config) Origin(key string) (Origin, bool) {
+	_, origin, found := c.lookup(key)
+	return origin, found
+}
+
+var decodeHookFunc = mapstructure.ComposeDecodeHookFunc(
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+	mapstructure.TextUnmarshallerHookFunc(),
+	stringUnmarshalHook,
+)
+
+func decodeValue(value any, out any, weaklyTyped bool) error {
+	decodeConfig := mapstructure.DecoderConfig{
+		DecodeHook:       decodeHookFunc,
+		WeaklyTypedInput: weaklyTyped,
+		Result:           out,
+	}
+
+	decoder, err := mapstructure.NewDecoder(&decodeConfig)
+	if err != nil {
+		return fmt.Errorf("fail to create a config decoder: %s ", err.Error())
+	}
+
+	if err = decoder.Decode(value); err != nil {
+		return fmt.Errorf("fail to run config decoder: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnvKeyNames(t *testing.T) {
+	cases := map[string][]string{
+		"region":    {"MGC_CONFIG_REGION", "MGC_REGION"},
+		"chunkSize": {"MGC_CONFIG_CHUNK_SIZE", "MGC_CONFIG_CHUNKSIZE", "MGC_CHUNKSIZE"},
+		"x-api-key": {"MGC_CONFIG_X_API_KEY", "MGC_X_API_KEY"},
+	}
+	for key, expected := range cases {
+		if got := envKeyNames(key); !reflect.DeepEqual(got, expected) {
+			t.Errorf("envKeyNames(%q): expected %v, got %v", key, expected, got)
+		}
+	}
+}
+
+func TestLayers(t *testing.T) {
+	dir := t.TempDir()
+	systemFile := filepath.Join(dir, "system.yaml")
+	projectFile := filepath.Join(dir, PROJECT_CONFIG_FILE)
+	if err := os.WriteFile(systemFile, []byte("region: br-mgl1\nworkers: 2\nchunkSize: 8\nmaxInFlight: 4\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectFile, []byte("region: br-ne1\nchunkSize: 16\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err, _ := setupWithFile([]byte("region: br-se1\nworkers: 4\n"), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.loadFileLayers(systemFile, projectFile)
+	if errs := c.FileLayerErrors(); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	t.Setenv("MGC_CONFIG_CHUNK_SIZE", "32")
+
+	cases := []struct {
+		key    string
+		value  int
+		origin Origin
+	}{
+		{"maxInFlight", 4, Origin{Layer: OriginSystem, Source: systemFile}},
+		{"workers", 4, Origin{Layer: OriginWorkspace, Source: filepath.Join(c.FilePath(), CONFIG_FILE)}},
+		{"chunkSize", 32, Origin{Layer: OriginEnv, Source: "MGC_CONFIG_CHUNK_SIZE"}},
+	}
+	for _, tc := range cases {
+		var value int
+		if err := c.Get(tc.key, &value); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.key, err)
+		}
+		if value != tc.value {
+			t.Errorf("%s: expected %d, got %d", tc.key, tc.value, value)
+		}
+		if origin, found := c.Origin(tc.key); !found || origin != tc.origin {
+			t.Errorf("%s: expected origin %v, got %v", tc.key, tc.origin, origin)
+		}
+	}
+
+	// the project file has precedence over the workspace
+	var region string
+	if err := c.Get("region", &region); err != nil || region != "br-ne1" {
+		t.Errorf("expected the project region, got %q (%v)", region, err)
+	}
+
+	if _, found := c.Origin("unknown"); found {
+		t.Errorf("unexpected origin of an unknown key")
+	}
+
+	if err := os.WriteFile(projectFile, []byte("region: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c.loadFileLayers(systemFile, projectFile)
+	if errs := c.FileLayerErrors(); len(errs) != 1 {
+		t.Errorf("expected an error for the invalid project file, got %v", errs)
+	}
+}
+
+func TestProjectLayerKeys(t *testing.T) {
+	dir := t.TempDir()
+	projectFile := filepath.Join(dir, PROJECT_CONFIG_FILE)
+	if err := os.WriteFile(projectFile, []byte("region: br-ne1\nproxy: http://evil:3128\nserverUrl: http://evil\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err, _ := setupWithFile([]byte("serverUrl: https://api.magalu.cloud\n"), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.loadFileLayers("", projectFile)
+	if errs := c.FileLayerErrors(); len(errs) != 2 {
+		t.Errorf("expected an error for each key not allowed in the project file, got %v", errs)
+	}
+
+	var region string
+	if err := c.Get("region", &region); err != nil || region != "br-ne1" {
+		t.Errorf("expected the project region, got %q (%v)", region, err)
+	}
+
+	var serverUrl string
+	if err := c.Get("serverUrl", &serverUrl); err != nil || serverUrl != "https://api.magalu.cloud" {
+		t.Errorf("expected the workspace serverUrl, got %q (%v)", serverUrl, err)
+	}
+	if _, found := c.Origin("proxy"); found {
+		t.Errorf("unexpected proxy from the project file")
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Default values of executor parameters, by command path pattern, such as:
+//
+//	defaults:
+//	  virtual-machine instances create:
+//	    availability-zone: br-se1-a
+//	    machine-type:
+//	      name: BV1-1-10
+//	  "virtual-machine *":
+//	    vpc-id: 00000000-0000-0000-0000-000000000000
+//
+// Patterns are space separated command names, each may use path.Match() globs,
+// and "**" matches any number of commands. Values are keyed by parameter or flag name.
+const ParameterDefaultsKey = "defaults"
+
+type ParameterDefaults map[string]map[string]any
+
+func parameterDefaultsSchema() *core.Schema {
+	s := schema.NewObjectSchema(nil, nil)
+	s.Description = `Default values of command parameters, by command path pattern, such as "virtual-machine instances create" or "virtual-machine *". Explicit flags take precedence`
+	// Parameters are only known by each executor, they are validated when used
+	values := schema.NewObjectSchema(nil, nil)
+	values.AdditionalProperties = openapi3.AdditionalProperties{}
+	s.AdditionalProperties = openapi3.AdditionalProperties{Schema: &openapi3.SchemaRef{Value: (*openapi3.Schema)(values)}}
+	return s
+}
+
+func matchCommandPatternParts(pattern, commandPath []string) bool {
+	if len(pattern) == 0 {
+		return len(commandPath) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(commandPath); i++ {
+			if matchCommandPatternParts(pattern[1:], commandPath[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(commandPath) == 0 {
+		return false
+	}
+
+	if ok, err := path.Match(pattern[0], commandPath[0]); err != nil || !ok {
+		return false
+	}
+	return matchCommandPatternParts(pattern[1:], commandPath[1:])
+}
+
+// Whether the command path, without the program name, matches the pattern
+func MatchCommandPattern(pattern string, commandPath []string) bool {
+	return matchCommandPatternParts(strings.Fields(pattern), commandPath)
+}
+
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// Explicit paths are the most specific, then longer patterns
+func compareCommandPatterns(a, b string) int {
+	if aGlob, bGlob := isGlobPattern(a), isGlobPattern(b); aGlob != bGlob {
+		if aGlob {
+			return -1
+		}
+		return 1
+	}
+	if c := len(strings.Fields(a)) - len(strings.Fields(b)); c != 0 {
+		return c
+	}
+	if c := len(a) - len(b); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// Recursively merge src into dst, src values take precedence
+func mergeDefaultValues(dst, src map[string]any) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]any)
+		dstMap, dstIsMap := dst[k].(map[string]any)
+		if srcIsMap && dstIsMap {
+			merged := make(map[string]any, len(dstMap))
+			mergeDefaultValues(merged, dstMap)
+			mergeDefaultValues(merged, srcMap)
+			dst[k] = merged
+		} else {
+			dst[k] = v
+		}
+	}
+}
+
+// Merge the values of all patterns matching the command. More specific patterns take precedence
+func (d ParameterDefaults) Get(commandPath []string) map[string]any {
+	var patterns []string
+	for pattern := range d {
+		if MatchCommandPattern(pattern, commandPath) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	slices.SortFunc(patterns, compareCommandPatterns)
+
+	result := map[string]any{}
+	for _, pattern := range patterns {
+		mergeDefaultValues(result, d[pattern])
+	}
+	return result
+}
+
+// Parameter defaults of the command path, without the program name, such as
+// []string{"virtual-machine", "instances", "create"}
+func (c *Config) ParameterDefaults(commandPath []string) (map[string]any, error) {
+	var defaults ParameterDefaults
+	if err := c.Get(ParameterDefaultsKey, &defaults); err != nil {
+		return nil, fmt.Errorf("invalid %q config: %w", ParameterDefaultsKey, err)
+	}
+	return defaults.Get(commandPath), nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchCommandPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    []string
+		match   bool
+	}{
+		{"virtual-machine instances create", []string{"virtual-machine", "instances", "create"}, true},
+		{"virtual-machine instances create", []string{"virtual-machine", "instances", "list"}, false},
+		{"virtual-machine instances", []string{"virtual-machine", "instances", "create"}, false},
+		{"virtual-machine *", []string{"virtual-machine", "instances"}, true},
+		{"virtual-machine *", []string{"virtual-machine", "instances", "create"}, false},
+		{"virtual-machine * create", []string{"virtual-machine", "instances", "create"}, true},
+		{"virtual-machine **", []string{"virtual-machine", "instances", "create"}, true},
+		{"virtual-machine **", []string{"virtual-machine"}, true},
+		{"** create", []string{"block-storage", "volumes", "create"}, true},
+		{"** create", []string{"block-storage", "volumes", "list"}, false},
+		{"block-storage volumes cr?ate", []string{"block-storage", "volumes", "create"}, true},
+		{"block-storage [", []string{"block-storage", "volumes"}, false},
+	}
+
+	for _, tc := range cases {
+		if got := MatchCommandPattern(tc.pattern, tc.path); got != tc.match {
+			t.Errorf("MatchCommandPattern(%q, %v): expected %v, got %v", tc.pattern, tc.path, tc.match, got)
+		}
+	}
+}
+
+func TestParameterDefaultsGet(t *testing.T) {
+	defaults := ParameterDefaults{
+		"**": {
+			"region-hint": "all",
+		},
+		"virtual-machine **": {
+			"availability-zone": "br-se1-b",
+			"image":             map[string]any{"name": "cloud-ubuntu-24.04 LTS"},
+		},
+		"virtual-machine instances create": {
+			"availability-zone": "br-se1-a",
+			"image":             map[string]any{"id": "some-id"},
+		},
+		"block-storage **": {
+			"availability-zone": "br-ne1-a",
+		},
+	}
+
+	expected := map[string]any{
+		"region-hint":       "all",
+		"availability-zone": "br-se1-a",
+		"image":             map[string]any{"name": "cloud-ubuntu-24.04 LTS", "id": "some-id"},
+	}
+	got := defaults.Get([]string{"virtual-machine", "instances", "create"})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+
+	expected = map[string]any{
+		"region-hint":       "all",
+		"availability-zone": "br-se1-b",
+		"image":             map[string]any{"name": "cloud-ubuntu-24.04 LTS"},
+	}
+	got = defaults.Get([]string{"virtual-machine", "instances", "list"})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+
+	// the merged values must not change the config
+	if _, ok := defaults["virtual-machine **"]["image"].(map[string]any)["id"]; ok {
+		t.Errorf("config values were changed: %#v", defaults)
+	}
+}
+
+func TestParameterDefaultsFromConfig(t *testing.T) {
+	c, _ := setupWithoutFile("")
+
+	value := map[string]any{
+		"virtual-machine instances create": map[string]any{
+			"machine-type": map[string]any{"name": "BV1-1-10"},
+		},
+	}
+	if err := c.Set(ParameterDefaultsKey, value); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := c.ParameterDefaults([]string{"virtual-machine", "instances", "create"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]any{"machine-type": map[string]any{"name": "BV1-1-10"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+}
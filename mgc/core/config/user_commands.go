@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// User defined commands, added to the root of the CLI:
+//
+//	aliases:
+//	  vml:
+//	    command: virtual-machine instances list
+//	    flags:
+//	      control.limit: 10
+//	macros:
+//	  vm-with-volume:
+//	    parameters:
+//	      name:
+//	        required: true
+//	    steps:
+//	      - name: vm
+//	        command: virtual-machine instances create
+//	        flags:
+//	          name: ${params.name}
+//	      - name: volume
+//	        command: block-storage volumes create
+//	        flags:
+//	          name: ${params.name}-data
+//	      - command: block-storage volumes attach
+//	        flags:
+//	          id: ${steps.volume.id}
+//	          virtual-machine-id: ${steps.vm.id}
+const (
+	UserAliasesKey = "aliases"
+	UserMacrosKey  = "macros"
+)
+
+type UserAlias struct {
+	Command     string         `json:"command"`
+	Flags       map[string]any `json:"flags,omitempty"`
+	Description string         `json:"description,omitempty"`
+}
+
+type UserMacroParameter struct {
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+type UserMacroStep struct {
+	Name    string         `json:"name,omitempty"`
+	Command string         `json:"command"`
+	Flags   map[string]any `json:"flags,omitempty"`
+}
+
+type UserMacro struct {
+	Description string                        `json:"description,omitempty"`
+	Parameters  map[string]UserMacroParameter `json:"parameters,omitempty"`
+	Steps       []UserMacroStep               `json:"steps"`
+}
+
+func newStringSchema(description string) *core.Schema {
+	s := schema.NewStringSchema()
+	s.Description = description
+	return s
+}
+
+// Objects with any property, by name
+func newMapSchema(description string, value *core.Schema) *core.Schema {
+	s := schema.NewObjectSchema(nil, nil)
+	s.Description = description
+	if value != nil {
+		s.AdditionalProperties = openapi3.AdditionalProperties{Schema: &openapi3.SchemaRef{Value: (*openapi3.Schema)(value)}}
+	} else {
+		s.AdditionalProperties = openapi3.AdditionalProperties{}
+	}
+	return s
+}
+
+func newCommandSchema() *core.Schema {
+	s := schema.NewStringSchema()
+	s.Description = "Command path, such as 'virtual-machine instances list'. Words after the command are positional arguments"
+	s.MinLength = 1
+	return s
+}
+
+func userAliasesSchema() *core.Schema {
+	alias := schema.NewObjectSchema(map[string]*core.Schema{
+		"command":     newCommandSchema(),
+		"flags":       newMapSchema("Preset flags, by name. Flags given to the alias take precedence", nil),
+		"description": newStringSchema("Shown in the help"),
+	}, []string{"command"})
+
+	return newMapSchema("User defined aliases, by name: command paths with preset flags", alias)
+}
+
+func userMacrosSchema() *core.Schema {
+	required := schema.NewBooleanSchema()
+	required.Description = "Whether the parameter must be given"
+
+	parameter := schema.NewObjectSchema(map[string]*core.Schema{
+		"description": newStringSchema("Shown in the help"),
+		"required":    required,
+		"default":     newStringSchema("Value used if the parameter is not given"),
+	}, nil)
+
+	step := schema.NewObjectSchema(map[string]*core.Schema{
+		"name":    newStringSchema("Name used to reference the step result, as ${steps.NAME}"),
+		"command": newCommandSchema(),
+		"flags":   newMapSchema("Flags, by name. String values may reference the macro parameters and the results of previous steps with ${jsonpath}, such as ${params.name} or ${steps.vm.id}", nil),
+	}, []string{"command"})
+
+	steps := schema.NewArraySchema(step)
+	steps.Description = "Commands to run, in order. The result of the last one is the macro output"
+	steps.MinItems = 1
+
+	macro := schema.NewObjectSchema(map[string]*core.Schema{
+		"description": newStringSchema("Shown in the help"),
+		"parameters":  newMapSchema("Macro flags, by name", parameter),
+		"steps":       steps,
+	}, []string{"steps"})
+
+	return newMapSchema("User defined macros, by name: sequences of commands where later steps may use the results of earlier ones", macro)
+}
+
+func (c *Config) UserAliases() (map[string]UserAlias, error) {
+	var aliases map[string]UserAlias
+	if err := c.Get(UserAliasesKey, &aliases); err != nil {
+		return nil, fmt.Errorf("invalid %q config: %w", UserAliasesKey, err)
+	}
+	return aliases, nil
+}
+
+func (c *Config) UserMacros() (map[string]UserMacro, error) {
+	var macros map[string]UserMacro
+	if err := c.Get(UserMacrosKey, &macros); err != nil {
+		return nil, fmt.Errorf("invalid %q config: %w", UserMacrosKey, err)
+	}
+	return macros, nil
+}
@@ -78,8 +78,45 @@ func ConfirmPromptWithTemplate(msg string) func(parameters Parameters, configs C
 	}
 }
 
+// ContextConfirmableExecutor is confirmed with a prompt that depends on the state of the
+// resources, such as how many objects the operation applies to, so it needs to query them.
+// An empty message skips the confirmation
+type ContextConfirmableExecutor interface {
+	Executor
+	ConfirmPromptWithContext(ctx context.Context, parameters Parameters, configs Configs) (message string, err error)
+}
+
+func NewContextConfirmableExecutor(
+	exec Executor,
+	confirmPrompt func(ctx context.Context, parameters Parameters, configs Configs) (message string, err error),
+) ContextConfirmableExecutor {
+	return &contextConfirmableExecutor{exec, confirmPrompt}
+}
+
+type contextConfirmableExecutor struct {
+	Executor
+	confirmPrompt func(ctx context.Context, parameters Parameters, configs Configs) (message string, err error)
+}
+
+func (o *contextConfirmableExecutor) Execute(ctx context.Context, parameters Parameters, configs Configs) (result Result, err error) {
+	result, err = o.Executor.Execute(ctx, parameters, configs)
+	return ExecutorWrapResult(o, result, err)
+}
+
+func (o *contextConfirmableExecutor) Unwrap() Executor {
+	return o.Executor
+}
+
+func (o *contextConfirmableExecutor) ConfirmPromptWithContext(ctx context.Context, parameters Parameters, configs Configs) (message string, err error) {
+	return o.confirmPrompt(ctx, parameters, configs)
+}
+
 var _ Executor = (*confirmableExecutor)(nil)
 var _ ExecutorWrapper = (*confirmableExecutor)(nil)
 var _ ConfirmableExecutor = (*confirmableExecutor)(nil)
 
+var _ Executor = (*contextConfirmableExecutor)(nil)
+var _ ExecutorWrapper = (*contextConfirmableExecutor)(nil)
+var _ ContextConfirmableExecutor = (*contextConfirmableExecutor)(nil)
+
 var _ error = UserDeniedConfirmationError{}
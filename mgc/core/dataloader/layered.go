@@ -0,0 +1,69 @@
+package dataloader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// A named Loader, so users know where the contents came from
+type Layer struct {
+	Name   string
+	Loader Loader
+}
+
+func (l Layer) String() string {
+	return fmt.Sprintf("%s: %v", l.Name, l.Loader)
+}
+
+// Layers are stacked in the given order, the last layer that has the name wins.
+// Layers that fail with errors other than "not exist" are skipped as well, so a
+// broken upper layer doesn't hide the contents of the lower ones.
+type LayeredLoader struct {
+	Layers []Layer
+}
+
+func NewLayeredLoader(layers ...Layer) *LayeredLoader {
+	return &LayeredLoader{layers}
+}
+
+func (l *LayeredLoader) Load(name string) ([]byte, error) {
+	_, data, err := l.LoadWithLayer(name)
+	return data, err
+}
+
+// Same as Load(), but also returns the name of the layer that provided the contents
+func (l *LayeredLoader) LoadWithLayer(name string) (layer string, data []byte, err error) {
+	var lastErr error
+	for i := len(l.Layers) - 1; i >= 0; i-- {
+		data, err = l.Layers[i].Loader.Load(name)
+		if err == nil {
+			return l.Layers[i].Name, data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			lastErr = fmt.Errorf("%s: %w", l.Layers[i].Name, err)
+		}
+	}
+	if lastErr == nil {
+		lastErr = &os.PathError{Op: "open", Path: name, Err: syscall.ENOENT}
+	}
+	return "", nil, lastErr
+}
+
+// Get the layer by its name
+func (l *LayeredLoader) Layer(name string) (Loader, bool) {
+	for _, layer := range l.Layers {
+		if layer.Name == name {
+			return layer.Loader, true
+		}
+	}
+	return nil, false
+}
+
+func (l *LayeredLoader) String() string {
+	return fmt.Sprintf("LayeredLoader(layers: %s)", l.Layers)
+}
+
+var _ Loader = (*LayeredLoader)(nil)
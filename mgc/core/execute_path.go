@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Walk the children of root by their names, the last one must be an Executor
+func GetExecutorByPath(root Grouper, path ...string) (Executor, error) {
+	var child Descriptor = root
+	for _, name := range path {
+		grouper, ok := child.(Grouper)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a group", child.Name())
+		}
+
+		var err error
+		if child, err = grouper.GetChildByName(name); err != nil {
+			return nil, &ChainedError{Name: name, Err: err}
+		}
+	}
+
+	exec, ok := child.(Executor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an executor", child.Name())
+	}
+	return exec, nil
+}
+
+func fillSchemaDefaults(schema *Schema, values map[string]any) map[string]any {
+	result := make(map[string]any, len(values))
+	for name, value := range values {
+		result[name] = value
+	}
+	if schema == nil {
+		return result
+	}
+	for name, propRef := range schema.Properties {
+		if _, ok := result[name]; !ok && propRef.Value != nil && propRef.Value.Default != nil {
+			result[name] = propRef.Value.Default
+		}
+	}
+	return result
+}
+
+// Execute another executor of the SDK tree, from GrouperFromContext(), such as a static executor
+// using an OpenAPI operation.
+//
+// Missing parameters and configs are filled with their schema defaults, as done by
+// the CLI flags, so required values such as API version headers are given.
+func ExecuteByPath(ctx context.Context, path []string, parameters Parameters, configs Configs) (Result, error) {
+	root := GrouperFromContext(ctx)
+	if root == nil {
+		return nil, fmt.Errorf("programming error: context did not contain SDK Grouper information")
+	}
+
+	exec, err := GetExecutorByPath(root, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters = fillSchemaDefaults(exec.ParametersSchema(), parameters)
+	ctx, span := telemetry.StartExecutorSpan(ctx, strings.Join(path, " "), parameters, (*openapi3.Schema)(exec.ParametersSchema()))
+	result, err := exec.Execute(ctx, parameters, fillSchemaDefaults(exec.ConfigsSchema(), configs))
+	telemetry.EndSpan(span, err)
+	return result, err
+}
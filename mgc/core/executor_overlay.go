@@ -0,0 +1,31 @@
+package core
+
+// Placeholder for an Executor that decorates another one with the same name, provided by
+// a group merged afterwards. This allows static code to extend OpenAPI operations.
+//
+// When merged, the placeholder is replaced by the result of Overlay(). If there is no
+// executor to decorate, the placeholder is not added to the merged group.
+type ExecutorOverlay interface {
+	Descriptor
+	Overlay(base Executor) (Executor, error)
+}
+
+type executorOverlay struct {
+	SimpleDescriptor
+	overlay func(base Executor) (Executor, error)
+}
+
+// The placeholder itself is always internal, so it's not visible if the group is not merged.
+func NewExecutorOverlay(name string, overlay func(base Executor) (Executor, error)) ExecutorOverlay {
+	isInternal := true
+	return &executorOverlay{
+		SimpleDescriptor{DescriptorSpec{Name: name, IsInternal: &isInternal}},
+		overlay,
+	}
+}
+
+func (o *executorOverlay) Overlay(base Executor) (Executor, error) {
+	return o.overlay(base)
+}
+
+var _ ExecutorOverlay = (*executorOverlay)(nil)
@@ -8,6 +8,9 @@ import (
 	"os"
 	"syscall"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ClientRetryer struct {
@@ -62,6 +65,15 @@ func (r *ClientRetryer) cloneRequest(req *http.Request) *http.Request {
 	return clonedRequest
 }
 
+// Event in the current span, such as the executor one, as each attempt has its own span
+func traceRetry(req *http.Request, attempt int, reason string) {
+	trace.SpanFromContext(req.Context()).AddEvent("http.retry", trace.WithAttributes(
+		attribute.Int("attempt", attempt),
+		attribute.String("reason", reason),
+		attribute.String("http.request.method", req.Method),
+	))
+}
+
 func (r *ClientRetryer) RoundTrip(req *http.Request) (*http.Response, error) {
 	waitBeforeRetry := 100 * time.Millisecond
 	var res *http.Response
@@ -78,6 +90,7 @@ func (r *ClientRetryer) RoundTrip(req *http.Request) (*http.Response, error) {
 
 			if os.IsTimeout(err) {
 				logger().Infow("\n\n\nRequest timeout, retrying...\n\n\n", "attempt", i+1, "")
+				traceRetry(req, i+1, "timeout")
 				time.Sleep(waitBeforeRetry)
 				waitBeforeRetry = waitBeforeRetry * 2
 				continue
@@ -86,6 +99,7 @@ func (r *ClientRetryer) RoundTrip(req *http.Request) (*http.Response, error) {
 			if errors.As(err, &sysErr) {
 				if sysErr.Err == syscall.ECONNRESET {
 					logger().Infow("\n\n\nConn reset by peer! THIS IS A SERVER PROBLEM!!!\n\n\n", "attempt", i+1, "")
+					traceRetry(req, i+1, "connection reset")
 					time.Sleep(waitBeforeRetry)
 					waitBeforeRetry = waitBeforeRetry * 2
 					continue
@@ -95,6 +109,7 @@ func (r *ClientRetryer) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 		if res.StatusCode >= 500 {
 			logger().Infow("\n\n\nServer responded with fail, retrying...\n\n\n", "attempt", i+1, "status code", res.StatusCode, "")
+			traceRetry(req, i+1, res.Status)
 			time.Sleep(waitBeforeRetry)
 			waitBeforeRetry = waitBeforeRetry * 2
 			continue
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span and duration metric of each round trip, including retries and those done after a token
+// refresh, if it's the innermost transport. Nothing is done unless telemetry.Enabled()
+type ClientTracer struct {
+	Transport http.RoundTripper
+}
+
+func NewDefaultClientTracer(transport http.RoundTripper) *ClientTracer {
+	return &ClientTracer{
+		Transport: transport,
+	}
+}
+
+// Query strings may have signatures, such as presigned URLs
+func tracedURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	redacted.User = nil
+	return redacted.String()
+}
+
+func (t *ClientTracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if !telemetry.Enabled() {
+		return transport.RoundTrip(req)
+	}
+
+	metricAttrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+	}
+	spanAttrs := append([]attribute.KeyValue{
+		attribute.String("url.full", tracedURL(req.URL)),
+		attribute.Int64("http.request.body.size", req.ContentLength),
+	}, metricAttrs...)
+	if port := req.URL.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err == nil {
+			spanAttrs = append(spanAttrs, attribute.Int("server.port", n))
+		}
+	}
+
+	ctx, span := telemetry.Tracer().Start(
+		req.Context(),
+		req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		metricAttrs = append(metricAttrs, attribute.String("error.type", "network"))
+	} else {
+		statusAttr := attribute.Int("http.response.status_code", resp.StatusCode)
+		span.SetAttributes(statusAttr, attribute.Int64("http.response.body.size", resp.ContentLength))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+		metricAttrs = append(metricAttrs, statusAttr)
+	}
+	telemetry.RecordHTTPDuration(ctx, time.Since(start), metricAttrs...)
+
+	return resp, err
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
 )
 
 type refreshTokenFn func(ctx context.Context) (string, error)
@@ -62,7 +64,10 @@ func (t *RefreshLogger) RoundTrip(req *http.Request) (*http.Response, error) {
 		return resp, err
 	}
 
-	token, rErr := t.RefreshFn(req.Context())
+	// The refresh request is a child of this span
+	ctx, span := telemetry.Tracer().Start(req.Context(), "token refresh")
+	token, rErr := t.RefreshFn(ctx)
+	telemetry.EndSpan(span, rErr)
 	if rErr != nil {
 		return resp, fmt.Errorf("Unauthorized and failed to refresh token. Please, login again: %w", rErr)
 	}
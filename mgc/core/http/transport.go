@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Clone of DefaultTransport() with the proxy, certificates and resolution of the workspace config.
+// DefaultTransport() itself is returned if there is nothing to change
+func NewTransport(cfg config.TransportConfig) (http.RoundTripper, error) {
+	if cfg.IsZero() {
+		return DefaultTransport(), nil
+	}
+
+	transport := DefaultTransport().(*http.Transport).Clone()
+
+	if cfg.Proxy != "" || cfg.NoProxy != "" {
+		proxyFunc, err := newProxyFunc(cfg.Proxy, cfg.NoProxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxyFunc
+	}
+
+	tlsConfig, err := newTLSConfig(transport.TLSClientConfig, cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if len(cfg.Resolve) > 0 {
+		transport.DialContext = newResolvingDialContext(transport.DialContext, cfg.Resolve)
+	}
+
+	return transport, nil
+}
+
+// An explicit proxy is used for both http and https, the environment is still used otherwise
+func newProxyFunc(proxy, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if proxy != "" {
+		if u, err := url.Parse(proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid %s %q: it must be an URL such as http://proxy.internal:3128", config.ProxyKey, proxy)
+		}
+		proxyConfig.HTTPProxy = proxy
+		proxyConfig.HTTPSProxy = proxy
+	}
+	if noProxy != "" {
+		proxyConfig.NoProxy = noProxy
+	}
+
+	proxyFunc := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+func newTLSConfig(base *tls.Config, cfg config.TransportConfig) (*tls.Config, error) {
+	if cfg.CABundle == "" && cfg.ClientCert == "" && cfg.ClientKey == "" {
+		return base, nil
+	}
+
+	var tlsConfig *tls.Config
+	if base != nil {
+		tlsConfig = base.Clone()
+	} else {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", config.CABundleKey, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s %q", config.CABundleKey, cfg.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("both %s and %s must be given", config.ClientCertKey, config.ClientKeyKey)
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Like curl's --resolve, "host:port" keys take precedence over "host". Values without a port
+// keep the requested one. The TLS server name is still the requested host
+func newResolvingDialContext(
+	dial func(ctx context.Context, network, addr string) (net.Conn, error),
+	resolve map[string]string,
+) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dial(ctx, network, resolveAddress(resolve, addr))
+	}
+}
+
+func resolveAddress(resolve map[string]string, addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	target, ok := resolve[addr]
+	if !ok {
+		if target, ok = resolve[host]; !ok {
+			return addr
+		}
+	}
+
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		target = net.JoinHostPort(target, port)
+	}
+	logger().Debugw("resolved address from config", "address", addr, "target", target)
+	return target
+}
+
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// Every request fails with the error. Used when the transport can't be created, since the
+// clients are created lazily where errors can't be returned
+func NewErrorTransport(err error) http.RoundTripper {
+	return errorTransport{err}
+}
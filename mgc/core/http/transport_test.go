@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+)
+
+func TestResolveAddress(t *testing.T) {
+	resolve := map[string]string{
+		"api.example.com:443": "10.0.0.1",
+		"api.example.com":     "10.0.0.2",
+		"other.example.com":   "10.0.0.3:8443",
+	}
+	cases := map[string]string{
+		"api.example.com:443":   "10.0.0.1:443",
+		"api.example.com:80":    "10.0.0.2:80",
+		"other.example.com:443": "10.0.0.3:8443",
+		"unknown.com:443":       "unknown.com:443",
+	}
+	for addr, expected := range cases {
+		if got := resolveAddress(resolve, addr); got != expected {
+			t.Errorf("resolveAddress(%q): expected %q, got %q", addr, expected, got)
+		}
+	}
+}
+
+func TestNewTransportProxy(t *testing.T) {
+	transport, err := NewTransport(config.TransportConfig{
+		Proxy:   "http://proxy.internal:3128",
+		NoProxy: ".internal.example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	proxyFunc := transport.(*http.Transport).Proxy
+	req, _ := http.NewRequest(http.MethodGet, "https://api.magalu.cloud/v1", nil)
+	if u, err := proxyFunc(req); err != nil || u == nil || u.Host != "proxy.internal:3128" {
+		t.Errorf("expected the configured proxy, got %v (%v)", u, err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://s3.internal.example.com/bucket", nil)
+	if u, err := proxyFunc(req); err != nil || u != nil {
+		t.Errorf("expected no proxy, got %v (%v)", u, err)
+	}
+}
+
+func TestNewTransportCABundleAndResolve(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caBundle, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	transport, err := NewTransport(config.TransportConfig{
+		CABundle: caBundle,
+		// the test certificate is valid for example.com
+		Resolve: map[string]string{"example.com": "127.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get("https://example.com:" + port)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+	}
+}
+
+func TestNewTransportErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]config.TransportConfig{
+		"missing CA bundle":  {CABundle: filepath.Join(dir, "missing.pem")},
+		"invalid CA bundle":  {CABundle: notPEM},
+		"client cert only":   {ClientCert: notPEM},
+		"invalid client key": {ClientCert: notPEM, ClientKey: notPEM},
+		"invalid proxy":      {Proxy: "proxy.internal"},
+	}
+	for name, cfg := range cases {
+		if _, err := NewTransport(cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if transport, err := NewTransport(config.TransportConfig{}); err != nil || transport != DefaultTransport() {
+		t.Errorf("expected DefaultTransport() without settings, got %v (%v)", transport, err)
+	}
+}
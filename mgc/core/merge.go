@@ -32,6 +32,44 @@ func mergeAfter(toMerge []Grouper, target Grouper, start int) (result []Grouper)
 	return result
 }
 
+func overlayAfter(toMerge []Grouper, overlay ExecutorOverlay, start int) (Executor, error) {
+	name := overlay.Name()
+
+	for i := start; i < len(toMerge); i += 1 {
+		child, err := toMerge[i].GetChildByName(name)
+		if err != nil {
+			continue
+		}
+		if base, ok := child.(Executor); ok {
+			return overlay.Overlay(base)
+		}
+	}
+	return nil, nil
+}
+
+// The first group gives the spec. Static groups that only add commands to a group of
+// another source, such as the kubeconfig ones in kubernetes, declare just their name,
+// so in that case the texts are taken from the others. Other specs are kept as they are.
+func mergedSpec(merged []Grouper) DescriptorSpec {
+	spec := merged[0].DescriptorSpec()
+	if spec.Summary != "" || spec.Description != "" || spec.Version != "" {
+		return spec
+	}
+	for _, g := range merged[1:] {
+		other := g.DescriptorSpec()
+		if spec.Summary == "" {
+			spec.Summary = other.Summary
+		}
+		if spec.Description == "" {
+			spec.Description = other.Description
+		}
+		if spec.Version == "" {
+			spec.Version = other.Version
+		}
+	}
+	return spec
+}
+
 func createChildren(createToMerge func() []Grouper) (children []Descriptor, err error) {
 	toMerge := createToMerge()
 	children = make([]Descriptor, 0)
@@ -48,8 +86,18 @@ func createChildren(createToMerge func() []Grouper) (children []Descriptor, err
 			if group, ok := child.(Grouper); ok {
 				merged := mergeAfter(toMerge, group, i+1)
 				if len(merged) > 1 {
-					child = NewMergeGroup(group.DescriptorSpec(), func() []Grouper { return merged })
+					child = NewMergeGroup(mergedSpec(merged), func() []Grouper { return merged })
+				}
+			} else if overlay, ok := child.(ExecutorOverlay); ok {
+				exec, err := overlayAfter(toMerge, overlay, i+1)
+				if err != nil {
+					return false, &ChainedError{Name: name, Err: err}
+				}
+				if exec == nil {
+					logger().Debugw("executor overlay has nothing to decorate", "name", name)
+					return true, nil
 				}
+				child = exec
 			}
 
 			children = append(children, child)
@@ -0,0 +1,39 @@
+package core
+
+import "testing"
+
+func TestMergeGroupSpecs(t *testing.T) {
+	group := func(spec DescriptorSpec) Grouper {
+		return NewStaticGroup(spec, func() []Descriptor { return nil })
+	}
+	root := func(children ...Descriptor) Grouper {
+		return NewStaticGroup(DescriptorSpec{Name: "root"}, func() []Descriptor { return children })
+	}
+
+	merged := NewMergeGroup(DescriptorSpec{Name: "products"}, func() []Grouper {
+		return []Grouper{
+			root(
+				group(DescriptorSpec{Name: "extended"}),
+				group(DescriptorSpec{Name: "complete", Description: "Static description"}),
+			),
+			root(
+				group(DescriptorSpec{Name: "extended", Summary: "Summary", Description: "Description", Version: "v1"}),
+				group(DescriptorSpec{Name: "complete", Summary: "Summary", Description: "Other description", Version: "v1"}),
+			),
+		}
+	})
+
+	for name, expected := range map[string]DescriptorSpec{
+		"extended": {Name: "extended", Summary: "Summary", Description: "Description", Version: "v1"},
+		"complete": {Name: "complete", Description: "Static description"},
+	} {
+		child, err := merged.GetChildByName(name)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		spec := child.DescriptorSpec()
+		if spec.Summary != expected.Summary || spec.Description != expected.Description || spec.Version != expected.Version {
+			t.Errorf("%s: expected spec %+v, got %+v", name, expected, spec)
+		}
+	}
+}
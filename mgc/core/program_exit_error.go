@@ -0,0 +1,14 @@
+package core
+
+import "fmt"
+
+// A program run by the executor, such as the system ssh, exited with a failure.
+// The CLI exits with the same code
+type ProgramExitError struct {
+	Program string
+	Code    int
+}
+
+func (e ProgramExitError) Error() string {
+	return fmt.Sprintf("%s exited with code %d", e.Program, e.Code)
+}
@@ -0,0 +1,27 @@
+package progress_report
+
+import "context"
+
+const itemReporterKey contextKey = "magalu.cli/core/progressreport/items"
+
+// Report of a single item of a units progress, such as a file being uploaded,
+// with its error, if it failed
+type ReportItem func(msg, item string, reportErr error)
+
+// Insert an item reporting function in the context. It's optional, the progress
+// itself is always given to the ReportProgress in the context
+func NewItemContext(ctx context.Context, reportItem ReportItem) context.Context {
+	return context.WithValue(ctx, itemReporterKey, reportItem)
+}
+
+// Retrieves the item reporting function from the context
+func ItemFromContext(ctx context.Context) ReportItem {
+	ri, ok := ctx.Value(itemReporterKey).(ReportItem)
+	if !ok {
+		return dummyItemReport
+	}
+	return ri
+}
+
+func dummyItemReport(msg, item string, reportErr error) {
+}
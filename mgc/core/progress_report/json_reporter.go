@@ -0,0 +1,158 @@
+package progress_report
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Minimum interval between progress events of the same report. Start and finish are always emitted
+var JSONProgressInterval = 200 * time.Millisecond
+
+const (
+	JSONEventStart      = "start"
+	JSONEventProgress   = "progress"
+	JSONEventItemDone   = "item_done"
+	JSONEventItemFailed = "item_failed"
+	JSONEventFinish     = "finish"
+)
+
+// Progress events, written as a single JSON line each
+type JSONProgressEvent struct {
+	Time  string `json:"time"`
+	Event string `json:"event"`
+	Name  string `json:"name"`
+	// "bytes" or "items"
+	Units string `json:"units"`
+	Done  uint64 `json:"done"`
+	Total uint64 `json:"total"`
+	// Units per second, since the start
+	Rate float64 `json:"rate"`
+	// Only on finish
+	Elapsed float64 `json:"elapsed,omitempty"`
+	Status  string  `json:"status,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Item events, reported by UnitsReporter.ReportItem()
+type JSONItemEvent struct {
+	Time  string `json:"time"`
+	Event string `json:"event"`
+	Name  string `json:"name"`
+	Item  string `json:"item"`
+	Error string `json:"error,omitempty"`
+}
+
+type jsonReportState struct {
+	start     time.Time
+	lastEvent time.Time
+	finished  bool
+}
+
+// Writes the progress as newline delimited JSON (NDJSON), so it can be consumed by other programs.
+//
+// Use ReportProgress() with NewContext() and ReportItem() with NewItemContext()
+type JSONReporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	reports map[string]*jsonReportState
+	now     func() time.Time
+}
+
+func NewJSONReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{
+		encoder: json.NewEncoder(w),
+		reports: map[string]*jsonReportState{},
+		now:     time.Now,
+	}
+}
+
+func jsonUnits(units Units) string {
+	if units == UnitsBytes {
+		return "bytes"
+	}
+	return "items"
+}
+
+func (r *JSONReporter) emit(event any) {
+	// nothing to do if the output is gone, the progress is not essential
+	_ = r.encoder.Encode(event)
+}
+
+func (r *JSONReporter) ReportProgress(msg string, done, total uint64, units Units, reportErr error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if errors.Is(reportErr, io.EOF) {
+		reportErr = nil
+	}
+
+	now := r.now()
+	event := JSONProgressEvent{
+		Time:  now.UTC().Format(time.RFC3339Nano),
+		Name:  msg,
+		Units: jsonUnits(units),
+		Done:  done,
+		Total: total,
+	}
+
+	state, ok := r.reports[msg]
+	// errors may be reported after the progress is done, these are given as another finish
+	if !ok || (state.finished && (reportErr == nil || errors.Is(reportErr, ErrorProgressDone))) {
+		state = &jsonReportState{start: now, lastEvent: now}
+		r.reports[msg] = state
+		event.Event = JSONEventStart
+		r.emit(event)
+		if reportErr == nil {
+			return
+		}
+	}
+
+	elapsed := now.Sub(state.start).Seconds()
+	if elapsed > 0 {
+		event.Rate = float64(done) / elapsed
+	}
+
+	if reportErr != nil {
+		event.Event = JSONEventFinish
+		event.Elapsed = elapsed
+		if errors.Is(reportErr, ErrorProgressDone) {
+			event.Status = "done"
+		} else {
+			event.Status = "failed"
+			event.Error = reportErr.Error()
+		}
+		state.finished = true
+		state.lastEvent = now
+		r.emit(event)
+		return
+	}
+
+	if done < total && now.Sub(state.lastEvent) < JSONProgressInterval {
+		return
+	}
+
+	event.Event = JSONEventProgress
+	state.lastEvent = now
+	r.emit(event)
+}
+
+func (r *JSONReporter) ReportItem(msg, item string, reportErr error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	event := JSONItemEvent{
+		Time:  now.UTC().Format(time.RFC3339Nano),
+		Event: JSONEventItemDone,
+		Name:  msg,
+		Item:  item,
+	}
+	if reportErr != nil {
+		event.Event = JSONEventItemFailed
+		event.Error = reportErr.Error()
+	}
+	r.emit(event)
+}
@@ -0,0 +1,87 @@
+package progress_report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeJSONEvents(t *testing.T, data string) []map[string]any {
+	var events []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		event := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestJSONReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewJSONReporter(buf)
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := start
+	r.now = func() time.Time { return now }
+
+	r.ReportProgress("upload", 0, 100, UnitsBytes, nil)
+	now = now.Add(time.Millisecond)
+	r.ReportProgress("upload", 10, 100, UnitsBytes, nil) // throttled
+	now = start.Add(time.Second)
+	r.ReportProgress("upload", 50, 100, UnitsBytes, nil)
+	r.ReportItem("upload", "a.txt", nil)
+	r.ReportItem("upload", "b.txt", errors.New("denied"))
+	now = start.Add(2 * time.Second)
+	r.ReportProgress("upload", 100, 100, UnitsBytes, ErrorProgressDone)
+
+	events := decodeJSONEvents(t, buf.String())
+	expected := []map[string]any{
+		{"time": "2024-01-02T03:04:05Z", "event": "start", "name": "upload", "units": "bytes", "done": 0.0, "total": 100.0, "rate": 0.0},
+		{"time": "2024-01-02T03:04:06Z", "event": "progress", "name": "upload", "units": "bytes", "done": 50.0, "total": 100.0, "rate": 50.0},
+		{"time": "2024-01-02T03:04:06Z", "event": "item_done", "name": "upload", "item": "a.txt"},
+		{"time": "2024-01-02T03:04:06Z", "event": "item_failed", "name": "upload", "item": "b.txt", "error": "denied"},
+		{"time": "2024-01-02T03:04:07Z", "event": "finish", "name": "upload", "units": "bytes", "done": 100.0, "total": 100.0, "rate": 50.0, "elapsed": 2.0, "status": "done"},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %s", len(expected), len(events), buf.String())
+	}
+	for i, event := range events {
+		for k, v := range expected[i] {
+			if event[k] != v {
+				t.Errorf("event %d: expected %s=%v, got %v", i, k, v, event[k])
+			}
+		}
+		if len(event) != len(expected[i]) {
+			t.Errorf("event %d: unexpected fields %v", i, event)
+		}
+	}
+}
+
+func TestJSONReporterErrorAfterDone(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewJSONReporter(buf)
+
+	r.ReportProgress("copy", 0, 1, UnitsNone, nil)
+	r.ReportProgress("copy", 2, 2, UnitsNone, ErrorProgressDone)
+	r.ReportProgress("copy", 2, 2, UnitsNone, errors.New("failed to copy"))
+	r.ReportProgress("copy", 0, 1, UnitsNone, nil)
+
+	events := decodeJSONEvents(t, buf.String())
+	var got []string
+	for _, event := range events {
+		got = append(got, event["event"].(string)+":"+event["units"].(string))
+	}
+	expected := "start:items finish:items finish:items start:items"
+	if strings.Join(got, " ") != expected {
+		t.Fatalf("expected %q, got %q", expected, strings.Join(got, " "))
+	}
+	if events[2]["status"] != "failed" || events[2]["error"] != "failed to copy" {
+		t.Errorf("expected failed finish, got %v", events[2])
+	}
+}
@@ -0,0 +1,159 @@
+package progress_report
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Token bucket limiting the transfer rate, in bytes per second. It may be shared by
+// many readers and writers, so their combined rate is limited.
+//
+// Up to one second worth of bytes may be transferred at once (burst).
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func NewTokenBucket(bytesPerSecond uint64) *TokenBucket {
+	rate := float64(max(bytesPerSecond, 1))
+	return &TokenBucket{
+		rate:   rate,
+		burst:  rate,
+		tokens: rate,
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Largest amount that can be taken at once, readers and writers shouldn't transfer more than this per call
+func (b *TokenBucket) Burst() int {
+	return int(b.burst)
+}
+
+// Take n tokens, if not available wait for them to be refilled
+func (b *TokenBucket) WaitN(ctx context.Context, n int) error {
+	remaining := float64(n)
+	for remaining > 0 {
+		take := min(remaining, b.burst)
+
+		b.mu.Lock()
+		now := b.now()
+		if !b.last.IsZero() {
+			b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		}
+		b.last = now
+		// tokens may go negative, later callers then wait for the debt to be paid
+		b.tokens -= take
+		wait := time.Duration(0)
+		if b.tokens < 0 {
+			wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+		}
+		b.mu.Unlock()
+
+		if wait > 0 {
+			if err := b.sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+		remaining -= take
+	}
+	return nil
+}
+
+type limitedReader struct {
+	ctx    context.Context
+	parent io.Reader
+	bucket *TokenBucket
+}
+
+// Wraps an io.Reader, such as the one from NewReporterReader(), in another Reader
+// which doesn't read faster than the bucket allows
+func NewLimitedReader(ctx context.Context, parent io.Reader, bucket *TokenBucket) *limitedReader {
+	return &limitedReader{ctx: ctx, parent: parent, bucket: bucket}
+}
+
+func (lr *limitedReader) Unwrap() io.Reader {
+	return lr.parent
+}
+
+func (lr *limitedReader) Read(p []byte) (n int, err error) {
+	if len(p) > lr.bucket.Burst() {
+		p = p[:lr.bucket.Burst()]
+	}
+	n, err = lr.parent.Read(p)
+	if n > 0 {
+		if waitErr := lr.bucket.WaitN(lr.ctx, n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+	return
+}
+
+func (lr *limitedReader) Close() error {
+	if closer, ok := lr.parent.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+var _ io.ReadCloser = (*limitedReader)(nil)
+
+type limitedWriter struct {
+	ctx    context.Context
+	parent io.Writer
+	bucket *TokenBucket
+}
+
+// Wraps an io.Writer, such as the one from NewReporterWriter(), in another Writer
+// which doesn't write faster than the bucket allows
+func NewLimitedWriter(ctx context.Context, parent io.Writer, bucket *TokenBucket) *limitedWriter {
+	return &limitedWriter{ctx: ctx, parent: parent, bucket: bucket}
+}
+
+func (lw *limitedWriter) Unwrap() io.Writer {
+	return lw.parent
+}
+
+func (lw *limitedWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p[:min(len(p), lw.bucket.Burst())]
+		if err = lw.bucket.WaitN(lw.ctx, len(chunk)); err != nil {
+			return
+		}
+		var written int
+		written, err = lw.parent.Write(chunk)
+		n += written
+		if err != nil {
+			return
+		}
+		p = p[len(chunk):]
+	}
+	return
+}
+
+func (lw *limitedWriter) Close() error {
+	if closer, ok := lw.parent.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+var _ io.WriteCloser = (*limitedWriter)(nil)
@@ -0,0 +1,77 @@
+package progress_report
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFakeClockBucket(bytesPerSecond uint64) (*TokenBucket, *time.Duration) {
+	b := NewTokenBucket(bytesPerSecond)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	slept := new(time.Duration)
+	b.now = func() time.Time { return now }
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		*slept += d
+		now = now.Add(d)
+		return nil
+	}
+	return b, slept
+}
+
+func TestTokenBucketWaitN(t *testing.T) {
+	b, slept := newFakeClockBucket(1000)
+	ctx := context.Background()
+
+	// the initial burst is available at once
+	if err := b.WaitN(ctx, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if *slept != 0 {
+		t.Fatalf("expected no wait for the burst, waited %s", *slept)
+	}
+
+	if err := b.WaitN(ctx, 2500); err != nil {
+		t.Fatal(err)
+	}
+	if *slept != 2500*time.Millisecond {
+		t.Fatalf("expected 2.5s wait, waited %s", *slept)
+	}
+}
+
+func TestTokenBucketCanceled(t *testing.T) {
+	b := NewTokenBucket(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = b.WaitN(ctx, 1)
+	if err := b.WaitN(ctx, 1); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLimitedReaderWriter(t *testing.T) {
+	b, slept := newFakeClockBucket(100)
+	ctx := context.Background()
+	data := strings.Repeat("x", 1000)
+
+	// both share the same bucket
+	out := &bytes.Buffer{}
+	reader := NewLimitedReader(ctx, strings.NewReader(data), b)
+	writer := NewLimitedWriter(ctx, out, b)
+	n, err := io.Copy(writer, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) || out.String() != data {
+		t.Fatalf("expected %d bytes copied, got %d", len(data), n)
+	}
+
+	// 2000 bytes at 100 bytes/s, the first 100 are the burst
+	if *slept != 19*time.Second {
+		t.Fatalf("expected 19s wait, waited %s", *slept)
+	}
+}
@@ -16,6 +16,7 @@ type UnitsReporter struct {
 	name           string
 	total          uint64
 	reportProgress ReportProgress
+	reportItem     ReportItem
 	reportChan     chan unitsProgressReport
 }
 
@@ -28,6 +29,7 @@ func NewUnitsReporter(
 		name:           name,
 		total:          total,
 		reportProgress: FromContext(ctx),
+		reportItem:     ItemFromContext(ctx),
 	}
 }
 
@@ -46,6 +48,16 @@ func (r *UnitsReporter) Report(units uint64, total uint64, err error) {
 	r.reportChan <- unitsProgressReport{units: units, total: total, err: err}
 }
 
+// Report a single unit progressed, identified by item, and its error, if any.
+// Nil-pointer safe
+func (r *UnitsReporter) ReportItem(item string, err error) {
+	if r == nil {
+		return
+	}
+	r.reportItem(r.name, item, err)
+	r.Report(1, 0, err)
+}
+
 func (r *UnitsReporter) End() {
 	if r.reportChan == nil {
 		return
@@ -41,6 +41,55 @@ func (schema Schema) MarshalJSON() ([]byte, error) {
 	return openapi3.Schema(schema).MarshalJSON()
 }
 
+// Copy of the schema with all references replaced by their values, as only the reference
+// would be serialized otherwise. Recursive schemas are cut with an empty schema.
+func InlineRefs(s *Schema) *Schema {
+	return (*Schema)(inlineRefs((*openapi3.Schema)(s), map[*openapi3.Schema]bool{}))
+}
+
+func inlineRefs(s *openapi3.Schema, visiting map[*openapi3.Schema]bool) *openapi3.Schema {
+	if s == nil {
+		return nil
+	}
+	if visiting[s] {
+		return &openapi3.Schema{}
+	}
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	inlineRef := func(ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+		if ref == nil {
+			return nil
+		}
+		return &openapi3.SchemaRef{Value: inlineRefs(ref.Value, visiting)}
+	}
+	inlineRefList := func(refs openapi3.SchemaRefs) openapi3.SchemaRefs {
+		if refs == nil {
+			return nil
+		}
+		result := make(openapi3.SchemaRefs, len(refs))
+		for i, ref := range refs {
+			result[i] = inlineRef(ref)
+		}
+		return result
+	}
+
+	c := *s
+	c.OneOf = inlineRefList(s.OneOf)
+	c.AnyOf = inlineRefList(s.AnyOf)
+	c.AllOf = inlineRefList(s.AllOf)
+	c.Not = inlineRef(s.Not)
+	c.Items = inlineRef(s.Items)
+	c.AdditionalProperties.Schema = inlineRef(s.AdditionalProperties.Schema)
+	if s.Properties != nil {
+		c.Properties = make(openapi3.Schemas, len(s.Properties))
+		for name, ref := range s.Properties {
+			c.Properties[name] = inlineRef(ref)
+		}
+	}
+	return &c
+}
+
 func NewSchemaRef(ref string, schema *Schema) *openapi3.SchemaRef {
 	return openapi3.NewSchemaRef(ref, (*openapi3.Schema)(schema))
 }
@@ -196,6 +196,13 @@ func convertJsonSchemaToOpenAPISchema(input *jsonschema.Schema, refResolver *ref
 		convertLogger().Debugw("will add x-contentSchema extension")
 		addExtensions(output, "x-contentSchema", input.ContentSchema)
 	}
+	// 'jsonschema_extras' struct tags, only extensions are kept
+	for name, value := range input.Extras {
+		if strings.HasPrefix(name, "x-") {
+			convertLogger().Debugw("will add extension", "name", name)
+			addExtensions(output, name, value)
+		}
+	}
 
 	convertLogger().Debugw("finished converting 'jsonschema.Schema' to 'kin-openapi.Schema'", "jsonschema", input, "kin-openapi", output)
 
@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redactedValue = "[REDACTED]"
+
+// Parameters with these words in their names are considered secrets, in addition to those
+// with the "password" format or writeOnly in their schemas
+var sensitiveNameParts = []string{"password", "secret", "token", "private_key", "privatekey", "passphrase", "credential", "user_data"}
+
+func isSensitiveName(name string) bool {
+	lower := strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return strings.HasSuffix(lower, "_key") && !strings.HasSuffix(lower, "ssh_key")
+}
+
+func isSensitiveSchema(s *openapi3.Schema) bool {
+	return s != nil && (s.Format == "password" || s.WriteOnly)
+}
+
+// Copy of the values with the secrets replaced, objects are handled recursively
+func RedactParameters(values map[string]any, schema *openapi3.Schema) map[string]any {
+	result := make(map[string]any, len(values))
+	for name, value := range values {
+		var propSchema *openapi3.Schema
+		if schema != nil {
+			if ref := schema.Properties[name]; ref != nil {
+				propSchema = ref.Value
+			}
+		}
+
+		if value == nil {
+			result[name] = nil
+		} else if isSensitiveName(name) || isSensitiveSchema(propSchema) {
+			result[name] = redactedValue
+		} else if m, ok := value.(map[string]any); ok {
+			result[name] = RedactParameters(m, propSchema)
+		} else {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// Span of an executor, named after its command path, such as "virtual-machine instances create".
+// It must be finished with EndSpan()
+func StartExecutorSpan(ctx context.Context, commandPath string, parameters map[string]any, schema *openapi3.Schema) (context.Context, trace.Span) {
+	if !Enabled() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("mgc.command", commandPath)}
+	if data, err := json.Marshal(RedactParameters(parameters, schema)); err == nil {
+		attrs = append(attrs, attribute.String("mgc.parameters", string(data)))
+	}
+
+	return Tracer().Start(ctx, commandPath, trace.WithAttributes(attrs...))
+}
+
+// Record the error, if any, and end the span started by this package
+func EndSpan(span trace.Span, err error) {
+	if !Enabled() {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,49 @@
+package telemetry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestRedactParameters(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("pin", &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "password"}).
+		WithProperty("nested", openapi3.NewObjectSchema().
+			WithProperty("code", &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, WriteOnly: true}))
+
+	values := map[string]any{
+		"name":       "my-vm",
+		"pin":        "1234",
+		"password":   "hunter2",
+		"api-key":    "abc",
+		"ssh_key":    "ssh-ed25519 AAAA",
+		"user-data":  "IyEvYmluL3No",
+		"nested":     map[string]any{"code": "42", "id": "some-id", "access_token": "t"},
+		"unset":      nil,
+		"key-pair":   map[string]any{"name": "default"},
+		"port-count": 2,
+	}
+	expected := map[string]any{
+		"name":       "my-vm",
+		"pin":        redactedValue,
+		"password":   redactedValue,
+		"api-key":    redactedValue,
+		"ssh_key":    "ssh-ed25519 AAAA",
+		"user-data":  redactedValue,
+		"nested":     map[string]any{"code": redactedValue, "id": "some-id", "access_token": redactedValue},
+		"unset":      nil,
+		"key-pair":   map[string]any{"name": "default"},
+		"port-count": 2,
+	}
+
+	got := RedactParameters(values, schema)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+	if values["password"] != "hunter2" {
+		t.Errorf("original values were changed: %#v", values)
+	}
+}
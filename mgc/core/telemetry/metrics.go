@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	DirectionUpload   = "upload"
+	DirectionDownload = "download"
+)
+
+type instruments struct {
+	httpDuration       metric.Float64Histogram
+	objectStorageBytes metric.Int64Counter
+}
+
+// Created once telemetry is enabled, so they're bound to the configured provider
+var getInstruments = utils.NewLazyLoader(func() *instruments {
+	meter := Meter()
+	result := &instruments{}
+	var err error
+
+	result.httpDuration, err = meter.Float64Histogram(
+		"http.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of HTTP client requests, each retry is a request"),
+	)
+	if err != nil {
+		logger().Debugw("unable to create instrument", "error", err)
+	}
+
+	result.objectStorageBytes, err = meter.Int64Counter(
+		"mgc.object_storage.transferred",
+		metric.WithUnit("By"),
+		metric.WithDescription("Bytes sent and received by object storage requests"),
+	)
+	if err != nil {
+		logger().Debugw("unable to create instrument", "error", err)
+	}
+
+	return result
+})
+
+func RecordHTTPDuration(ctx context.Context, duration time.Duration, attrs ...attribute.KeyValue) {
+	if !Enabled() {
+		return
+	}
+	if h := getInstruments().httpDuration; h != nil {
+		h.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+	}
+}
+
+type countingReader struct {
+	io.ReadCloser
+	ctx       context.Context
+	direction string
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if c := getInstruments().objectStorageBytes; c != nil {
+			c.Add(r.ctx, int64(n), metric.WithAttributes(attribute.String("direction", r.direction)))
+		}
+	}
+	return n, err
+}
+
+func (r *countingReader) Unwrap() io.ReadCloser {
+	return r.ReadCloser
+}
+
+// Count the bytes read as transferred by object storage in the given direction, see DirectionUpload
+func CountObjectStorageBytes(ctx context.Context, r io.ReadCloser, direction string) io.ReadCloser {
+	if !Enabled() || r == nil {
+		return r
+	}
+	return &countingReader{ReadCloser: r, ctx: ctx, direction: direction}
+}
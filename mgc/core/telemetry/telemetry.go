@@ -0,0 +1,180 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenTelemetry traces and metrics. They're disabled unless Setup() is called with an exporter,
+// then the global OpenTelemetry providers are used.
+
+const ConfigKey = "telemetry"
+
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+const instrumentationName = "github.com/MagaluCloud/magalu/mgc"
+
+type Config struct {
+	Exporter string `json:"exporter,omitempty" jsonschema:"enum=none,enum=otlp,enum=file,default=none" jsonschema_description:"Where to export traces and metrics: 'otlp' sends them over OTLP/HTTP to the endpoint, 'file' appends them as JSON to the file"`
+	// Base URL, the signal paths such as /v1/traces are appended. Defaults to $OTEL_EXPORTER_OTLP_ENDPOINT
+	Endpoint string            `json:"endpoint,omitempty" jsonschema_description:"OTLP/HTTP base URL, such as http://localhost:4318. Defaults to $OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Headers  map[string]string `json:"headers,omitempty" jsonschema_description:"Headers sent to the OTLP endpoint, such as authentication"`
+	File     string            `json:"file,omitempty" jsonschema_description:"File used by the 'file' exporter"`
+}
+
+var enabled atomic.Bool
+
+// Whether Setup() was done, used to avoid the instrumentation overhead otherwise
+func Enabled() bool {
+	return enabled.Load()
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+func Meter() metric.Meter {
+	return otel.Meter(instrumentationName)
+}
+
+// $OTEL_RESOURCE_ATTRIBUTES and $OTEL_SERVICE_NAME take precedence, so pipelines may identify themselves
+func newResource(version string) (*resource.Resource, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(
+			attribute.String("service.name", "mgc"),
+			attribute.String("service.version", version),
+		),
+	)
+	if err != nil {
+		return res, err
+	}
+	return resource.Merge(res, resource.Environment())
+}
+
+func newOTLPExporters(ctx context.Context, cfg Config) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	var traceOptions []otlptracehttp.Option
+	var metricOptions []otlpmetrichttp.Option
+	if cfg.Endpoint != "" {
+		base := strings.TrimRight(cfg.Endpoint, "/")
+		traceOptions = append(traceOptions, otlptracehttp.WithEndpointURL(base+"/v1/traces"))
+		metricOptions = append(metricOptions, otlpmetrichttp.WithEndpointURL(base+"/v1/metrics"))
+	}
+	if len(cfg.Headers) > 0 {
+		traceOptions = append(traceOptions, otlptracehttp.WithHeaders(cfg.Headers))
+		metricOptions = append(metricOptions, otlpmetrichttp.WithHeaders(cfg.Headers))
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx, traceOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return traceExporter, metricExporter, nil
+}
+
+func newFileExporters(cfg Config) (sdktrace.SpanExporter, sdkmetric.Exporter, io.Closer, error) {
+	if cfg.File == "" {
+		return nil, nil, nil, fmt.Errorf("the %q exporter requires a file", ExporterFile)
+	}
+
+	f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	traceExporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+	if err != nil {
+		f.Close()
+		return nil, nil, nil, err
+	}
+	metricExporter, err := stdoutmetric.New(stdoutmetric.WithWriter(f))
+	if err != nil {
+		f.Close()
+		return nil, nil, nil, err
+	}
+	return traceExporter, metricExporter, f, nil
+}
+
+func noopShutdown(context.Context) error {
+	return nil
+}
+
+// Configure the global providers with the exporter. The returned function must be called before
+// the program exits, so pending spans and metrics are exported.
+func Setup(ctx context.Context, cfg Config, version string) (shutdown func(context.Context) error, err error) {
+	var traceExporter sdktrace.SpanExporter
+	var metricExporter sdkmetric.Exporter
+	var closer io.Closer
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return noopShutdown, nil
+	case ExporterOTLP:
+		traceExporter, metricExporter, err = newOTLPExporters(ctx, cfg)
+	case ExporterFile:
+		traceExporter, metricExporter, closer, err = newFileExporters(cfg)
+	default:
+		err = fmt.Errorf("unknown exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return noopShutdown, err
+	}
+
+	res, err := newResource(version)
+	if err != nil {
+		logger().Debugw("unable to create the telemetry resource", "error", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger().Debugw("telemetry error", "error", err)
+	}))
+	enabled.Store(true)
+
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		err := errors.Join(
+			tracerProvider.Shutdown(ctx),
+			meterProvider.Shutdown(ctx),
+		)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
 )
 
 type TerminatorExecutor interface {
@@ -54,34 +56,50 @@ func (o *executeTerminatorWithCheck) executeUntilTermination(context context.Con
 		}
 	}
 
+	// Each attempt is reported, so the wait can be followed like other progresses
+	reportProgress := progress_report.FromContext(context)
+	reportMsg := fmt.Sprintf("Waiting for %q to terminate", o.Name())
+	maxRetries := uint64(o.maxRetries)
+	reportProgress(reportMsg, 0, maxRetries, progress_report.UnitsNone, nil)
+
 	for i := 0; i < o.maxRetries; i++ {
+		attempt := uint64(i + 1)
 		result, err = exec()
 		if err != nil {
+			reportProgress(reportMsg, attempt, maxRetries, progress_report.UnitsNone, err)
 			return result, err
 		}
 		resultWithValue, ok := ResultAs[ResultWithValue](result)
 		if !ok {
-			return result, fmt.Errorf("result does not have a value")
+			err = fmt.Errorf("result does not have a value")
+			reportProgress(reportMsg, attempt, maxRetries, progress_report.UnitsNone, err)
+			return result, err
 		}
 		terminated, err := o.checkTerminate(context, o.Executor, resultWithValue)
 		if err != nil {
+			reportProgress(reportMsg, attempt, maxRetries, progress_report.UnitsNone, err)
 			return result, err
 		}
 		if terminated {
+			reportProgress(reportMsg, maxRetries, maxRetries, progress_report.UnitsNone, progress_report.ErrorProgressDone)
 			return result, nil
 		}
+		reportProgress(reportMsg, attempt, maxRetries, progress_report.UnitsNone, nil)
 
 		timer := time.NewTimer(o.interval)
 		select {
 		case <-context.Done():
 			timer.Stop()
+			reportProgress(reportMsg, attempt, maxRetries, progress_report.UnitsNone, context.Err())
 			return nil, context.Err()
 		case <-timer.C:
 		}
 	}
 
 	msg := fmt.Sprintf("maximum number of retries exceeded. Retries: %d, interval: %s", o.maxRetries, o.interval)
-	return result, FailedTerminationError{Result: result, Message: msg}
+	err = FailedTerminationError{Result: result, Message: msg}
+	reportProgress(reportMsg, maxRetries, maxRetries, progress_report.UnitsNone, err)
+	return result, err
 }
 
 var _ TerminatorExecutor = (*executeTerminatorWithCheck)(nil)
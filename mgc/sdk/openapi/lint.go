@@ -0,0 +1,532 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/scanner"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-openapi/jsonpointer"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	LintRuleWaitTermination = "wait-termination"
+	LintRuleConfirmable     = "confirmable"
+	LintRulePromptInput     = "prompt-input"
+	LintRuleLink            = "link"
+	LintRuleExtraParameters = "extra-parameters"
+	LintRuleDuplicateName   = "duplicate-name"
+)
+
+// Problem with one of the extensions, found by LintDocument(). Path has the keys from
+// the document root to the offending value, Line and Column its position in the file
+type LintFinding struct {
+	Rule    string
+	Path    []string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%d:%d: [%s] %s (at %s)", f.Line, f.Column, f.Rule, f.Message, strings.Join(f.Path, " > "))
+}
+
+type linter struct {
+	doc             *openapi3.T
+	root            *yaml.Node
+	extensionPrefix *string
+	operationsById  map[string]*operationDesc
+	findings        []LintFinding
+}
+
+// Check the extensions the same way they're used by the SDK, so broken expressions and names are
+// found before the spec is released, instead of failing or being ignored at runtime:
+//   - wait-termination queries must compile
+//   - confirmable and promptInput templates must parse
+//   - link targets and runtime expressions must resolve against the operations and response schemas
+//   - extra-parameters of links must not collide with each other or the target parameters
+//   - names, including the x-mgc-name renames, must not repeat among the commands of a resource
+func LintDocument(data []byte, extensionPrefix string) ([]LintFinding, error) {
+	loader := openapi3.Loader{Context: context.Background(), IsExternalRefsAllowed: false}
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(data, root); err != nil {
+		return nil, err
+	}
+
+	l := &linter{doc: doc, root: root, extensionPrefix: &extensionPrefix, operationsById: map[string]*operationDesc{}}
+	l.forEachOperation(func(desc *operationDesc) {
+		if desc.op.OperationID != "" {
+			l.operationsById[desc.op.OperationID] = desc
+		}
+	})
+
+	l.forEachOperation(l.lintOperation)
+	l.lintNames()
+
+	slices.SortStableFunc(l.findings, func(a, b LintFinding) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return l.findings, nil
+}
+
+func (l *linter) extensionKey(name string) string {
+	return *l.extensionPrefix + "-" + name
+}
+
+func (l *linter) add(rule string, path []string, format string, args ...any) {
+	finding := LintFinding{Rule: rule, Path: path, Message: fmt.Sprintf(format, args...)}
+	finding.Line, finding.Column = findPosition(l.root, path)
+	l.findings = append(l.findings, finding)
+}
+
+// Position of the deepest node of path found in the document
+func findPosition(node *yaml.Node, path []string) (line, column int) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line, column = node.Line, node.Column
+
+	for _, key := range path {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					line, column = node.Content[i].Line, node.Content[i].Column
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+				line, column = next.Line, next.Column
+			}
+		}
+		if next == nil {
+			return
+		}
+		node = next
+	}
+	return
+}
+
+func (l *linter) forEachOperation(cb func(desc *operationDesc)) {
+	paths := l.doc.Paths.Map()
+	for _, key := range slices.Sorted(mapKeys(paths)) {
+		path := paths[key]
+		for _, method := range []string{"get", "post", "put", "patch", "delete"} {
+			if op := path.GetOperation(strings.ToUpper(method)); op != nil {
+				cb(&operationDesc{path: path, op: op, method: method, pathKey: key})
+			}
+		}
+	}
+}
+
+func mapKeys[T any](m map[string]T) func(yield func(string) bool) {
+	return func(yield func(string) bool) {
+		for k := range m {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func (l *linter) lintOperation(desc *operationDesc) {
+	opPath := []string{"paths", desc.pathKey, desc.method}
+
+	if ext, ok := getExtensionObject(l.extensionPrefix, "wait-termination", desc.op.Extensions, nil); ok && ext != nil {
+		l.lintWaitTermination(append(slices.Clone(opPath), l.extensionKey("wait-termination")), ext)
+	}
+
+	if ext, ok := getExtensionObject(l.extensionPrefix, "confirmable", desc.op.Extensions, nil); ok && ext != nil {
+		c, err := utils.DecodeNewValue[confirmation](ext)
+		extPath := append(slices.Clone(opPath), l.extensionKey("confirmable"))
+		if err != nil {
+			l.add(LintRuleConfirmable, extPath, "invalid extension: %s", err)
+		} else if _, err := utils.NewTemplate(c.Message); err != nil {
+			l.add(LintRuleConfirmable, append(slices.Clone(extPath), "message"), "invalid template: %s", err)
+		}
+	}
+
+	if ext, ok := getExtensionObject(l.extensionPrefix, "promptInput", desc.op.Extensions, nil); ok && ext != nil {
+		p, err := utils.DecodeNewValue[promptSpec](ext)
+		extPath := append(slices.Clone(opPath), l.extensionKey("promptInput"))
+		if err != nil {
+			l.add(LintRulePromptInput, extPath, "invalid extension: %s", err)
+		} else {
+			for field, tmpl := range map[string]string{"message": p.MessageTemplate, "confirmValue": p.ConfirmValueTemplate} {
+				if _, err := utils.NewTemplate(tmpl); err != nil {
+					l.add(LintRulePromptInput, append(slices.Clone(extPath), field), "invalid template: %s", err)
+				}
+			}
+		}
+	}
+
+	if desc.op.Responses == nil {
+		return
+	}
+	responses := desc.op.Responses.Map()
+	for _, code := range slices.Sorted(mapKeys(responses)) {
+		response := responses[code].Value
+		if response == nil {
+			continue
+		}
+		for _, key := range slices.Sorted(mapKeys(response.Links)) {
+			if link := response.Links[key].Value; link != nil {
+				l.lintLink(append(slices.Clone(opPath), "responses", code, "links", key), desc, response, link)
+			}
+		}
+	}
+}
+
+func (l *linter) lintWaitTermination(path []string, ext map[string]any) {
+	cfg, err := utils.DecodeNewValue[core.WaitTerminationConfig](ext)
+	if err != nil {
+		l.add(LintRuleWaitTermination, path, "invalid extension: %s", err)
+		return
+	}
+
+	if (cfg.JSONPathQuery == "") == (cfg.TemplateQuery == "") {
+		l.add(LintRuleWaitTermination, path, "needs exactly one of jsonPathQuery or templateQuery")
+	}
+	if cfg.ErrorJSONPathQuery != "" && cfg.ErrorTemplateQuery != "" {
+		l.add(LintRuleWaitTermination, path, "cannot specify both errorJsonPathQuery and errorTemplateQuery")
+	}
+
+	for field, query := range map[string]string{"jsonPathQuery": cfg.JSONPathQuery, "errorJsonPathQuery": cfg.ErrorJSONPathQuery} {
+		if query == "" {
+			continue
+		}
+		if _, err := utils.NewJsonPath(query); err != nil {
+			l.add(LintRuleWaitTermination, append(slices.Clone(path), field), "invalid JSONPath: %s", err)
+		}
+	}
+	for field, query := range map[string]string{"templateQuery": cfg.TemplateQuery, "errorTemplateQuery": cfg.ErrorTemplateQuery} {
+		if query == "" {
+			continue
+		}
+		if _, err := utils.NewTemplate(query); err != nil {
+			l.add(LintRuleWaitTermination, append(slices.Clone(path), field), "invalid template: %s", err)
+		}
+	}
+}
+
+func (l *linter) resolveLinkTarget(link *openapi3.Link) (*operationDesc, error) {
+	if link.OperationID != "" {
+		if target, ok := l.operationsById[link.OperationID]; ok {
+			return target, nil
+		}
+		return nil, fmt.Errorf("operationId %q not found", link.OperationID)
+	}
+
+	ref, ok := strings.CutPrefix(link.OperationRef, "#/paths/")
+	if !ok {
+		// references to other documents are resolved at runtime
+		return nil, nil
+	}
+	tokens := strings.Split(ref, "/")
+	if len(tokens) == 2 {
+		pathKey := strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[0])
+		if path := l.doc.Paths.Find(pathKey); path != nil {
+			if op := path.GetOperation(strings.ToUpper(tokens[1])); op != nil {
+				return &operationDesc{path: path, op: op, method: tokens[1], pathKey: pathKey}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("operationRef %q not found", link.OperationRef)
+}
+
+func (l *linter) lintLink(path []string, owner *operationDesc, response *openapi3.Response, link *openapi3.Link) {
+	if ext, ok := getExtensionObject(l.extensionPrefix, "wait-termination", link.Extensions, nil); ok && ext != nil {
+		l.lintWaitTermination(append(slices.Clone(path), l.extensionKey("wait-termination")), ext)
+	}
+
+	target, err := l.resolveLinkTarget(link)
+	if err != nil {
+		l.add(LintRuleLink, path, "%s", err)
+		return
+	}
+
+	ownerParams := newParameters(owner.pathKey, owner.path.Parameters, owner.op.Parameters, l.extensionPrefix)
+	resolve := func(exprPath []string, expr any) {
+		s, ok := expr.(string)
+		if !ok {
+			return
+		}
+		if err := lintLinkRtExpression(s, owner.op, ownerParams, response); err != nil {
+			l.add(LintRuleLink, exprPath, "%s", err)
+		}
+	}
+
+	// target external name by internal name and location
+	var targetNames map[string]string
+	if target != nil {
+		targetNames = map[string]string{}
+		targetParams := newParameters(target.pathKey, target.path.Parameters, target.op.Parameters, l.extensionPrefix)
+		_, _ = targetParams.forEach(parametersLocations, func(externalName string, parameter *openapi3.Parameter) (bool, error) {
+			targetNames[parameter.Name] = externalName
+			targetNames[parameter.In+"."+parameter.Name] = externalName
+			return true, nil
+		})
+		_, _ = targetParams.forEach(configLocations, func(externalName string, parameter *openapi3.Parameter) (bool, error) {
+			targetNames[parameter.Name] = externalName
+			targetNames[parameter.In+"."+parameter.Name] = externalName
+			return true, nil
+		})
+		_, _ = newRequestBody(strings.ToUpper(target.method), target.op, logger(), l.extensionPrefix).forEach(func(externalName, internalName, location string) (bool, error) {
+			targetNames["body."+internalName] = externalName
+			return true, nil
+		})
+	}
+
+	for _, name := range slices.Sorted(mapKeys(link.Parameters)) {
+		paramPath := append(slices.Clone(path), "parameters", name)
+		if targetNames != nil && targetNames[name] == "" {
+			l.add(LintRuleLink, paramPath, "target operation has no parameter %q", name)
+		}
+		resolve(paramPath, link.Parameters[name])
+	}
+
+	if ext, ok := getExtensionObject(l.extensionPrefix, "requestBodyParameters", link.Extensions, nil); ok && ext != nil {
+		extPath := append(slices.Clone(path), l.extensionKey("requestBodyParameters"))
+		for _, jpStr := range slices.Sorted(mapKeys(ext)) {
+			jp, err := jsonpointer.New(jpStr)
+			if err != nil {
+				l.add(LintRuleLink, append(slices.Clone(extPath), jpStr), "invalid JSON Pointer: %s", err)
+				continue
+			}
+			if tokens := jp.DecodedTokens(); targetNames != nil && len(tokens) > 0 && targetNames["body."+tokens[0]] == "" {
+				l.add(LintRuleLink, append(slices.Clone(extPath), jpStr), "target request body has no property %q", tokens[0])
+			}
+			resolve(append(slices.Clone(extPath), jpStr), ext[jpStr])
+		}
+	}
+
+	if extraParams, ok := getExtensionArray(l.extensionPrefix, "extra-parameters", link.Extensions, nil); ok && extraParams != nil {
+		l.lintExtraParameters(append(slices.Clone(path), l.extensionKey("extra-parameters")), extraParams, targetNames)
+	}
+}
+
+func (l *linter) lintExtraParameters(path []string, extraParams []any, targetNames map[string]string) {
+	external := map[string]bool{}
+	for _, name := range targetNames {
+		external[name] = true
+	}
+
+	seen := map[string]bool{}
+	for i, spec := range extraParams {
+		paramPath := append(slices.Clone(path), strconv.Itoa(i))
+		param, err := utils.DecodeNewValue[extraParameterExtension](spec)
+		if err != nil {
+			l.add(LintRuleExtraParameters, paramPath, "invalid extra parameter: %s", err)
+			continue
+		}
+
+		switch {
+		case param.Name == "":
+			l.add(LintRuleExtraParameters, paramPath, "missing name")
+		case seen[param.Name]:
+			l.add(LintRuleExtraParameters, paramPath, "%q is repeated", param.Name)
+		case external[param.Name]:
+			l.add(LintRuleExtraParameters, paramPath, "%q collides with a parameter of the target operation and would be ignored", param.Name)
+		}
+		seen[param.Name] = true
+	}
+}
+
+// Static version of linkRtExpression.resolve(), checking the expression against the owner
+// operation and response instead of the actual values
+func lintLinkRtExpression(expr string, op *openapi3.Operation, params *parameters, response *openapi3.Response) error {
+	rest, ok := strings.CutPrefix(expr, "$")
+	if !ok {
+		if strings.Contains(expr, "{") && strings.Contains(expr, "}") {
+			return fmt.Errorf("malformed link runtime expression: %q, embedded expressions are not supported", expr)
+		}
+		return nil
+	}
+
+	source, rest, _ := strings.Cut(rest, ".")
+	switch source {
+	case "url", "method", "statusCode":
+		return nil
+	case "request", "response":
+	default:
+		return fmt.Errorf("malformed link runtime expression: %q at %q", expr, source)
+	}
+
+	location, name, _ := strings.Cut(rest, ".")
+	if location == "body" || strings.HasPrefix(location, "body#") {
+		pointer, ok := strings.CutPrefix(rest, "body#")
+		if !ok {
+			return fmt.Errorf("malformed link runtime expression: %q, body needs a JSON Pointer such as body#/id", expr)
+		}
+		var content openapi3.Content
+		if source == "response" {
+			content = response.Content
+		} else if op.RequestBody != nil && op.RequestBody.Value != nil {
+			content = op.RequestBody.Value.Content
+		}
+		return lintSchemaPointer(expr, content, pointer)
+	}
+
+	if name == "" {
+		return fmt.Errorf("malformed link runtime expression: %q, missing the %s name", expr, location)
+	}
+	// the runtime reads a single token as the name
+	s := &scanner.Scanner{}
+	s.Init(strings.NewReader(name))
+	if s.Scan(); s.TokenText() != name {
+		return fmt.Errorf("malformed link runtime expression: %q, only %q would be read as the name", expr, s.TokenText())
+	}
+
+	switch location {
+	case "header":
+		return nil
+	case "query", "path":
+		if source == "response" {
+			return fmt.Errorf("malformed link runtime expression: %q, responses have no %s", expr, location)
+		}
+		found := false
+		_, _ = params.forEach([]string{location}, func(_ string, parameter *openapi3.Parameter) (bool, error) {
+			found = found || parameter.Name == name
+			return !found, nil
+		})
+		if !found {
+			return fmt.Errorf("link runtime expression %q: operation has no %s parameter %q", expr, location, name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("malformed link runtime expression: %q at %q", expr, location)
+	}
+}
+
+func lintSchemaPointer(expr string, content openapi3.Content, pointer string) error {
+	jp, err := jsonpointer.New(pointer)
+	if err != nil {
+		return fmt.Errorf("malformed JSON Pointer on link runtime expression %q: %w", expr, err)
+	}
+
+	mt := content.Get("application/json")
+	if mt == nil {
+		for _, other := range content {
+			mt = other
+			break
+		}
+	}
+	if mt == nil || mt.Schema == nil {
+		return fmt.Errorf("link runtime expression %q: there's no body schema", expr)
+	}
+
+	if !schemaHasPointer(mt.Schema, jp.DecodedTokens(), 0) {
+		return fmt.Errorf("link runtime expression %q: %q not found in the body schema", expr, pointer)
+	}
+	return nil
+}
+
+// Whether the schema may have a value at the JSON Pointer tokens. Free-form objects have any
+const maxSchemaPointerDepth = 32
+
+func schemaHasPointer(ref *openapi3.SchemaRef, tokens []string, depth int) bool {
+	if len(tokens) == 0 || depth > maxSchemaPointerDepth {
+		return true
+	}
+	if ref == nil || ref.Value == nil {
+		return false
+	}
+	schema := ref.Value
+
+	for _, sub := range slices.Concat(schema.AllOf, schema.OneOf, schema.AnyOf) {
+		if schemaHasPointer(sub, tokens, depth+1) {
+			return true
+		}
+	}
+
+	if prop, ok := schema.Properties[tokens[0]]; ok {
+		return schemaHasPointer(prop, tokens[1:], depth+1)
+	}
+	if schema.Items != nil {
+		if _, err := strconv.Atoi(tokens[0]); err == nil {
+			return schemaHasPointer(schema.Items, tokens[1:], depth+1)
+		}
+	}
+	if schema.AdditionalProperties.Schema != nil || (schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has) {
+		return true
+	}
+	freeForm := len(schema.Properties) == 0 && schema.Items == nil && len(schema.AllOf)+len(schema.OneOf)+len(schema.AnyOf) == 0
+	return freeForm && (schema.Type == nil || schema.Type.Is("object"))
+}
+
+type namedCommand struct {
+	name string
+	path []string
+}
+
+// The names of the commands and sub resources of a resource, as in collectResourceChildren()
+func (l *linter) tableNames(table *operationTable) []namedCommand {
+	var names []namedCommand
+	for _, entry := range table.childOperations {
+		name := getNameExtension(l.extensionPrefix, entry.desc.op.Extensions, entry.key)
+		path := []string{"paths", entry.desc.pathKey, entry.desc.method}
+		if _, ok := entry.desc.op.Extensions[l.extensionKey("name")]; ok {
+			path = append(path, l.extensionKey("name"))
+		}
+		names = append(names, namedCommand{name, path})
+	}
+	for _, child := range table.childTables {
+		names = append(names, namedCommand{child.name, nil})
+	}
+	return names
+}
+
+func (l *linter) lintTable(resource string, table *operationTable, extra []namedCommand) {
+	byName := map[string][]namedCommand{}
+	for _, n := range append(l.tableNames(table), extra...) {
+		byName[n.name] = append(byName[n.name], n)
+	}
+
+	for _, name := range slices.Sorted(mapKeys(byName)) {
+		if duplicates := byName[name]; len(duplicates) > 1 {
+			for _, d := range duplicates {
+				if d.path != nil {
+					l.add(LintRuleDuplicateName, d.path, "%q is used by %d commands of %q, only one of them is available", name, len(duplicates), resource)
+				}
+			}
+		}
+	}
+
+	for _, child := range table.childTables {
+		l.lintTable(resource+" "+child.name, child, nil)
+	}
+}
+
+func (l *linter) lintNames() {
+	// resources share the module level with the operations without tags
+	var resources []namedCommand
+	for i, tag := range l.doc.Tags {
+		path := []string{"tags", strconv.Itoa(i)}
+		if _, ok := tag.Extensions[l.extensionKey("name")]; ok {
+			path = append(path, l.extensionKey("name"))
+		}
+		resources = append(resources, namedCommand{getNameExtension(l.extensionPrefix, tag.Extensions, tag.Name), path})
+
+		l.lintTable(tag.Name, collectOperations(tag, l.doc, l.extensionPrefix, logger()), nil)
+	}
+	l.lintTable("module", collectOperations(nil, l.doc, l.extensionPrefix, logger()), resources)
+}
@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const lintSpec = `openapi: 3.0.3
+info: {title: test, version: "1.0"}
+tags:
+  - name: instances
+  - name: snapshots
+    x-mgc-name: vms
+  - name: vms
+paths:
+  /v1/instances:
+    post:
+      tags: [instances]
+      operationId: create
+      x-mgc-wait-termination:
+        jsonPathQuery: $.status == "running"
+        templateQuery: '{{ eq .status "running" }}'
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id: {type: string}
+          links:
+            get:
+              operationId: get
+              parameters:
+                id: $response.body#/id
+            delete:
+              operationId: delete
+              parameters:
+                id: $response.body#/uuid
+            rename:
+              operationId: rename
+              parameters:
+                id: $request.query.zone
+              x-mgc-extra-parameters:
+                - {name: name, schema: {type: string}}
+                - {name: force, schema: {type: boolean}}
+                - {name: force, schema: {type: boolean}}
+            missing:
+              operationId: nope
+  /v1/instances/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      tags: [instances]
+      operationId: get
+      x-mgc-wait-termination:
+        jsonPathQuery: $.status ==
+      responses:
+        "200": {description: ok}
+    delete:
+      tags: [instances]
+      operationId: delete
+      x-mgc-confirmable:
+        message: "Delete {{ .parameters.id }"
+      responses:
+        "204": {description: ok}
+    patch:
+      tags: [instances]
+      operationId: rename
+      x-mgc-name: delete
+      x-mgc-promptInput:
+        message: "Rename {{ .parameters.id }"
+        confirmValue: "{{ .parameters.id }"
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+      responses:
+        "204": {description: ok}
+`
+
+func TestLintDocument(t *testing.T) {
+	findings, err := LintDocument([]byte(lintSpec), "x-mgc")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []struct {
+		rule    string
+		line    int
+		message string
+	}{
+		{LintRuleDuplicateName, 6, `"vms" is used by 2 commands`},
+		{LintRuleDuplicateName, 7, `"vms" is used by 2 commands`},
+		{LintRuleWaitTermination, 13, "needs exactly one of"},
+		{LintRuleLink, 40, `"/uuid" not found in the body schema`},
+		{LintRuleLink, 44, `no query parameter "zone"`},
+		{LintRuleExtraParameters, 46, `"name" collides with a parameter`},
+		{LintRuleExtraParameters, 48, `"force" is repeated`},
+		{LintRuleLink, 49, `operationId "nope" not found`},
+		{LintRuleWaitTermination, 58, "invalid JSONPath"},
+		{LintRuleConfirmable, 65, "invalid template"},
+		{LintRuleDuplicateName, 61, `"delete" is used by 2 commands of "instances"`},
+		{LintRuleDuplicateName, 71, `"delete" is used by 2 commands of "instances"`},
+		{LintRulePromptInput, 73, "invalid template"},
+		{LintRulePromptInput, 74, "invalid template"},
+	}
+
+	for _, e := range expected {
+		found := slices.ContainsFunc(findings, func(f LintFinding) bool {
+			return f.Rule == e.rule && f.Line == e.line && strings.Contains(f.Message, e.message)
+		})
+		if !found {
+			t.Errorf("expected [%s] at line %d: %s", e.rule, e.line, e.message)
+		}
+	}
+
+	// the paths of sibling findings must not share their backing array
+	var promptFields []string
+	for _, f := range findings {
+		if f.Rule == LintRulePromptInput {
+			promptFields = append(promptFields, f.Path[len(f.Path)-1])
+		}
+	}
+	slices.Sort(promptFields)
+	if !slices.Equal(promptFields, []string{"confirmValue", "message"}) {
+		t.Errorf("expected prompt input findings for confirmValue and message, got %v", promptFields)
+	}
+
+	if len(findings) != len(expected) {
+		t.Errorf("expected %d findings, got %d:", len(expected), len(findings))
+		for _, f := range findings {
+			t.Log(f)
+		}
+	}
+}
+
+func TestLintDocumentEmbeddedSpecs(t *testing.T) {
+	files, err := filepath.Glob("openapis/*.openapi.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		findings, err := LintDocument(data, "x-mgc")
+		if err != nil {
+			t.Errorf("%s: %s", file, err)
+		}
+		for _, f := range findings {
+			t.Errorf("%s:%s", file, f)
+		}
+	}
+}
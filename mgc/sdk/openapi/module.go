@@ -84,49 +84,89 @@ func newModule(
 	refResolver *core.MultiRefPathResolver,
 ) (m core.Grouper, err error) {
 	logger = logger.Named(indexModule.Name)
-	loadRefsDocument := newRefsDocumentLoader(&m)
+	// Links are resolved by the actual operations, never by the cached ones
+	var actual core.Grouper
+	loadRefsDocument := newRefsDocumentLoader(&actual)
 	docResolver := core.NewDocumentRefPathResolver(func() (any, error) { return loadRefsDocument() })
 	err = refResolver.Add(indexModule.Url, docResolver)
 	if err != nil {
 		return
 	}
 
+	loadChildren := utils.NewLazyLoaderWithError(func() (children []core.Descriptor, err error) {
+		ctx := context.Background()
+		mData, err := loader.Load(indexModule.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		oapiLoader := openapi3.Loader{Context: ctx, IsExternalRefsAllowed: false}
+		doc, err := oapiLoader.LoadFromData(mData)
+		if err != nil {
+			return
+		}
+
+		boundRefResolver := core.NewBoundRefResolver(indexModule.Url, refResolver)
+		children = make([]core.Descriptor, 0)
+
+		opTable := collectOperations(nil, doc, extensionPrefix, logger)
+
+		untaggedChildren, err := collectResourceChildren(indexModule.Name, opTable, doc, extensionPrefix, logger, boundRefResolver)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, untaggedChildren...)
+
+		for _, tag := range doc.Tags {
+			resource := newResource(
+				tag,
+				doc,
+				extensionPrefix,
+				logger,
+				boundRefResolver,
+			)
+
+			children = append(children, resource)
+		}
+
+		return children, nil
+	})
+
+	actual = core.NewSimpleGrouper(indexModule.DescriptorSpec, loadChildren)
+
+	cacheDir, err := TreeCacheDir()
+	if err != nil {
+		logger.Debugw("tree cache disabled", "error", err)
+		return actual, nil
+	}
+
 	m = core.NewSimpleGrouper(
 		indexModule.DescriptorSpec,
 		func() (children []core.Descriptor, err error) {
-			ctx := context.Background()
 			mData, err := loader.Load(indexModule.Path)
 			if err != nil {
 				return nil, err
 			}
 
-			oapiLoader := openapi3.Loader{Context: ctx, IsExternalRefsAllowed: false}
-			doc, err := oapiLoader.LoadFromData(mData)
-			if err != nil {
-				return
+			key := treeCacheKey(indexModule, extensionPrefix, mData)
+			if nodes, err := loadTreeCache(cacheDir, indexModule.Name, key); err == nil {
+				logger.Debugw("using tree cache", "key", key)
+				return newCachedModuleChildren(actual, nodes), nil
+			} else {
+				logger.Debugw("no usable tree cache", "key", key, "error", err)
 			}
 
-			boundRefResolver := core.NewBoundRefResolver(indexModule.Url, refResolver)
-			children = make([]core.Descriptor, 0)
-
-			opTable := collectOperations(nil, doc, extensionPrefix, logger)
-
-			untaggedChildren, err := collectResourceChildren(indexModule.Name, opTable, doc, extensionPrefix, logger, boundRefResolver)
+			// a copy, as the actual module sorts its own children
+			children, err = visitAllChildren(actual)
 			if err != nil {
 				return nil, err
 			}
-			children = append(children, untaggedChildren...)
-
-			for _, tag := range doc.Tags {
-				resource := newResource(
-					tag,
-					doc,
-					extensionPrefix,
-					logger,
-					boundRefResolver,
-				)
-
-				children = append(children, resource)
+
+			// Failing to cache only makes the next run slower
+			if nodes, err := newTreeCacheNodes(children); err != nil {
+				logger.Debugw("unable to create tree cache", "error", err)
+			} else if err = saveTreeCache(cacheDir, indexModule.Name, key, nodes); err != nil {
+				logger.Debugw("unable to save tree cache", "error", err)
 			}
 
 			return children, nil
@@ -337,3 +337,40 @@ func newOperationTable(name string, descs []*operationDesc) *operationTable {
 	table.finalizeEntryKeys()
 	return table
 }
+
+// Identifies an operation outside of a loaded document, such as by tools that parse the
+// specs with other libraries. CliName is the value of its "x-cli-name" extension, if any
+type OperationRef struct {
+	Method  string
+	Path    string
+	CliName string
+}
+
+// Returns the names given to the operations of a resource (OAPI Tag), by index of 'ops'. The
+// sub-resources come first and the operation key last, such as ["volumes", "attach"]. The
+// "x-mgc-name" extension, which replaces the operation key, is not considered
+func OperationNames(resourceName string, ops []OperationRef) [][]string {
+	descs := make([]*operationDesc, len(ops))
+	indexes := make(map[*operationDesc]int, len(ops))
+	for i, ref := range ops {
+		op := &openapi3.Operation{}
+		if ref.CliName != "" {
+			op.Extensions = map[string]any{"x-cli-name": ref.CliName}
+		}
+		descs[i] = &operationDesc{op: op, method: ref.Method, pathKey: ref.Path}
+		indexes[descs[i]] = i
+	}
+
+	names := make([][]string, len(ops))
+	var collect func(t *operationTable, prefix []string)
+	collect = func(t *operationTable, prefix []string) {
+		for _, entry := range t.childOperations {
+			names[indexes[entry.desc]] = append(slices.Clone(prefix), entry.key)
+		}
+		for _, childTable := range t.childTables {
+			collect(childTable, append(slices.Clone(prefix), childTable.name))
+		}
+	}
+	collect(newOperationTable(resourceName, descs), nil)
+	return names
+}
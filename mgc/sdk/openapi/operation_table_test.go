@@ -550,3 +550,24 @@ func Test_operationTree_dbaas_clusters_import_mode(t *testing.T) {
 }
 
 // END: Test virtual-machine.openapi.yaml resources
+
+func TestOperationNames(t *testing.T) {
+	ops := []OperationRef{
+		{Method: "get", Path: "/v0/snapshots"},
+		{Method: "post", Path: "/v0/snapshots/{id}/restore"},
+		{Method: "get", Path: "/v0/snapshots/{id}/volumes"},
+		{Method: "post", Path: "/v0/snapshots/{id}/volumes/{volume_id}/start", CliName: "start-restore"},
+		{Method: "post", Path: "/v0/snapshots/{id}/volumes/{volume_id}/stop"},
+	}
+	expected := [][]string{
+		{"list"},
+		{"restore"},
+		{"volumes", "list"},
+		{"volumes", "start-restore"},
+		{"volumes", "stop"},
+	}
+	got := OperationNames("snapshots", ops)
+	if !slices.EqualFunc(expected, got, slices.Equal) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
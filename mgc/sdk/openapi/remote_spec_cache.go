@@ -0,0 +1,213 @@
+package openapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/dataloader"
+	"gopkg.in/yaml.v3"
+)
+
+const remoteSpecMetaSuffix = ".meta.json"
+
+// Saved next to each cached spec, so it can be verified and revalidated
+type RemoteSpecMeta struct {
+	Url       string    `json:"url"`
+	ETag      string    `json:"etag,omitempty"`
+	Sha256    string    `json:"sha256"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Specs downloaded from the module URLs of the index. Loading them never reaches
+// the network, they are only fetched by Refresh(), and their checksum is verified
+// against the one recorded when they were downloaded.
+type RemoteSpecCache struct {
+	Dir string
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Cache entries are flat files, names must not escape the cache directory
+func (c RemoteSpecCache) path(name string) (string, error) {
+	if name == "" || filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid cache entry name %q", name)
+	}
+	return filepath.Join(c.Dir, name), nil
+}
+
+func (c RemoteSpecCache) Meta(name string) (*RemoteSpecMeta, error) {
+	p, err := c.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(p + remoteSpecMetaSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &RemoteSpecMeta{}
+	if err = json.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("invalid cache metadata of %q: %w", name, err)
+	}
+	return meta, nil
+}
+
+func (c RemoteSpecCache) Load(name string) ([]byte, error) {
+	p, err := c.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := c.Meta(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if sum := sha256Hex(data); sum != meta.Sha256 {
+		return nil, fmt.Errorf("checksum mismatch of cached %q: expected %s, got %s", name, meta.Sha256, sum)
+	}
+	return data, nil
+}
+
+// Remove the cached spec, so the lower layers are used again
+func (c RemoteSpecCache) Remove(name string) error {
+	p, err := c.path(name)
+	if err != nil {
+		return err
+	}
+	return errors.Join(
+		removeIfExists(p),
+		removeIfExists(p+remoteSpecMetaSuffix),
+	)
+}
+
+func removeIfExists(p string) error {
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func writeFileAtomic(p string, data []byte) error {
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func validateSpecDocument(data []byte) error {
+	// JSON is also valid YAML
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid spec document: %w", err)
+	}
+	if _, ok := doc["openapi"]; !ok {
+		return fmt.Errorf("invalid spec document: missing \"openapi\" version")
+	}
+	return nil
+}
+
+// Download the spec from url, revalidating the cached one with its ETag.
+// If expectedSha256 is given, the downloaded contents must match it.
+// Returns whether the cached spec changed.
+func (c RemoteSpecCache) Refresh(ctx context.Context, client *http.Client, name, url, expectedSha256 string) (changed bool, err error) {
+	p, err := c.path(name)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// Only revalidate if the cache is usable, otherwise download it again
+	meta, metaErr := c.Meta(name)
+	_, loadErr := c.Load(name)
+	revalidate := metaErr == nil && loadErr == nil && meta.Url == url && meta.ETag != ""
+	if revalidate {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		// Only valid as the answer to If-None-Match, such as from a misbehaving proxy
+		if !revalidate {
+			return false, fmt.Errorf("unable to download %q: unexpected %s without a cached spec", url, resp.Status)
+		}
+		meta.FetchedAt = time.Now().UTC()
+		return false, c.writeMeta(p, meta)
+	case http.StatusOK:
+	default:
+		return false, fmt.Errorf("unable to download %q: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	sum := sha256Hex(data)
+	if expectedSha256 != "" && !strings.EqualFold(sum, expectedSha256) {
+		return false, fmt.Errorf("checksum mismatch of %q: expected %s, got %s", url, expectedSha256, sum)
+	}
+	if err = validateSpecDocument(data); err != nil {
+		return false, fmt.Errorf("%s: %w", url, err)
+	}
+
+	if err = os.MkdirAll(c.Dir, 0755); err != nil {
+		return false, err
+	}
+	if err = writeFileAtomic(p, data); err != nil {
+		return false, err
+	}
+
+	logger().Debugw("cached remote spec", "name", name, "url", url, "etag", resp.Header.Get("ETag"), "sha256", sum)
+
+	return true, c.writeMeta(p, &RemoteSpecMeta{
+		Url:       url,
+		ETag:      resp.Header.Get("ETag"),
+		Sha256:    sum,
+		FetchedAt: time.Now().UTC(),
+	})
+}
+
+func (c RemoteSpecCache) writeMeta(p string, meta *RemoteSpecMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p+remoteSpecMetaSuffix, data)
+}
+
+func (c RemoteSpecCache) String() string {
+	return fmt.Sprintf("RemoteSpecCache(dir: %s)", c.Dir)
+}
+
+var _ dataloader.Loader = (*RemoteSpecCache)(nil)
@@ -0,0 +1,132 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const remoteSpec = `{"openapi": "3.0.0", "info": {"title": "test", "version": "1.0.0"}, "paths": {}}`
+
+func TestRemoteSpecCacheRefresh(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(remoteSpec))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := RemoteSpecCache{Dir: filepath.Join(t.TempDir(), "cache")}
+
+	if _, err := cache.Load("test.openapi.yaml"); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error before refresh, got %v", err)
+	}
+
+	changed, err := cache.Refresh(ctx, server.Client(), "test.openapi.yaml", server.URL, "")
+	if err != nil || !changed {
+		t.Fatalf("expected first refresh to download, got changed=%v err=%v", changed, err)
+	}
+
+	data, err := cache.Load("test.openapi.yaml")
+	if err != nil || string(data) != remoteSpec {
+		t.Fatalf("expected cached spec, got %q err=%v", data, err)
+	}
+
+	changed, err = cache.Refresh(ctx, server.Client(), "test.openapi.yaml", server.URL, "")
+	if err != nil || changed {
+		t.Fatalf("expected second refresh to be revalidated with the ETag, got changed=%v err=%v", changed, err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+
+	if err = os.WriteFile(filepath.Join(cache.Dir, "test.openapi.yaml"), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cache.Load("test.openapi.yaml"); err == nil {
+		t.Errorf("expected checksum error of tampered spec")
+	}
+
+	// not revalidated as the cache is broken
+	changed, err = cache.Refresh(ctx, server.Client(), "test.openapi.yaml", server.URL, "")
+	if err != nil || !changed {
+		t.Fatalf("expected broken cache to be downloaded again, got changed=%v err=%v", changed, err)
+	}
+}
+
+func TestRemoteSpecCacheRefreshChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(remoteSpec))
+	}))
+	defer server.Close()
+
+	cache := RemoteSpecCache{Dir: t.TempDir()}
+	ctx := context.Background()
+
+	if _, err := cache.Refresh(ctx, server.Client(), "test.openapi.yaml", server.URL, "0000"); err == nil {
+		t.Errorf("expected checksum mismatch error")
+	}
+	if _, err := cache.Load("test.openapi.yaml"); !os.IsNotExist(err) {
+		t.Errorf("spec with wrong checksum must not be cached, got %v", err)
+	}
+
+	if _, err := cache.Refresh(ctx, server.Client(), "test.openapi.yaml", server.URL, sha256Hex([]byte(remoteSpec))); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if _, err := cache.Load("../test.openapi.yaml"); err == nil {
+		t.Errorf("expected error for name outside of the cache")
+	}
+}
+
+func TestRemoteSpecCacheRefreshUnexpectedNotModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	cache := RemoteSpecCache{Dir: filepath.Join(t.TempDir(), "cache")}
+	changed, err := cache.Refresh(context.Background(), server.Client(), "test.openapi.yaml", server.URL, "")
+	if err == nil || changed {
+		t.Fatalf("expected error for 304 without a cached spec, got changed=%v err=%v", changed, err)
+	}
+	if _, err = cache.Meta("test.openapi.yaml"); !os.IsNotExist(err) {
+		t.Errorf("expected no metadata to be written, got %v", err)
+	}
+}
+
+func TestLayeredLoaderOverrideWinsOverRemote(t *testing.T) {
+	overrideDir := t.TempDir()
+	cacheDir := t.TempDir()
+	t.Setenv(openApiDirEnvVar, overrideDir)
+	t.Setenv(openApiCacheDirEnvVar, cacheDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(remoteSpec))
+	}))
+	defer server.Close()
+
+	cache := RemoteSpecCache{Dir: cacheDir}
+	if _, err := cache.Refresh(context.Background(), server.Client(), "test.openapi.yaml", server.URL, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	override := `{"openapi": "3.0.0", "info": {"title": "override", "version": "1.0.0"}, "paths": {}}`
+	if err := os.WriteFile(filepath.Join(overrideDir, "test.openapi.yaml"), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := NewLayeredLoader().Load("test.openapi.yaml")
+	if err != nil || string(data) != override {
+		t.Errorf("expected the local override, got %q err=%v", data, err)
+	}
+}
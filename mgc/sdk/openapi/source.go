@@ -13,6 +13,8 @@ type indexModuleSpec struct {
 	core.DescriptorSpec
 	Url  string
 	Path string
+	// Optional, verified when the spec is downloaded from Url
+	Sha256 string
 }
 
 type indexFileSpec struct {
@@ -23,6 +25,23 @@ type indexFileSpec struct {
 const indexFileName = "index.openapi.yaml"
 const indexVersion = "1.0.0"
 
+func loadIndex(loader dataloader.Loader) (*indexFileSpec, error) {
+	data, err := loader.Load(indexFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var index indexFileSpec
+	err = yaml.Unmarshal(data, &index)
+	if err != nil {
+		return nil, err
+	}
+	if index.Version != indexVersion {
+		return nil, fmt.Errorf("unsupported %q version %q, expected %q", indexFileName, index.Version, indexVersion)
+	}
+	return &index, nil
+}
+
 // Source -> Module -> Resource -> Operation
 
 // -- ROOT: Source
@@ -34,19 +53,10 @@ func NewSource(loader dataloader.Loader, extensionPrefix *string) *core.SimpleGr
 			Description: fmt.Sprintf("OpenApis loaded using %v", loader),
 		},
 		func() (modules []core.Grouper, err error) {
-			data, err := loader.Load(indexFileName)
-			if err != nil {
-				return nil, err
-			}
-
-			var index indexFileSpec
-			err = yaml.Unmarshal(data, &index)
+			index, err := loadIndex(loader)
 			if err != nil {
 				return nil, err
 			}
-			if index.Version != indexVersion {
-				return nil, fmt.Errorf("unsupported %q version %q, expected %q", indexFileName, index.Version, indexVersion)
-			}
 			modules = make([]core.Grouper, len(index.Modules))
 			refResolver := core.NewMultiRefPathResolver()
 			for i := range index.Modules {
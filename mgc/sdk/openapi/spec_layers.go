@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/dataloader"
+)
+
+// Spec layers, from the bottom to the top: the last one that has a file wins
+const (
+	// Specs built into the binary with -tags embed
+	LayerEmbedded = "embedded"
+	// Specs downloaded from the index module URLs with RefreshRemoteSpecs()
+	LayerRemote = "remote"
+	// Specs explicitly provided by the user in $MGC_SDK_OPENAPI_DIR or ./openapis
+	LayerOverride = "override"
+)
+
+const (
+	openApiDirEnvVar      = "MGC_SDK_OPENAPI_DIR"
+	openApiCacheDirEnvVar = "MGC_SDK_OPENAPI_CACHE_DIR"
+	defaultOpenApiDir     = "openapis"
+)
+
+func OverrideDir() string {
+	if dir := os.Getenv(openApiDirEnvVar); dir != "" {
+		return dir
+	}
+	return defaultOpenApiDir
+}
+
+func RemoteCacheDir() (string, error) {
+	if dir := os.Getenv(openApiCacheDirEnvVar); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mgc", "openapis"), nil
+}
+
+// Embedded specs, overridden by the downloaded specs, overridden by the user directory, so
+// local changes are never hidden by a refresh. The index itself is never downloaded, so the
+// remote layer only replaces modules.
+func NewLayeredLoader() *dataloader.LayeredLoader {
+	var layers []dataloader.Layer
+	if embedLoader := GetEmbedLoader(); embedLoader != nil {
+		layers = append(layers, dataloader.Layer{Name: LayerEmbedded, Loader: embedLoader})
+	}
+	if dir, err := RemoteCacheDir(); err == nil {
+		layers = append(layers, dataloader.Layer{Name: LayerRemote, Loader: RemoteSpecCache{Dir: dir}})
+	} else {
+		logger().Debugw("remote specs disabled, no cache directory", "error", err)
+	}
+	layers = append(layers, dataloader.Layer{Name: LayerOverride, Loader: dataloader.FileLoader{Dir: OverrideDir()}})
+	return dataloader.NewLayeredLoader(layers...)
+}
+
+type SpecStatus struct {
+	Module  string `json:"module"`
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// Layer the module is loaded from
+	Layer string `json:"layer"`
+	// All layers that have the module
+	Available []string `json:"available"`
+	Url       string   `json:"url"`
+	FetchedAt string   `json:"fetched_at"`
+	// Problems of layers that have the module but couldn't be loaded, such as invalid checksums
+	Errors []string `json:"errors"`
+}
+
+func getRemoteSpecCache(loader *dataloader.LayeredLoader) (RemoteSpecCache, error) {
+	l, ok := loader.Layer(LayerRemote)
+	if !ok {
+		return RemoteSpecCache{}, fmt.Errorf("remote specs are not available")
+	}
+	cache, ok := l.(RemoteSpecCache)
+	if !ok {
+		return RemoteSpecCache{}, fmt.Errorf("unexpected remote spec loader %v", l)
+	}
+	return cache, nil
+}
+
+// Which layer each module of the index comes from, including the index itself
+func GetSpecsStatus(loader *dataloader.LayeredLoader) ([]SpecStatus, error) {
+	index, err := loadIndex(loader)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, cacheErr := getRemoteSpecCache(loader)
+
+	status := func(name, path, version, url string) SpecStatus {
+		s := SpecStatus{Module: name, Path: path, Version: version, Url: url}
+		for _, layer := range loader.Layers {
+			if _, err := layer.Loader.Load(path); err == nil {
+				s.Available = append(s.Available, layer.Name)
+				s.Layer = layer.Name
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				s.Errors = append(s.Errors, fmt.Sprintf("%s: %s", layer.Name, err))
+			}
+		}
+		if cacheErr == nil {
+			if meta, err := cache.Meta(path); err == nil {
+				s.FetchedAt = meta.FetchedAt.Format(time.RFC3339)
+			}
+		}
+		return s
+	}
+
+	result := make([]SpecStatus, 0, len(index.Modules)+1)
+	result = append(result, status("index", indexFileName, index.Version, ""))
+	for _, m := range index.Modules {
+		result = append(result, status(m.Name, m.Path, m.Version, m.Url))
+	}
+	return result, nil
+}
+
+type SpecRefreshResult struct {
+	Module  string `json:"module"`
+	Url     string `json:"url"`
+	Changed bool   `json:"changed"`
+	Error   string `json:"error"`
+}
+
+// Download the specs of the given modules, or all modules with an URL if none is given.
+// Failures of a module don't stop the others, they are reported in the result and the returned error.
+func RefreshRemoteSpecs(ctx context.Context, loader *dataloader.LayeredLoader, client *http.Client, modules []string) ([]SpecRefreshResult, error) {
+	cache, err := getRemoteSpecCache(loader)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := loadIndex(loader)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range modules {
+		wanted[name] = true
+	}
+
+	var results []SpecRefreshResult
+	var errs []error
+	for _, m := range index.Modules {
+		if len(wanted) > 0 && !wanted[m.Name] {
+			continue
+		}
+		delete(wanted, m.Name)
+
+		if m.Url == "" {
+			if len(modules) > 0 {
+				errs = append(errs, fmt.Errorf("module %q has no URL", m.Name))
+			}
+			continue
+		}
+
+		result := SpecRefreshResult{Module: m.Name, Url: m.Url}
+		result.Changed, err = cache.Refresh(ctx, client, m.Path, m.Url, m.Sha256)
+		if err != nil {
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("module %q: %w", m.Name, err))
+		}
+		results = append(results, result)
+	}
+
+	for name := range wanted {
+		errs = append(errs, fmt.Errorf("unknown module %q", name))
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// Remove the downloaded specs of the given modules, or all of them if none is given
+func ResetRemoteSpecs(loader *dataloader.LayeredLoader, modules []string) error {
+	cache, err := getRemoteSpecCache(loader)
+	if err != nil {
+		return err
+	}
+
+	index, err := loadIndex(loader)
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range modules {
+		wanted[name] = true
+	}
+
+	var errs []error
+	for _, m := range index.Modules {
+		if len(wanted) == 0 || wanted[m.Name] {
+			errs = append(errs, cache.Remove(m.Path))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,366 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Bump whenever the cached format or the way descriptors are generated from the specs changes
+const treeCacheVersion = "2"
+
+const (
+	treeCacheDirEnvVar     = "MGC_SDK_TREE_CACHE_DIR"
+	treeCacheDisableEnvVar = "MGC_SDK_NO_TREE_CACHE"
+)
+
+// Processed module tree: resources, operations, their schemas and links.
+//
+// Schemas are kept raw and only decoded when used, so building the command tree
+// doesn't need to parse the spec nor every schema of the module.
+type treeCacheNode struct {
+	Spec     core.DescriptorSpec `json:"spec"`
+	Children []*treeCacheNode    `json:"children,omitempty"`
+	Executor *treeCacheExecutor  `json:"executor,omitempty"`
+}
+
+type treeCacheExecutor struct {
+	Parameters     json.RawMessage  `json:"parameters"`
+	Configs        json.RawMessage  `json:"configs"`
+	Result         json.RawMessage  `json:"result"`
+	PositionalArgs []string         `json:"positionalArgs,omitempty"`
+	HiddenFlags    []string         `json:"hiddenFlags,omitempty"`
+	Links          []*treeCacheLink `json:"links,omitempty"`
+}
+
+type treeCacheLink struct {
+	Key                  string          `json:"key"`
+	Name                 string          `json:"name"`
+	Description          string          `json:"description"`
+	IsInternal           bool            `json:"isInternal,omitempty"`
+	AdditionalParameters json.RawMessage `json:"additionalParameters"`
+	AdditionalConfigs    json.RawMessage `json:"additionalConfigs"`
+	Result               json.RawMessage `json:"result"`
+	IsTargetTerminator   bool            `json:"isTargetTerminator,omitempty"`
+	// Path of the target executor inside the same module, if it's there.
+	// Otherwise the links of the target are taken from the actual link
+	Target []string `json:"target,omitempty"`
+}
+
+func TreeCacheDir() (string, error) {
+	if os.Getenv(treeCacheDisableEnvVar) != "" {
+		return "", fmt.Errorf("disabled by $%s", treeCacheDisableEnvVar)
+	}
+	if dir := os.Getenv(treeCacheDirEnvVar); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mgc", "tree"), nil
+}
+
+// Identifies the program that processed the specs, as other builds may process them differently
+var treeCacheBuildID = utils.NewLazyLoader(func() string {
+	var parts []string
+	if info, ok := debug.ReadBuildInfo(); ok {
+		parts = append(parts, info.Main.Version)
+		for _, setting := range info.Settings {
+			if strings.HasPrefix(setting.Key, "vcs.") {
+				parts = append(parts, setting.Value)
+			}
+		}
+	}
+	// development builds may have the same version and revision
+	if exe, err := os.Executable(); err == nil {
+		if st, err := os.Stat(exe); err == nil {
+			parts = append(parts, fmt.Sprint(st.Size(), st.ModTime().UnixNano()))
+		}
+	}
+	return strings.Join(parts, " ")
+})
+
+func treeCacheKey(indexModule *indexModuleSpec, extensionPrefix *string, specData []byte) string {
+	h := sha256.New()
+	prefix := ""
+	if extensionPrefix != nil {
+		prefix = *extensionPrefix
+	}
+	indexData, _ := json.Marshal(indexModule)
+	for _, part := range [][]byte{[]byte(treeCacheVersion), []byte(treeCacheBuildID()), []byte(prefix), indexData, specData} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func treeCacheFile(dir, moduleName, key string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", moduleName, key))
+}
+
+func loadTreeCache(dir, moduleName, key string) ([]*treeCacheNode, error) {
+	data, err := os.ReadFile(treeCacheFile(dir, moduleName, key))
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*treeCacheNode
+	if err = json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// Older entries of the same module are removed, as they can't be used anymore
+func saveTreeCache(dir, moduleName, key string, nodes []*treeCacheNode) error {
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file := treeCacheFile(dir, moduleName, key)
+	if err = writeFileAtomic(file, data); err != nil {
+		return err
+	}
+
+	if old, err := filepath.Glob(filepath.Join(dir, moduleName+"-*.json")); err == nil {
+		for _, f := range old {
+			if f != file && len(filepath.Base(f)) == len(filepath.Base(file)) {
+				_ = os.Remove(f)
+			}
+		}
+	}
+	return nil
+}
+
+// Schemas are cached as they are, with their references. As only "$ref" is serialized for
+// references, their values are kept aside, once per reference, and set back when decoding.
+type cachedSchema struct {
+	Schema *openapi3.Schema            `json:"schema"`
+	Refs   map[string]*openapi3.Schema `json:"refs,omitempty"`
+}
+
+// Direct children of the schema, the same ones serialized by openapi3.Schema
+func visitChildSchemaRefs(s *openapi3.Schema, cb func(ref *openapi3.SchemaRef)) {
+	for _, refs := range []openapi3.SchemaRefs{s.OneOf, s.AnyOf, s.AllOf} {
+		for _, ref := range refs {
+			if ref != nil {
+				cb(ref)
+			}
+		}
+	}
+	for _, ref := range []*openapi3.SchemaRef{s.Not, s.Items, s.AdditionalProperties.Schema} {
+		if ref != nil {
+			cb(ref)
+		}
+	}
+	for _, ref := range s.Properties {
+		if ref != nil {
+			cb(ref)
+		}
+	}
+}
+
+func encodeCachedSchema(s *core.Schema) (json.RawMessage, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	cached := cachedSchema{Schema: (*openapi3.Schema)(s), Refs: map[string]*openapi3.Schema{}}
+	visited := map[*openapi3.Schema]bool{}
+	var collect func(s *openapi3.Schema)
+	collect = func(s *openapi3.Schema) {
+		if s == nil || visited[s] {
+			return
+		}
+		visited[s] = true
+		visitChildSchemaRefs(s, func(ref *openapi3.SchemaRef) {
+			if ref.Ref != "" && ref.Value != nil {
+				if _, ok := cached.Refs[ref.Ref]; !ok {
+					cached.Refs[ref.Ref] = ref.Value
+				}
+			}
+			collect(ref.Value)
+		})
+	}
+	collect(cached.Schema)
+
+	return json.Marshal(cached)
+}
+
+func decodeCachedSchema(data json.RawMessage) (*core.Schema, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	cached := cachedSchema{}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	if cached.Schema == nil {
+		return nil, fmt.Errorf("missing cached schema")
+	}
+
+	var err error
+	resolved := map[string]bool{}
+	var resolve func(s *openapi3.Schema)
+	resolve = func(s *openapi3.Schema) {
+		visitChildSchemaRefs(s, func(ref *openapi3.SchemaRef) {
+			if ref.Ref == "" {
+				if ref.Value != nil {
+					resolve(ref.Value)
+				}
+				return
+			}
+			value, ok := cached.Refs[ref.Ref]
+			if !ok {
+				err = fmt.Errorf("missing cached schema of %q", ref.Ref)
+				return
+			}
+			// all references share the same value, like the actual ones
+			ref.Value = value
+			if !resolved[ref.Ref] {
+				resolved[ref.Ref] = true
+				resolve(value)
+			}
+		})
+	}
+	resolve(cached.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return (*core.Schema)(cached.Schema), nil
+}
+
+func descriptorCacheSpec(desc core.Descriptor) core.DescriptorSpec {
+	spec := desc.DescriptorSpec()
+	spec.Name = desc.Name()
+	spec.Description = desc.Description()
+	spec.Summary = desc.Summary()
+	spec.IsInternal = utils.BoolPtr(desc.IsInternal())
+	spec.Scopes = desc.Scopes()
+	spec.GroupID = desc.GroupID()
+	return spec
+}
+
+func visitAllChildren(group core.Grouper) (children []core.Descriptor, err error) {
+	_, err = group.VisitChildren(func(child core.Descriptor) (bool, error) {
+		children = append(children, child)
+		return true, nil
+	})
+	return
+}
+
+type linkerWithTarget interface {
+	Target() core.Executor
+}
+
+// Serialize the descriptors of the actual module children
+func newTreeCacheNodes(children []core.Descriptor) ([]*treeCacheNode, error) {
+	paths := map[core.Executor][]string{}
+	var collectPaths func(children []core.Descriptor, path []string) error
+	collectPaths = func(children []core.Descriptor, path []string) error {
+		for _, child := range children {
+			childPath := slices.Concat(path, []string{child.Name()})
+			if exec, ok := child.(core.Executor); ok {
+				// only used as map keys, which must be comparable
+				if reflect.ValueOf(exec).Comparable() {
+					paths[exec] = childPath
+				}
+			} else if group, ok := child.(core.Grouper); ok {
+				grandChildren, err := visitAllChildren(group)
+				if err != nil {
+					return err
+				}
+				if err = collectPaths(grandChildren, childPath); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := collectPaths(children, nil); err != nil {
+		return nil, err
+	}
+
+	var newNodes func(children []core.Descriptor) ([]*treeCacheNode, error)
+	newNodes = func(children []core.Descriptor) ([]*treeCacheNode, error) {
+		nodes := make([]*treeCacheNode, 0, len(children))
+		for _, child := range children {
+			node := &treeCacheNode{Spec: descriptorCacheSpec(child)}
+			var err error
+			if exec, ok := child.(core.Executor); ok {
+				node.Executor, err = newTreeCacheExecutor(exec, paths)
+			} else if group, ok := child.(core.Grouper); ok {
+				var grandChildren []core.Descriptor
+				if grandChildren, err = visitAllChildren(group); err == nil {
+					node.Children, err = newNodes(grandChildren)
+				}
+			} else {
+				err = fmt.Errorf("child %v not group/executor", child)
+			}
+			if err != nil {
+				return nil, &utils.ChainedError{Name: child.Name(), Err: err}
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes, nil
+	}
+	return newNodes(children)
+}
+
+func newTreeCacheExecutor(exec core.Executor, paths map[core.Executor][]string) (result *treeCacheExecutor, err error) {
+	result = &treeCacheExecutor{
+		PositionalArgs: exec.PositionalArgs(),
+		HiddenFlags:    exec.HiddenFlags(),
+	}
+	if result.Parameters, err = encodeCachedSchema(exec.ParametersSchema()); err != nil {
+		return
+	}
+	if result.Configs, err = encodeCachedSchema(exec.ConfigsSchema()); err != nil {
+		return
+	}
+	if result.Result, err = encodeCachedSchema(exec.ResultSchema()); err != nil {
+		return
+	}
+
+	links := exec.Links()
+	for _, pair := range utils.SortedMapIterator(links) {
+		key, link := pair.Key, pair.Value
+		cached := &treeCacheLink{
+			Key:                key,
+			Name:               link.Name(),
+			Description:        link.Description(),
+			IsInternal:         link.IsInternal(),
+			IsTargetTerminator: link.IsTargetTerminatorExecutor(),
+		}
+		if cached.AdditionalParameters, err = encodeCachedSchema(link.AdditionalParametersSchema()); err != nil {
+			return
+		}
+		if cached.AdditionalConfigs, err = encodeCachedSchema(link.AdditionalConfigsSchema()); err != nil {
+			return
+		}
+		if cached.Result, err = encodeCachedSchema(link.ResultSchema()); err != nil {
+			return
+		}
+		if l, ok := link.(linkerWithTarget); ok && reflect.ValueOf(l.Target()).Comparable() {
+			cached.Target = paths[l.Target()]
+		}
+		result.Links = append(result.Links, cached)
+	}
+	return
+}
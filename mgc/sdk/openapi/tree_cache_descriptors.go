@@ -0,0 +1,276 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+// Descriptors built from the tree cache. The actual module, which parses the spec,
+// is only loaded when an executor runs or when something not cached is needed.
+type cachedModuleTree struct {
+	actual    core.Grouper
+	executors map[string]*cachedExecutor
+}
+
+func cachedPathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+func newCachedModuleChildren(actual core.Grouper, nodes []*treeCacheNode) []core.Descriptor {
+	tree := &cachedModuleTree{actual: actual, executors: map[string]*cachedExecutor{}}
+	return tree.newChildren(nodes, nil)
+}
+
+func (t *cachedModuleTree) newChildren(nodes []*treeCacheNode, path []string) []core.Descriptor {
+	children := make([]core.Descriptor, 0, len(nodes))
+	for _, node := range nodes {
+		childPath := append(path[:len(path):len(path)], node.Spec.Name)
+		if node.Executor != nil {
+			exec := newCachedExecutor(t, childPath, node)
+			t.executors[cachedPathKey(childPath)] = exec
+			children = append(children, exec)
+		} else {
+			grandChildren := t.newChildren(node.Children, childPath)
+			children = append(children, core.NewSimpleGrouper(node.Spec, func() ([]core.Descriptor, error) {
+				return grandChildren, nil
+			}))
+		}
+	}
+	return children
+}
+
+func lazyCachedSchema(data []byte, name string, fallback func() (*core.Schema, error)) func() *core.Schema {
+	return utils.NewLazyLoader(func() *core.Schema {
+		s, err := decodeCachedSchema(data)
+		if err == nil {
+			return s
+		}
+		logger().Debugw("invalid cached schema, using the actual one", "name", name, "error", err)
+		if s, err = fallback(); err != nil {
+			logger().Warnw("unable to load schema", "name", name, "error", err)
+		}
+		return s
+	})
+}
+
+type cachedExecutor struct {
+	core.SimpleDescriptor
+	tree             *cachedModuleTree
+	path             []string
+	node             *treeCacheExecutor
+	parametersSchema func() *core.Schema
+	configsSchema    func() *core.Schema
+	resultSchema     func() *core.Schema
+	links            func() core.Links
+	actual           func() (core.Executor, error)
+}
+
+func newCachedExecutor(tree *cachedModuleTree, path []string, node *treeCacheNode) *cachedExecutor {
+	o := &cachedExecutor{
+		SimpleDescriptor: core.SimpleDescriptor{Spec: node.Spec},
+		tree:             tree,
+		path:             path,
+		node:             node.Executor,
+	}
+	o.actual = utils.NewLazyLoaderWithError(func() (core.Executor, error) {
+		return core.GetExecutorByPath(tree.actual, path...)
+	})
+	name := strings.Join(path, " ")
+	o.parametersSchema = lazyCachedSchema(o.node.Parameters, name, func() (*core.Schema, error) {
+		exec, err := o.actual()
+		if err != nil {
+			return nil, err
+		}
+		return exec.ParametersSchema(), nil
+	})
+	o.configsSchema = lazyCachedSchema(o.node.Configs, name, func() (*core.Schema, error) {
+		exec, err := o.actual()
+		if err != nil {
+			return nil, err
+		}
+		return exec.ConfigsSchema(), nil
+	})
+	o.resultSchema = lazyCachedSchema(o.node.Result, name, func() (*core.Schema, error) {
+		exec, err := o.actual()
+		if err != nil {
+			return nil, err
+		}
+		return exec.ResultSchema(), nil
+	})
+	o.links = utils.NewLazyLoader(func() core.Links {
+		links := make(core.Links, len(o.node.Links))
+		for _, link := range o.node.Links {
+			links[link.Key] = newCachedLinker(o, link)
+		}
+		return links
+	})
+	return o
+}
+
+func (o *cachedExecutor) ParametersSchema() *core.Schema {
+	return o.parametersSchema()
+}
+
+func (o *cachedExecutor) ConfigsSchema() *core.Schema {
+	return o.configsSchema()
+}
+
+func (o *cachedExecutor) ResultSchema() *core.Schema {
+	return o.resultSchema()
+}
+
+func (o *cachedExecutor) PositionalArgs() []string {
+	return o.node.PositionalArgs
+}
+
+func (o *cachedExecutor) HiddenFlags() []string {
+	return o.node.HiddenFlags
+}
+
+func (o *cachedExecutor) Links() core.Links {
+	return o.links()
+}
+
+func (o *cachedExecutor) Related() map[string]core.Executor {
+	exec, err := o.actual()
+	if err != nil {
+		logger().Warnw("unable to load executor", "path", o.path, "error", err)
+		return map[string]core.Executor{}
+	}
+	return exec.Related()
+}
+
+func (o *cachedExecutor) Execute(ctx context.Context, parameters core.Parameters, configs core.Configs) (core.Result, error) {
+	exec, err := o.actual()
+	if err != nil {
+		return nil, err
+	}
+	result, err := exec.Execute(ctx, parameters, configs)
+	return core.ExecutorWrapResult(o, result, err)
+}
+
+func (o *cachedExecutor) EmptyResult() core.Result {
+	exec, err := o.actual()
+	if err != nil {
+		logger().Warnw("unable to load executor", "path", o.path, "error", err)
+		return nil
+	}
+	return exec.EmptyResult()
+}
+
+// The actual executor, so its optional interfaces, such as core.ConfirmableExecutor, are found
+// by core.ExecutorAs(). It's nil if it couldn't be loaded
+func (o *cachedExecutor) Unwrap() core.Executor {
+	exec, err := o.actual()
+	if err != nil {
+		logger().Warnw("unable to load executor", "path", o.path, "error", err)
+		return nil
+	}
+	return exec
+}
+
+var _ core.ExecutorWrapper = (*cachedExecutor)(nil)
+
+type cachedLinker struct {
+	owner                      *cachedExecutor
+	node                       *treeCacheLink
+	additionalParametersSchema func() *core.Schema
+	additionalConfigsSchema    func() *core.Schema
+	resultSchema               func() *core.Schema
+	actual                     func() (core.Linker, error)
+}
+
+func newCachedLinker(owner *cachedExecutor, node *treeCacheLink) *cachedLinker {
+	l := &cachedLinker{owner: owner, node: node}
+	l.actual = utils.NewLazyLoaderWithError(func() (core.Linker, error) {
+		exec, err := owner.actual()
+		if err != nil {
+			return nil, err
+		}
+		link, ok := exec.Links()[node.Key]
+		if !ok {
+			return nil, fmt.Errorf("%s: missing link %q", strings.Join(owner.path, " "), node.Key)
+		}
+		return link, nil
+	})
+	name := strings.Join(owner.path, " ") + " ! " + node.Key
+	l.additionalParametersSchema = lazyCachedSchema(node.AdditionalParameters, name, func() (*core.Schema, error) {
+		link, err := l.actual()
+		if err != nil {
+			return nil, err
+		}
+		return link.AdditionalParametersSchema(), nil
+	})
+	l.additionalConfigsSchema = lazyCachedSchema(node.AdditionalConfigs, name, func() (*core.Schema, error) {
+		link, err := l.actual()
+		if err != nil {
+			return nil, err
+		}
+		return link.AdditionalConfigsSchema(), nil
+	})
+	l.resultSchema = lazyCachedSchema(node.Result, name, func() (*core.Schema, error) {
+		link, err := l.actual()
+		if err != nil {
+			return nil, err
+		}
+		return link.ResultSchema(), nil
+	})
+	return l
+}
+
+func (l *cachedLinker) Name() string {
+	return l.node.Name
+}
+
+func (l *cachedLinker) Description() string {
+	return l.node.Description
+}
+
+func (l *cachedLinker) IsInternal() bool {
+	return l.node.IsInternal
+}
+
+func (l *cachedLinker) AdditionalParametersSchema() *core.Schema {
+	return l.additionalParametersSchema()
+}
+
+func (l *cachedLinker) AdditionalConfigsSchema() *core.Schema {
+	return l.additionalConfigsSchema()
+}
+
+func (l *cachedLinker) ResultSchema() *core.Schema {
+	return l.resultSchema()
+}
+
+func (l *cachedLinker) CreateExecutor(originalResult core.Result) (core.Executor, error) {
+	link, err := l.actual()
+	if err != nil {
+		return nil, err
+	}
+	return link.CreateExecutor(originalResult)
+}
+
+// Targets in the same module are cached as well, others are loaded
+func (l *cachedLinker) Links() core.Links {
+	if l.node.Target != nil {
+		if target, ok := l.owner.tree.executors[cachedPathKey(l.node.Target)]; ok {
+			return target.Links()
+		}
+	}
+	link, err := l.actual()
+	if err != nil {
+		logger().Warnw("unable to load link", "path", l.owner.path, "link", l.node.Key, "error", err)
+		return core.Links{}
+	}
+	return link.Links()
+}
+
+func (l *cachedLinker) IsTargetTerminatorExecutor() bool {
+	return l.node.IsTargetTerminator
+}
+
+var _ core.Linker = (*cachedLinker)(nil)
@@ -0,0 +1,125 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+)
+
+func getSourceModule(t *testing.T, name string) core.Grouper {
+	prefix := "x-mgc"
+	child, err := NewSource(GetEmbedLoader(), &prefix).GetChildByName(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return child.(core.Grouper)
+}
+
+func collectExecutors(t *testing.T, group core.Grouper) map[string]core.Executor {
+	result := map[string]core.Executor{}
+	_, err := core.VisitAllExecutors(group, nil, true, func(exec core.Executor, path []string) (bool, error) {
+		result[strings.Join(path, " ")] = exec
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return result
+}
+
+func mustEncodeCachedSchema(t *testing.T, s *core.Schema) []byte {
+	data, err := encodeCachedSchema(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return data
+}
+
+func mustMarshal(t *testing.T, s *core.Schema) []byte {
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return data
+}
+
+func TestTreeCache(t *testing.T) {
+	t.Setenv(treeCacheDirEnvVar, t.TempDir())
+
+	// container-registry has schemas with references
+	hasRefs := false
+	for _, module := range []string{"block-storage", "container-registry"} {
+		hasRefs = checkTreeCache(t, module) || hasRefs
+	}
+	if !hasRefs {
+		t.Errorf("expected schemas with references to be compared")
+	}
+}
+
+// The cached descriptors must be the same as the actual ones, returns whether any schema has references
+func checkTreeCache(t *testing.T, module string) (hasRefs bool) {
+	t.Setenv(treeCacheDisableEnvVar, "1")
+	expected := collectExecutors(t, getSourceModule(t, module))
+	t.Setenv(treeCacheDisableEnvVar, "")
+
+	// first one creates the cache
+	_ = collectExecutors(t, getSourceModule(t, module))
+	got := collectExecutors(t, getSourceModule(t, module))
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d executors, got %d", len(expected), len(got))
+	}
+
+	for path, expectedExec := range expected {
+		exec, ok := got[path]
+		if !ok {
+			t.Errorf("%s: missing executor", path)
+			continue
+		}
+		if _, ok := exec.(*cachedExecutor); !ok {
+			t.Errorf("%s: expected cached executor, got %T", path, exec)
+			continue
+		}
+		if exec.Description() != expectedExec.Description() || exec.Summary() != expectedExec.Summary() {
+			t.Errorf("%s: descriptions differ", path)
+		}
+		for name, schemas := range map[string][2]*core.Schema{
+			"parameters": {exec.ParametersSchema(), expectedExec.ParametersSchema()},
+			"configs":    {exec.ConfigsSchema(), expectedExec.ConfigsSchema()},
+			"result":     {exec.ResultSchema(), expectedExec.ResultSchema()},
+		} {
+			// as in dump-tree, then with the values of the references
+			data, expectedData := mustMarshal(t, schemas[0]), mustMarshal(t, schemas[1])
+			if !bytes.Equal(data, expectedData) {
+				t.Errorf("%s: %s schemas differ:\n%s\n%s", path, name, data, expectedData)
+			}
+			if !bytes.Equal(mustEncodeCachedSchema(t, schemas[0]), mustEncodeCachedSchema(t, schemas[1])) {
+				t.Errorf("%s: %s schema references differ", path, name)
+			}
+			hasRefs = hasRefs || bytes.Contains(expectedData, []byte(`"$ref"`))
+		}
+		if !slices.Equal(exec.PositionalArgs(), expectedExec.PositionalArgs()) {
+			t.Errorf("%s: positional args differ", path)
+		}
+		for name, expectedLink := range expectedExec.Links() {
+			link, ok := exec.Links()[name]
+			if !ok {
+				t.Errorf("%s: missing link %q", path, name)
+				continue
+			}
+			if link.IsTargetTerminatorExecutor() != expectedLink.IsTargetTerminatorExecutor() || len(link.Links()) != len(expectedLink.Links()) {
+				t.Errorf("%s: link %q differs", path, name)
+			}
+		}
+		_, isConfirmable := core.ExecutorAs[core.ConfirmableExecutor](exec)
+		_, expectedConfirmable := core.ExecutorAs[core.ConfirmableExecutor](expectedExec)
+		if isConfirmable != expectedConfirmable {
+			t.Errorf("%s: expected the actual executor to be unwrapped", path)
+		}
+	}
+	return hasRefs
+}
@@ -36,6 +36,7 @@ type Sdk struct {
 	httpClient     *mgcHttpPkg.Client
 	config         *config.Config
 	refResolver    core.RefPathResolver
+	openApiLoader  *dataloader.LayeredLoader
 }
 
 type contextKey string
@@ -91,17 +92,19 @@ func (o *Sdk) WrapContext(ctx context.Context) context.Context {
 	return ctx
 }
 
-func (o *Sdk) newOpenApiSource() core.Grouper {
-	embedLoader := openapi.GetEmbedLoader()
+// Embedded specs, overridden by $MGC_SDK_OPENAPI_DIR (or ./openapis), overridden by
+// the specs downloaded from the module URLs, see openapi.NewLayeredLoader()
+func (o *Sdk) OpenApiLoader() *dataloader.LayeredLoader {
+	if o.openApiLoader == nil {
+		o.openApiLoader = openapi.NewLayeredLoader()
+	}
+	return o.openApiLoader
+}
 
+func (o *Sdk) newOpenApiSource() core.Grouper {
 	// TODO: are these going to be fixed? configurable?
 	extensionPrefix := "x-mgc"
-	var loader dataloader.Loader
-	if embedLoader != nil {
-		loader = dataloader.NewMergeLoader(embedLoader)
-	}
-
-	return openapi.NewSource(loader, &extensionPrefix)
+	return openapi.NewSource(o.OpenApiLoader(), &extensionPrefix)
 }
 
 func (o *Sdk) RefResolver() core.RefPathResolver {
@@ -130,11 +133,22 @@ func (o *Sdk) Group() core.Grouper {
 	return o.group
 }
 
-func newHttpTransport(version string) http.RoundTripper {
+func newHttpTransport(version string, cfg *config.Config) http.RoundTripper {
 	userAgent := fmt.Sprintf("MgcCLI/%s (%s; %s)", version, runtime.GOOS, runtime.GOARCH)
 	// To avoid creating a transport with zero values, we leverage
-	// DefaultTransport (exemple: `Proxy: ProxyFromEnvironment`)
-	transport := mgcHttpPkg.DefaultTransport()
+	// DefaultTransport (exemple: `Proxy: ProxyFromEnvironment`), changed by the workspace
+	// proxy, CA bundle, client certificate and resolve settings
+	transportConfig, err := cfg.TransportConfig()
+	var transport http.RoundTripper
+	if err == nil {
+		transport, err = mgcHttpPkg.NewTransport(transportConfig)
+	}
+	if err != nil {
+		// requests will fail, errors can't be returned here
+		transport = mgcHttpPkg.NewErrorTransport(fmt.Errorf("invalid network configuration: %w", err))
+	}
+	// innermost, so every retry and request after a token refresh is traced
+	transport = mgcHttpPkg.NewDefaultClientTracer(transport)
 	transport = mgcHttpPkg.NewDefaultClientLogger(transport)
 	transport = newDefaultSdkTransport(transport, userAgent)
 	transport = mgcHttpPkg.NewDefaultClientRetryer(transport)
@@ -154,7 +168,7 @@ func (o *Sdk) ProfileManager() *profile_manager.ProfileManager {
 
 func (o *Sdk) Auth() *auth.Auth {
 	if o.auth == nil {
-		client := &http.Client{Transport: newHttpTransport(o.version)}
+		client := &http.Client{Transport: newHttpTransport(o.version, o.Config())}
 		o.auth = auth.New(authConfigMap, client, o.ProfileManager(), o.Config())
 	}
 
@@ -163,7 +177,7 @@ func (o *Sdk) Auth() *auth.Auth {
 
 func (o *Sdk) HttpClient() *mgcHttpPkg.Client {
 	if o.httpClient == nil {
-		transport := o.addHttpRefreshHandler(newHttpTransport(o.version))
+		transport := o.addHttpRefreshHandler(newHttpTransport(o.version, o.Config()))
 		o.httpClient = mgcHttpPkg.NewClient(transport)
 	}
 	return o.httpClient
@@ -0,0 +1,214 @@
+package events
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+const pageSize = 50
+
+var listPath = []string{"audit", "events", "list"}
+
+type Config struct {
+	Region string `json:"region,omitempty" jsonschema:"description=Region to reach the service,default=br-se1,enum=br-ne1,enum=br-se1,enum=br-mgl1,enum=global"`
+	Env    string `json:"env,omitempty" jsonschema:"description=Environment to use,default=prod,enum=prod,enum=pre-prod"`
+
+	// See more about the 'squash' directive here: https://pkg.go.dev/github.com/mitchellh/mapstructure#hdr-Embedded_Structs_and_Squashing
+	config.NetworkConfig `json:",squash"` // nolint
+}
+
+// Configs to be given to the OpenAPI operation
+func (c Config) configs() core.Configs {
+	configs := core.Configs{}
+	if c.Region != "" {
+		configs["region"] = c.Region
+	}
+	if c.Env != "" {
+		configs["env"] = c.Env
+	}
+	if c.ServerUrl != "" {
+		configs["serverUrl"] = c.ServerUrl
+	}
+	return configs
+}
+
+// Same filters of "audit events list"
+type Filters struct {
+	TypeLike      string `json:"type_like,omitempty" jsonschema_description:"Type of event related to the originating occurrence ('like' operation)"`
+	ProductLike   string `json:"product_like,omitempty" jsonschema_description:"In which producer product an event occurred ('like' operation)"`
+	SourceLike    string `json:"source_like,omitempty" jsonschema_description:"Context in which the event occurred ('like' operation)"`
+	AuthID        string `json:"authid,omitempty" jsonschema_description:"Identification of the actor of the action"`
+	CorrelationID string `json:"correlationid,omitempty" jsonschema_description:"Correlation between event chain"`
+}
+
+func (f Filters) parameters() core.Parameters {
+	parameters := core.Parameters{}
+	for name, value := range map[string]string{
+		"type__like":    f.TypeLike,
+		"product__like": f.ProductLike,
+		"source__like":  f.SourceLike,
+		"authid":        f.AuthID,
+		"correlationid": f.CorrelationID,
+	} {
+		if value != "" {
+			parameters[name] = value
+		}
+	}
+	return parameters
+}
+
+// As returned by the API, which already follows the CloudEvents spec
+type Event map[string]any
+
+func (e Event) ID() string {
+	id, _ := e["id"].(string)
+	return id
+}
+
+func (e Event) Time() time.Time {
+	s, _ := e["time"].(string)
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+type eventsPage struct {
+	Results []Event `json:"results"`
+}
+
+type listPageFunc func(ctx context.Context, offset int) ([]Event, error)
+
+func newListPageFunc(filters Filters, cfg Config) listPageFunc {
+	return func(ctx context.Context, offset int) ([]Event, error) {
+		parameters := filters.parameters()
+		parameters["_limit"] = pageSize
+		parameters["_offset"] = offset
+
+		result, err := core.ExecuteByPath(ctx, listPath, parameters, cfg.configs())
+		if err != nil {
+			return nil, err
+		}
+		resultWithValue, ok := core.ResultAs[core.ResultWithValue](result)
+		if !ok {
+			return nil, fmt.Errorf("audit events list returned no value")
+		}
+		page, err := utils.DecodeNewValue[eventsPage](resultWithValue.Value())
+		if err != nil {
+			return nil, err
+		}
+		return page.Results, nil
+	}
+}
+
+// Events with time in [start, end), or after start if end is zero, sorted by time.
+// The API lists the newest events first, so paging stops at the first page with only
+// events older than start. Events created while paging shift the offsets and are listed
+// again, they're skipped by ID
+func listEventsSince(ctx context.Context, listPage listPageFunc, start, end time.Time) ([]Event, error) {
+	var result []Event
+	listed := map[string]bool{}
+	for offset := 0; ; offset += pageSize {
+		page, err := listPage(ctx, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		reachedStart := len(page) > 0
+		for _, e := range page {
+			if id := e.ID(); id != "" {
+				if listed[id] {
+					continue
+				}
+				listed[id] = true
+			}
+
+			t := e.Time()
+			if t.Before(start) {
+				continue
+			}
+			reachedStart = false
+			if end.IsZero() || t.Before(end) {
+				result = append(result, e)
+			}
+		}
+
+		if len(page) < pageSize || reachedStart {
+			break
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b Event) int {
+		if c := a.Time().Compare(b.Time()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID(), b.ID())
+	})
+	return result, nil
+}
+
+// The extension attributes, such as "tenantid", are kept
+func toCloudEvent(e Event) map[string]any {
+	result := make(map[string]any, len(e)+2)
+	for k, v := range e {
+		if v != nil {
+			result[k] = v
+		}
+	}
+	if _, ok := result["specversion"]; !ok {
+		result["specversion"] = "1.0"
+	}
+	if _, ok := result["data"]; ok {
+		result["datacontenttype"] = "application/json"
+	}
+	return result
+}
+
+func writeNDJSON(w io.Writer, e Event) error {
+	data, err := json.Marshal(toCloudEvent(e))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+var csvColumns = []string{"id", "time", "type", "source", "subject", "product", "region", "authid", "authtype", "tenantid", "correlationid", "data"}
+
+func csvRecord(e Event) []string {
+	record := make([]string, len(csvColumns))
+	for i, column := range csvColumns {
+		switch v := e[column].(type) {
+		case nil:
+		case string:
+			record[i] = v
+		default:
+			data, _ := json.Marshal(v)
+			record[i] = string(data)
+		}
+	}
+	return record
+}
+
+func writeCSV(w *csv.Writer, events []Event, header bool) error {
+	if header {
+		if err := w.Write(csvColumns); err != nil {
+			return err
+		}
+	}
+	for _, e := range events {
+		if err := w.Write(csvRecord(e)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,199 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var baseTime = time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func newEvent(id string, minutes int) Event {
+	return Event{"id": id, "time": baseTime.Add(time.Duration(minutes) * time.Minute).Format(time.RFC3339Nano)}
+}
+
+// Serves the events newest first
+func fakeListPage(events *[]Event, calls *int) listPageFunc {
+	return func(ctx context.Context, offset int) ([]Event, error) {
+		*calls++
+		newest := make([]Event, len(*events))
+		for i, e := range *events {
+			newest[len(newest)-1-i] = e
+		}
+		if offset >= len(newest) {
+			return nil, nil
+		}
+		return newest[offset:min(offset+pageSize, len(newest))], nil
+	}
+}
+
+func eventIDs(events []Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID()
+	}
+	return ids
+}
+
+func TestListEventsSince(t *testing.T) {
+	var events []Event
+	for i := 0; i < 3*pageSize; i++ {
+		events = append(events, newEvent(fmt.Sprintf("e%03d", i), i))
+	}
+
+	calls := 0
+	got, err := listEventsSince(context.Background(), fakeListPage(&events, &calls), baseTime.Add(140*time.Minute), baseTime.Add(145*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []string{"e140", "e141", "e142", "e143", "e144"}
+	if ids := eventIDs(got); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+	if calls != 2 {
+		t.Errorf("expected the paging to stop at the first page older than start, got %d calls", calls)
+	}
+
+	calls = 0
+	got, err = listEventsSince(context.Background(), fakeListPage(&events, &calls), baseTime.Add(10*time.Minute), time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 3*pageSize-10 || got[0].ID() != "e010" {
+		t.Errorf("expected the events from e010 on, got %v", eventIDs(got))
+	}
+	if calls != 4 {
+		t.Errorf("expected the pages down to start to be listed, got %d calls", calls)
+	}
+}
+
+func TestListEventsSinceShiftedPages(t *testing.T) {
+	var events []Event
+	for i := 0; i < 2*pageSize; i++ {
+		events = append(events, newEvent(fmt.Sprintf("e%03d", i), i))
+	}
+
+	// new events arrive after the first page, shifting the next ones
+	calls := 0
+	listPage := fakeListPage(&events, &calls)
+	shifted := func(ctx context.Context, offset int) ([]Event, error) {
+		if offset > 0 && len(events) == 2*pageSize {
+			events = append(events, newEvent("new1", 2*pageSize), newEvent("new2", 2*pageSize))
+		}
+		return listPage(ctx, offset)
+	}
+
+	got, err := listEventsSince(context.Background(), shifted, baseTime, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 2*pageSize {
+		t.Errorf("expected each listed event once, got %d: %v", len(got), eventIDs(got))
+	}
+}
+
+func TestTailerPoll(t *testing.T) {
+	events := []Event{newEvent("a", 0), newEvent("b", 1)}
+	now := baseTime.Add(2 * time.Minute)
+	calls := 0
+	out := &bytes.Buffer{}
+	tl := &tailer{
+		listPage: fakeListPage(&events, &calls),
+		window:   5 * time.Minute,
+		seen:     map[string]time.Time{},
+		now:      func() time.Time { return now },
+		out:      out,
+	}
+
+	printed := func() []string {
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			e := Event{}
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("invalid line %q: %s", line, err)
+			}
+			if e["specversion"] != "1.0" {
+				t.Errorf("expected specversion in %q", line)
+			}
+			ids = append(ids, e.ID())
+		}
+		out.Reset()
+		return ids
+	}
+
+	if err := tl.poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ids := printed(); !reflect.DeepEqual(ids, []string{"a", "b"}) {
+		t.Errorf("expected a and b, got %v", ids)
+	}
+
+	// "b" is still in the window and must not be printed again, "a" is out of it
+	events = append(events, newEvent("c", 3), newEvent("d", 3))
+	now = baseTime.Add(5*time.Minute + 30*time.Second)
+	if err := tl.poll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ids := printed(); !reflect.DeepEqual(ids, []string{"c", "d"}) {
+		t.Errorf("expected c and d, got %v", ids)
+	}
+	if _, ok := tl.seen["a"]; ok {
+		t.Errorf("expected a to be forgotten once out of the window")
+	}
+}
+
+func TestNextCheckpoint(t *testing.T) {
+	start := baseTime
+	overlap := 10 * time.Minute
+	if got := nextCheckpoint(nil, start, overlap, nil); !got.Time.Equal(start) || !got.Start.Equal(start) || len(got.IDs) != 0 {
+		t.Errorf("expected the start without IDs, got %+v", got)
+	}
+
+	previous := &checkpoint{Start: start, Time: baseTime.Add(time.Minute), IDs: map[string]time.Time{"a": baseTime.Add(time.Minute)}}
+	got := nextCheckpoint(previous, start, overlap, nil)
+	if !reflect.DeepEqual(got, previous) {
+		t.Errorf("expected the previous checkpoint, got %+v", got)
+	}
+
+	// "b" arrived late, before the checkpoint time
+	got = nextCheckpoint(previous, start, overlap, []Event{newEvent("b", 0), newEvent("c", 1)})
+	expected := &checkpoint{Start: start, Time: previous.Time, IDs: map[string]time.Time{
+		"a": baseTime.Add(time.Minute),
+		"b": baseTime,
+		"c": baseTime.Add(time.Minute),
+	}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the IDs to be merged, got %+v", got)
+	}
+
+	// only the IDs within the overlap are kept
+	got = nextCheckpoint(previous, start, overlap, []Event{newEvent("d", 5), newEvent("e", 11)})
+	expected = &checkpoint{Start: start, Time: baseTime.Add(11 * time.Minute), IDs: map[string]time.Time{
+		"a": baseTime.Add(time.Minute),
+		"d": baseTime.Add(5 * time.Minute),
+		"e": baseTime.Add(11 * time.Minute),
+	}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the IDs within the overlap, got %+v", got)
+	}
+	got = nextCheckpoint(got, start, overlap, []Event{newEvent("f", 20)})
+	if _, ok := got.IDs["a"]; ok || len(got.IDs) != 2 {
+		t.Errorf("expected the IDs out of the overlap to be forgotten, got %+v", got)
+	}
+}
+
+func TestCSVRecord(t *testing.T) {
+	e := Event{"id": "a", "time": "2024-07-01T12:00:00Z", "data": map[string]any{"k": "v"}, "authid": nil}
+	record := csvRecord(e)
+	if len(record) != len(csvColumns) {
+		t.Fatalf("expected %d columns, got %d", len(csvColumns), len(record))
+	}
+	if record[0] != "a" || record[len(record)-1] != `{"k":"v"}` {
+		t.Errorf("unexpected record %v", record)
+	}
+}
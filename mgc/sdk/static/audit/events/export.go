@@ -0,0 +1,239 @@
+package events
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+const (
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+type exportParams struct {
+	Start      string `json:"start,omitempty" jsonschema_description:"Export events from this time on. Required unless the checkpoint file exists, then it's ignored" jsonschema:"format=date-time,example=2024-07-01T00:00:00Z"`
+	End        string `json:"end,omitempty" jsonschema_description:"Export events before this time. Defaults to now" jsonschema:"format=date-time"`
+	Format     string `json:"format,omitempty" jsonschema:"enum=jsonl,enum=csv,default=jsonl" jsonschema_description:"jsonl writes one CloudEvents JSON object per line"`
+	File       string `json:"file,omitempty" jsonschema_description:"File to append the events to. Defaults to the standard output"`
+	Checkpoint string `json:"checkpoint,omitempty" jsonschema_description:"File with the time and IDs of the last exported events. The export continues from it and updates it once the events are written, so the command can be run periodically"`
+	Overlap    string `json:"overlap,omitempty" jsonschema_description:"With a checkpoint, how far before its time the events are listed again, so events listed late are still exported once" jsonschema:"default=15m,example=1h"`
+	Filters
+}
+
+type exportResult struct {
+	Exported   int    `json:"exported"`
+	File       string `json:"file"`
+	Checkpoint string `json:"checkpoint,omitempty"`
+}
+
+// Time of the newest exported event, and the IDs and times of the exported events within
+// the overlap before it. Events are never exported from before Start, the first --start
+type checkpoint struct {
+	Start time.Time            `json:"start"`
+	Time  time.Time            `json:"time"`
+	IDs   map[string]time.Time `json:"ids"`
+}
+
+var getExport = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	executor := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "export",
+			Summary: "Export the events of a time range as CloudEvents JSON lines or CSV",
+			Description: `Page through the events from --start to --end and append them, oldest first,
+to --file as CloudEvents JSON lines or CSV.
+
+With --checkpoint, the time and IDs of the last exported events are saved after they're
+written and the next export continues from there, skipping what was already exported.
+Events may be listed some time after they happen, so the export lists again the --overlap
+before the checkpoint and exports only the events not exported yet, by ID.
+This allows shipping the events from a cron job:
+
+    mgc audit events export --checkpoint ~/audit.checkpoint --file ~/audit.jsonl --start 2024-07-01T00:00:00Z`,
+		},
+		export,
+	)
+	return core.NewExecuteResultOutputOptions(executor, func(exec core.Executor, result core.Result) string {
+		return "template=Exported {{.exported}} events to {{.file}}\n"
+	})
+})
+
+func export(ctx context.Context, params exportParams, cfg Config) (*exportResult, error) {
+	if params.Format != "" && params.Format != formatJSONL && params.Format != formatCSV {
+		return nil, core.UsageError{Err: fmt.Errorf("unknown format %q", params.Format)}
+	}
+
+	end := time.Now()
+	if params.End != "" {
+		var err error
+		if end, err = time.Parse(time.RFC3339Nano, params.End); err != nil {
+			return nil, core.UsageError{Err: fmt.Errorf("invalid end: %w", err)}
+		}
+	}
+
+	overlap, err := parseDuration(params.Overlap, 15*time.Minute)
+	if err != nil {
+		return nil, core.UsageError{Err: fmt.Errorf("invalid overlap: %w", err)}
+	}
+
+	previous, err := readCheckpoint(params.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var start time.Time
+	switch {
+	case previous != nil:
+		start = previous.Time.Add(-overlap)
+		if start.Before(previous.Start) {
+			start = previous.Start
+		}
+	case params.Start != "":
+		if start, err = time.Parse(time.RFC3339Nano, params.Start); err != nil {
+			return nil, core.UsageError{Err: fmt.Errorf("invalid start: %w", err)}
+		}
+	default:
+		return nil, core.UsageError{Err: fmt.Errorf("start is required without a checkpoint")}
+	}
+
+	events, err := listEventsSince(ctx, newListPageFunc(params.Filters, cfg), start, end)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		events = slices.DeleteFunc(events, func(e Event) bool {
+			_, exported := previous.IDs[e.ID()]
+			return exported
+		})
+	}
+	logger().Debugw("exporting audit events", "start", start, "end", end, "events", len(events))
+
+	if err := writeEvents(params.File, params.Format, events); err != nil {
+		return nil, err
+	}
+
+	result := &exportResult{Exported: len(events), File: params.File}
+	if params.Checkpoint != "" {
+		next := nextCheckpoint(previous, start, overlap, events)
+		if err := writeCheckpoint(params.Checkpoint, next); err != nil {
+			return nil, fmt.Errorf("events were exported, but the checkpoint was not saved: %w", err)
+		}
+		result.Checkpoint = next.Time.Format(time.RFC3339Nano)
+	}
+
+	// the summary would be mixed with the events in the standard output
+	if params.File == "" {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func writeEvents(file, format string, events []Event) (err error) {
+	var w io.Writer = os.Stdout
+	header := true
+
+	if file != "" {
+		f, openErr := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if openErr != nil {
+			return openErr
+		}
+		defer func() {
+			if err == nil {
+				err = f.Sync()
+			}
+			err = errors.Join(err, f.Close())
+		}()
+
+		if info, statErr := f.Stat(); statErr == nil && info.Size() > 0 {
+			header = false
+		}
+		w = f
+	}
+
+	if format == formatCSV {
+		return writeCSV(csv.NewWriter(w), events, header)
+	}
+	for _, e := range events {
+		if err := writeNDJSON(w, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The time of the newest exported event, keeping the IDs of the events within the overlap
+func nextCheckpoint(previous *checkpoint, start time.Time, overlap time.Duration, events []Event) *checkpoint {
+	next := &checkpoint{Start: start, Time: start, IDs: map[string]time.Time{}}
+	if previous != nil {
+		next.Start = previous.Start
+		next.Time = previous.Time
+		maps.Copy(next.IDs, previous.IDs)
+	}
+
+	for _, e := range events {
+		if e.Time().After(next.Time) {
+			next.Time = e.Time()
+		}
+		next.IDs[e.ID()] = e.Time()
+	}
+
+	forgetBefore := next.Time.Add(-overlap)
+	maps.DeleteFunc(next.IDs, func(_ string, t time.Time) bool {
+		return t.Before(forgetBefore)
+	})
+	return next
+}
+
+func readCheckpoint(path string) (*checkpoint, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	result := &checkpoint{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %q: %w", path, err)
+	}
+	return result, nil
+}
+
+// Written to a temporary file, then renamed, so it's never left incomplete
+func writeCheckpoint(path string, c *checkpoint) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
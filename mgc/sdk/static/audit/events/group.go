@@ -0,0 +1,18 @@
+package events
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "events"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				getTail(),   // audit events tail
+				getExport(), // audit events export
+			}
+		},
+	)
+})
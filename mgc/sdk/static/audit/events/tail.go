@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type tailParams struct {
+	Follow   bool   `json:"follow,omitempty" jsonschema_description:"Keep polling for new events until interrupted"`
+	Window   string `json:"window,omitempty" jsonschema_description:"How far back each poll looks, such as 10m. It must be longer than the interval plus the delay of the events to be listed" jsonschema:"default=5m,example=10m"`
+	Interval string `json:"interval,omitempty" jsonschema_description:"Time between polls when following, such as 30s" jsonschema:"default=10s,example=30s"`
+	Filters
+}
+
+var getTail = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	return core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "tail",
+			Summary: "Print the recent events, optionally following the new ones",
+			Description: `Print the events of the last --window as one CloudEvents JSON object per line (NDJSON),
+oldest first. With --follow, the API is polled every --interval with a window moving with
+the current time, and only events not printed yet, by ID, are printed.`,
+		},
+		func(ctx context.Context, params tailParams, cfg Config) (core.Value, error) {
+			window, err := parseDuration(params.Window, 5*time.Minute)
+			if err != nil {
+				return nil, core.UsageError{Err: fmt.Errorf("invalid window: %w", err)}
+			}
+			interval, err := parseDuration(params.Interval, 10*time.Second)
+			if err != nil {
+				return nil, core.UsageError{Err: fmt.Errorf("invalid interval: %w", err)}
+			}
+			if params.Follow && interval >= window {
+				return nil, core.UsageError{Err: fmt.Errorf("the window (%s) must be longer than the interval (%s)", window, interval)}
+			}
+
+			t := &tailer{
+				listPage: newListPageFunc(params.Filters, cfg),
+				window:   window,
+				seen:     map[string]time.Time{},
+				now:      time.Now,
+				out:      os.Stdout,
+			}
+			return nil, t.run(ctx, params.Follow, interval)
+		},
+	)
+})
+
+func parseDuration(s string, defaultValue time.Duration) (time.Duration, error) {
+	if s == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("%q must be positive", s)
+	}
+	return d, err
+}
+
+type tailer struct {
+	listPage listPageFunc
+	window   time.Duration
+	// printed event IDs by their time, forgotten once they're out of the window
+	seen map[string]time.Time
+	now  func() time.Time
+	out  io.Writer
+}
+
+// Print the events in the window that were not printed yet
+func (t *tailer) poll(ctx context.Context) error {
+	start := t.now().Add(-t.window)
+	events, err := listEventsSince(ctx, t.listPage, start, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	for id, eventTime := range t.seen {
+		if eventTime.Before(start) {
+			delete(t.seen, id)
+		}
+	}
+
+	for _, e := range events {
+		if _, ok := t.seen[e.ID()]; ok {
+			continue
+		}
+		if err := writeNDJSON(t.out, e); err != nil {
+			return err
+		}
+		t.seen[e.ID()] = e.Time()
+	}
+
+	logger().Debugw("polled audit events", "start", start, "listed", len(events), "seen", len(t.seen))
+	return nil
+}
+
+func (t *tailer) run(ctx context.Context, follow bool, interval time.Duration) error {
+	if err := t.poll(ctx); err != nil || !follow {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			// the next polls cover the same window, so events are only lost if polls fail for longer than it
+			if err := t.poll(ctx); err != nil {
+				logger().Warnw("unable to poll audit events", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/audit/events"
+)
+
+// Named after the group of audit.openapi.yaml, so it is merged into it and only adds the events commands
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "audit"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				events.GetGroup(), // audit events
+			}
+		},
+	)
+})
@@ -21,8 +21,8 @@ func newGet() core.Executor {
 			Name:    "get",
 			Summary: "Get a specific Config value that has been previously set",
 			Description: `Get a specific Config value that has been previously set. If there's an env variable
-matching the key (in uppercase and with the 'MGC_' prefix), it'll be retreived.
-Otherwise, the value will be searched for in the YAML file`,
+matching the key (in uppercase and with the 'MGC_CONFIG_' or 'MGC_' prefix), it'll be retreived.
+Otherwise, the value will be searched for in the project, workspace and system YAML files`,
 		},
 		func(ctx context.Context, parameter configGetParams, _ struct{}) (result core.Value, err error) {
 			config := mgcConfigPkg.FromContext(ctx)
@@ -14,7 +14,12 @@ var GetGroup = utils.NewLazyLoader(func() core.Grouper {
 			Description: `Configuration values are available to be set so that they persist between
 different executions of the MgcSDK. They reside in a YAML file when set.
 Config values may also be loaded via Environment Variables. Any Config available
-(see 'list') may be exported as an env variable in uppercase with the 'MGC_' prefix`,
+(see 'list') may be exported as an env variable in uppercase with the 'MGC_CONFIG_' prefix,
+such as MGC_CONFIG_CHUNK_SIZE. The older 'MGC_' prefix is still accepted.
+
+Values are looked up in this order: environment variables, the project file (.mgc.yaml in the
+current directory or any parent), the workspace file and the system file (/etc/mgc/cli.yaml or
+$MGC_SYSTEM_CONFIG_FILE). Use 'list --show-origin' to see where each value comes from`,
 		},
 		func() []core.Descriptor {
 			return []core.Descriptor{
@@ -2,19 +2,39 @@ package config
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"slices"
 
 	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcConfigPkg "github.com/MagaluCloud/magalu/mgc/core/config"
 	mgcUtilsPkg "github.com/MagaluCloud/magalu/mgc/core/utils"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/config/common"
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
+type configListParams struct {
+	ShowOrigin bool `json:"show-origin,omitempty" jsonschema_description:"List only the configs with a value, showing the value and where it comes from: the environment, project, workspace or system file"`
+}
+
 type configInfo struct {
 	Name        string `json:"name"`
 	Type        string `json:"type"`
 	Description string `json:"description"`
+	Value       any    `json:"value,omitempty"`
+	Origin      string `json:"origin,omitempty"`
+}
+
+func formatConfigValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
 }
 
 func configListFormatter(exec core.Executor, result core.Result) string {
@@ -22,18 +42,30 @@ func configListFormatter(exec core.Executor, result core.Result) string {
 	resultWithValue, _ := core.ResultAs[core.ResultWithValue](result)
 	configMap := resultWithValue.Value().(map[string]any)
 
-	writer := table.NewWriter()
-	writer.AppendHeader(table.Row{"Name", "Type", "Description"})
-
 	sortedKeys := make([]string, 0, len(configMap))
-	for k := range configMap {
+	showOrigin := false
+	for k, info := range configMap {
 		sortedKeys = append(sortedKeys, k)
+		if _, ok := info.(map[string]any)["origin"]; ok {
+			showOrigin = true
+		}
 	}
 	slices.Sort(sortedKeys)
 
+	writer := table.NewWriter()
+	if showOrigin {
+		writer.AppendHeader(table.Row{"Name", "Value", "Origin"})
+	} else {
+		writer.AppendHeader(table.Row{"Name", "Type", "Description"})
+	}
+
 	for _, k := range sortedKeys {
 		info := configMap[k].(map[string]any)
-		writer.AppendRow(table.Row{k, info["type"], info["description"]})
+		if showOrigin {
+			writer.AppendRow(table.Row{k, formatConfigValue(info["value"]), info["origin"]})
+		} else {
+			writer.AppendRow(table.Row{k, info["type"], info["description"]})
+		}
 	}
 
 	return writer.Render()
@@ -42,7 +74,7 @@ func configListFormatter(exec core.Executor, result core.Result) string {
 var getList = mgcUtilsPkg.NewLazyLoader[core.Executor](newList)
 
 func newList() core.Executor {
-	executor := core.NewStaticExecuteSimple(
+	executor := core.NewStaticExecute(
 		core.DescriptorSpec{
 			Name:        "list",
 			Description: "List all available Configs",
@@ -52,7 +84,7 @@ func newList() core.Executor {
 	return core.NewExecuteFormat(executor, configListFormatter)
 }
 
-func getAllConfigs(ctx context.Context) (map[string]configInfo, error) {
+func getAllConfigs(ctx context.Context, parameters configListParams, _ struct{}) (map[string]configInfo, error) {
 	var toHide = []string{"logging", "env", "logfilter", "serverUrl"}
 
 	configSchemas, err := common.ListAllConfigSchemas(ctx)
@@ -60,18 +92,35 @@ func getAllConfigs(ctx context.Context) (map[string]configInfo, error) {
 		return nil, err
 	}
 
+	config := mgcConfigPkg.FromContext(ctx)
+	if config == nil && parameters.ShowOrigin {
+		return nil, fmt.Errorf("unable to retrieve system configuration")
+	}
+
 	result := make(map[string]configInfo, len(configSchemas))
 	for name, schema := range configSchemas {
-		if !slices.Contains(toHide, name) {
-			if schema.Type != nil && len(schema.Type.Slice()) > 0 {
-				result[name] = configInfo{
-					Name:        name,
-					Type:        schema.Type.Slice()[0],
-					Description: schema.Description,
-				}
+		if slices.Contains(toHide, name) || schema.Type == nil || len(schema.Type.Slice()) == 0 {
+			continue
+		}
+
+		info := configInfo{
+			Name:        name,
+			Type:        schema.Type.Slice()[0],
+			Description: schema.Description,
+		}
 
+		if parameters.ShowOrigin {
+			origin, found := config.Origin(name)
+			if !found {
+				continue
 			}
+			if err := config.Get(name, &info.Value); err != nil {
+				return nil, fmt.Errorf("unable to get config %q: %w", name, err)
+			}
+			info.Origin = origin.String()
 		}
+
+		result[name] = info
 	}
 
 	return result, nil
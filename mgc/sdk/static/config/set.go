@@ -13,7 +13,7 @@ import (
 
 type configSetParams struct {
 	Key   string `json:"key" jsonschema_description:"Name of the desired config" mgc:"positional"`
-	Value any    `json:"value" jsonschema:"oneof_type=string;integer" jsonschema_description:"New flag value" mgc:"positional"`
+	Value any    `json:"value" jsonschema:"oneof_type=string;integer;object" jsonschema_description:"New flag value" mgc:"positional"`
 }
 
 var getSet = utils.NewLazyLoader[core.Executor](newSet)
@@ -22,7 +22,7 @@ func newSet() core.Executor {
 	return core.NewStaticExecute(
 		core.DescriptorSpec{
 			Name:        "set",
-			Description: "Set a specific Config value in the configuration file",
+			Description: "Set a specific Config value in the workspace configuration file",
 		},
 		func(ctx context.Context, parameter configSetParams, _ struct{}) (core.Value, error) {
 			config := mgcConfigPkg.FromContext(ctx)
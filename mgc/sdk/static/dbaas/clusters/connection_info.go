@@ -0,0 +1,45 @@
+package clusters
+
+import (
+	"context"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/dbaas/common"
+)
+
+type connectionInfoParams struct {
+	ClusterID string `json:"cluster_id" jsonschema_description:"ID of the cluster" mgc:"positional"`
+	ReadOnly  bool   `json:"read_only,omitempty" jsonschema_description:"Use the read-only endpoint of the cluster, instead of the read-write one"`
+	common.ConnectionInfoParams
+}
+
+var getConnectionInfo = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	return common.NewConnectionInfoExecutor(core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "connection-info",
+			Summary: "Show how to connect to a database cluster",
+			Description: `Print the connection URI, JDBC URL and environment variables for the engine
+of the cluster, using its read-write endpoint or, with --read-only, its read-only one.
+The password is never printed nor stored in the configuration: the environment
+variables reference --password-env, if given.
+
+With --write-credentials-file, the password is read from --password-env or prompted
+and written to ~/.pgpass (PostgreSQL) or ~/.my.cnf (MySQL).`,
+		},
+		func(ctx context.Context, params connectionInfoParams, cfg common.Config) (*common.ConnectionInfo, error) {
+			purpose := "READ_WRITE"
+			if params.ReadOnly {
+				purpose = "READONLY"
+			}
+			return common.GetConnectionInfo(
+				ctx,
+				[]string{"dbaas", "clusters", "get"},
+				core.Parameters{"cluster_id": params.ClusterID},
+				purpose,
+				params.ConnectionInfoParams,
+				cfg,
+			)
+		},
+	))
+})
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+)
+
+type Config struct {
+	Region string `json:"region,omitempty" jsonschema:"description=Region to reach the service,default=br-se1,enum=br-ne1,enum=br-se1,enum=br-mgl1"`
+	Env    string `json:"env,omitempty" jsonschema:"description=Environment to use,default=prod,enum=prod,enum=pre-prod"`
+
+	// See more about the 'squash' directive here: https://pkg.go.dev/github.com/mitchellh/mapstructure#hdr-Embedded_Structs_and_Squashing
+	config.NetworkConfig `json:",squash"` // nolint
+}
+
+// Configs to be given to the OpenAPI operations
+func (c Config) configs() core.Configs {
+	configs := core.Configs{}
+	if c.Region != "" {
+		configs["region"] = c.Region
+	}
+	if c.Env != "" {
+		configs["env"] = c.Env
+	}
+	if c.ServerUrl != "" {
+		configs["serverUrl"] = c.ServerUrl
+	}
+	return configs
+}
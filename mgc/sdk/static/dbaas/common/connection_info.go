@@ -0,0 +1,316 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"golang.org/x/term"
+)
+
+type ConnectionInfoParams struct {
+	User                 string `json:"user" jsonschema_description:"Database user, as given when the database was created"`
+	Database             string `json:"database,omitempty" jsonschema_description:"Database name. Defaults to the engine default database, if any"`
+	PasswordEnv          string `json:"password_env,omitempty" jsonschema_description:"Name of the environment variable with the password. It's referenced by the env-var form and read to write the credentials file. If not given, the password is prompted when writing the credentials file"`
+	Private              bool   `json:"private,omitempty" jsonschema_description:"Use the private address, even if there is a public one"`
+	WriteCredentialsFile bool   `json:"write_credentials_file,omitempty" jsonschema_description:"Add or replace the entry of this database in the engine credentials file: ~/.pgpass for PostgreSQL and ~/.my.cnf for MySQL"`
+	CredentialsFile      string `json:"credentials_file,omitempty" jsonschema_description:"Credentials file to write, instead of the engine default"`
+}
+
+type ConnectionInfo struct {
+	Engine          string            `json:"engine"`
+	EngineVersion   string            `json:"engine_version"`
+	Host            string            `json:"host"`
+	Port            string            `json:"port"`
+	Access          string            `json:"access"`
+	User            string            `json:"user"`
+	Database        string            `json:"database,omitempty"`
+	URI             string            `json:"uri"`
+	JDBC            string            `json:"jdbc"`
+	Env             map[string]string `json:"env"`
+	CredentialsFile string            `json:"credentials_file,omitempty"`
+	// How to use the credentials file, when it's not automatic
+	CredentialsUsage string `json:"credentials_usage,omitempty"`
+}
+
+// Common to instances, clusters and replicas
+type Address struct {
+	Access  string `json:"access"`
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	Port    string `json:"port"`
+	Purpose string `json:"purpose"`
+}
+
+type resource struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	EngineID  string    `json:"engine_id"`
+	Addresses []Address `json:"addresses"`
+}
+
+type engine struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// How to connect to each engine
+type engineKind struct {
+	name            string
+	scheme          string
+	defaultPort     string
+	defaultDatabase string
+	// env var name -> value, the password is added separately
+	env         func(info *ConnectionInfo) map[string]string
+	passwordEnv string
+	// Write the credentials to the file, returning how to use it, if not automatic
+	credentialsFile string
+	writeFile       func(file, name string, info *ConnectionInfo, password string) (usage string, err error)
+}
+
+var engineKinds = []*engineKind{
+	{
+		name:            "postgresql",
+		scheme:          "postgresql",
+		defaultPort:     "5432",
+		defaultDatabase: "postgres",
+		env: func(info *ConnectionInfo) map[string]string {
+			return map[string]string{
+				"PGHOST":     info.Host,
+				"PGPORT":     info.Port,
+				"PGUSER":     info.User,
+				"PGDATABASE": info.Database,
+			}
+		},
+		passwordEnv:     "PGPASSWORD",
+		credentialsFile: ".pgpass",
+		writeFile:       writePgpass,
+	},
+	{
+		name:        "mysql",
+		scheme:      "mysql",
+		defaultPort: "3306",
+		env: func(info *ConnectionInfo) map[string]string {
+			return map[string]string{
+				"MYSQL_HOST":     info.Host,
+				"MYSQL_TCP_PORT": info.Port,
+			}
+		},
+		passwordEnv:     "MYSQL_PWD",
+		credentialsFile: ".my.cnf",
+		writeFile:       writeMyCnf,
+	},
+}
+
+func findEngineKind(name string) (*engineKind, error) {
+	lower := strings.ToLower(name)
+	for _, kind := range engineKinds {
+		if strings.HasPrefix(lower, kind.name) || strings.HasPrefix(kind.name, lower) {
+			return kind, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported engine %q", name)
+}
+
+// Addresses with the given purpose, if they have any, are considered. Public IPv4 is preferred,
+// private addresses are used if requested or if there is no public one.
+func selectAddress(addresses []Address, purpose string, private bool) (*Address, error) {
+	var candidates []Address
+	for _, a := range addresses {
+		if a.Address == "" || (a.Purpose != "" && purpose != "" && a.Purpose != purpose) {
+			continue
+		}
+		candidates = append(candidates, a)
+	}
+
+	rank := func(a Address) int {
+		r := 0
+		if (a.Access == "PUBLIC") == private {
+			r += 2
+		}
+		if a.Type == "IPv6" {
+			r += 1
+		}
+		return r
+	}
+
+	var best *Address
+	for i := range candidates {
+		if best == nil || rank(candidates[i]) < rank(*best) {
+			best = &candidates[i]
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no address available, the database may still be creating")
+	}
+	return best, nil
+}
+
+func buildConnectionInfo(kind *engineKind, version string, address *Address, p ConnectionInfoParams) *ConnectionInfo {
+	info := &ConnectionInfo{
+		Engine:        kind.name,
+		EngineVersion: version,
+		Host:          address.Address,
+		Port:          address.Port,
+		Access:        address.Access,
+		User:          p.User,
+		Database:      p.Database,
+	}
+	if info.Port == "" {
+		info.Port = kind.defaultPort
+	}
+	if info.Database == "" {
+		info.Database = kind.defaultDatabase
+	}
+
+	hostPort := net.JoinHostPort(info.Host, info.Port)
+	dbPath := ""
+	if info.Database != "" {
+		dbPath = "/" + url.PathEscape(info.Database)
+	}
+
+	uri := url.URL{Scheme: kind.scheme, User: url.User(info.User), Host: hostPort}
+	info.URI = uri.String() + dbPath
+	info.JDBC = fmt.Sprintf("jdbc:%s://%s%s?%s", kind.scheme, hostPort, dbPath, url.Values{"user": {info.User}}.Encode())
+
+	info.Env = map[string]string{"DATABASE_URL": info.URI}
+	for k, v := range kind.env(info) {
+		if v != "" {
+			info.Env[k] = v
+		}
+	}
+	// Only references, the password itself is never given
+	if p.PasswordEnv != "" {
+		info.Env[kind.passwordEnv] = "${" + p.PasswordEnv + "}"
+	}
+
+	return info
+}
+
+func readPassword(p ConnectionInfoParams, info *ConnectionInfo) (string, error) {
+	if p.PasswordEnv != "" {
+		password := os.Getenv(p.PasswordEnv)
+		if password == "" {
+			return "", fmt.Errorf("environment variable %q is empty", p.PasswordEnv)
+		}
+		return password, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", core.UsageError{Err: fmt.Errorf("unable to prompt the password, use --password-env")}
+	}
+
+	fmt.Fprintf(os.Stderr, "Password for %s@%s: ", info.User, info.Host)
+	data, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func defaultCredentialsFile(kind *engineKind) (string, error) {
+	if kind.name == "postgresql" {
+		if file := os.Getenv("PGPASSFILE"); file != "" {
+			return file, nil
+		}
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the home directory: %w", err)
+	}
+	return homeDir + string(os.PathSeparator) + kind.credentialsFile, nil
+}
+
+func executeValue[T any](ctx context.Context, path []string, parameters core.Parameters, cfg Config) (*T, error) {
+	result, err := core.ExecuteByPath(ctx, path, parameters, cfg.configs())
+	if err != nil {
+		return nil, err
+	}
+
+	resultWithValue, ok := core.ResultAs[core.ResultWithValue](result)
+	if !ok {
+		return nil, fmt.Errorf("%s returned no value", strings.Join(path, " "))
+	}
+	return utils.DecodeNewValue[T](resultWithValue.Value())
+}
+
+// Get the resource (instance, cluster or replica) with the given "get" executor and return how to connect to it.
+// If purpose is given, only addresses with such purpose are considered, such as READ_WRITE.
+func GetConnectionInfo(
+	ctx context.Context,
+	getPath []string,
+	getParameters core.Parameters,
+	purpose string,
+	p ConnectionInfoParams,
+	cfg Config,
+) (*ConnectionInfo, error) {
+	res, err := executeValue[resource](ctx, getPath, getParameters, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	eng, err := executeValue[engine](ctx, []string{"dbaas", "engines", "get"}, core.Parameters{"engine_id": res.EngineID}, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get engine %q: %w", res.EngineID, err)
+	}
+
+	kind, err := findEngineKind(eng.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := selectAddress(res.Addresses, purpose, p.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	info := buildConnectionInfo(kind, eng.Version, address, p)
+	logger().Debugw("connection info", "resource", res.ID, "engine", eng, "address", address)
+
+	if !p.WriteCredentialsFile {
+		return info, nil
+	}
+
+	file := p.CredentialsFile
+	if file == "" {
+		if file, err = defaultCredentialsFile(kind); err != nil {
+			return nil, err
+		}
+	}
+
+	password, err := readPassword(p, info)
+	if err != nil {
+		return nil, err
+	}
+
+	info.CredentialsUsage, err = kind.writeFile(file, res.Name, info, password)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write %q: %w", file, err)
+	}
+	info.CredentialsFile = file
+
+	return info, nil
+}
+
+// Output shared by the connection-info executors
+func NewConnectionInfoExecutor(exec core.Executor) core.Executor {
+	return core.NewExecuteResultOutputOptions(exec, func(exec core.Executor, result core.Result) string {
+		return `template=URI:  {{.uri}}
+JDBC: {{.jdbc}}
+
+{{range $k, $v := .env}}export {{$k}}={{$v}}
+{{end}}{{if .credentials_file}}
+Credentials written to {{.credentials_file}}
+{{if .credentials_usage}}Use with: {{.credentials_usage}}
+{{end}}{{end}}`
+	})
+}
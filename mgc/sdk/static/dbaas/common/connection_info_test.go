@@ -0,0 +1,119 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectAddress(t *testing.T) {
+	addresses := []Address{
+		{Access: "PRIVATE", Type: "IPv4", Address: "10.0.0.1"},
+		{Access: "PUBLIC", Type: "IPv6", Address: "2001:db8::1"},
+		{Access: "PUBLIC", Type: "IPv4", Address: "203.0.113.1"},
+	}
+
+	tests := []struct {
+		name      string
+		addresses []Address
+		purpose   string
+		private   bool
+		expected  string
+	}{
+		{"public IPv4 preferred", addresses, "", false, "203.0.113.1"},
+		{"private requested", addresses, "", true, "10.0.0.1"},
+		{"public IPv6 fallback", addresses[:2], "", false, "2001:db8::1"},
+		{"private fallback", addresses[:1], "", false, "10.0.0.1"},
+		{
+			"purpose",
+			[]Address{
+				{Access: "PUBLIC", Type: "IPv4", Address: "203.0.113.1", Port: "5432", Purpose: "READ_WRITE"},
+				{Access: "PUBLIC", Type: "IPv4", Address: "203.0.113.2", Port: "5433", Purpose: "READONLY"},
+			},
+			"READONLY",
+			false,
+			"203.0.113.2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			address, err := selectAddress(tc.addresses, tc.purpose, tc.private)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if address.Address != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, address.Address)
+			}
+		})
+	}
+
+	if _, err := selectAddress(nil, "", false); err == nil {
+		t.Errorf("expected error without addresses")
+	}
+}
+
+func TestBuildConnectionInfo(t *testing.T) {
+	postgres, _ := findEngineKind("postgresql")
+	info := buildConnectionInfo(postgres, "16", &Address{Address: "2001:db8::1"}, ConnectionInfoParams{User: "admin", PasswordEnv: "DB_PASS"})
+
+	if info.URI != "postgresql://admin@[2001:db8::1]:5432/postgres" {
+		t.Errorf("unexpected URI %q", info.URI)
+	}
+	if info.JDBC != "jdbc:postgresql://[2001:db8::1]:5432/postgres?user=admin" {
+		t.Errorf("unexpected JDBC %q", info.JDBC)
+	}
+	if info.Env["PGPASSWORD"] != "${DB_PASS}" || info.Env["PGDATABASE"] != "postgres" {
+		t.Errorf("unexpected env %v", info.Env)
+	}
+
+	mysql, err := findEngineKind("MySQL")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	info = buildConnectionInfo(mysql, "8.0", &Address{Address: "203.0.113.1", Port: "6446"}, ConnectionInfoParams{User: "admin"})
+	if info.URI != "mysql://admin@203.0.113.1:6446" {
+		t.Errorf("unexpected URI %q", info.URI)
+	}
+	if _, ok := info.Env["MYSQL_PWD"]; ok {
+		t.Errorf("password must not be referenced without password_env: %v", info.Env)
+	}
+}
+
+func TestUpsertPgpass(t *testing.T) {
+	info := &ConnectionInfo{Host: "db.example.com", Port: "5432", User: "admin", Database: "postgres"}
+
+	lines := upsertPgpass([]string{"other:5432:*:user:secret"}, info, "old")
+	lines = upsertPgpass(lines, info, `new:pass\`)
+
+	expected := []string{"other:5432:*:user:secret", `db.example.com:5432:postgres:admin:new\:pass\\`}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %q, got %q", expected, lines)
+	}
+}
+
+func TestUpsertMyCnf(t *testing.T) {
+	info := &ConnectionInfo{Host: "db.example.com", Port: "3306", User: "admin"}
+
+	lines := []string{"[client]", "user=me", "", "[client-mydb]", "password=old", "", "[mysqld]", "port=3306"}
+	lines = upsertMyCnf(lines, "mydb", info, `p"w`)
+
+	expected := `[client]
+user=me
+
+[client-mydb]
+host=db.example.com
+port=3306
+user=admin
+password="p\"w"
+
+[mysqld]
+port=3306`
+	if got := strings.Join(lines, "\n"); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	lines = upsertMyCnf(nil, "other", info, "pw")
+	if lines[0] != "[client-other]" {
+		t.Errorf("expected new group, got %q", lines)
+	}
+}
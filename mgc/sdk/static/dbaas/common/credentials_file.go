@@ -0,0 +1,133 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func readLines(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	return strings.Split(text, "\n"), nil
+}
+
+// Password files must not be readable by others, libpq ignores .pgpass otherwise
+func writeLines(file string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return err
+	}
+	return os.Chmod(file, 0600)
+}
+
+// .pgpass fields are separated by ':', which must be escaped with '\' as well as '\' itself
+func escapePgpass(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `:`, `\:`).Replace(s)
+}
+
+func pgpassKey(info *ConnectionInfo) string {
+	database := "*"
+	if info.Database != "" {
+		database = escapePgpass(info.Database)
+	}
+	return strings.Join([]string{escapePgpass(info.Host), info.Port, database, escapePgpass(info.User)}, ":") + ":"
+}
+
+// Replace the line of the same host, port, database and user, or append a new one
+func upsertPgpass(lines []string, info *ConnectionInfo, password string) []string {
+	key := pgpassKey(info)
+	entry := key + escapePgpass(password)
+	for i, line := range lines {
+		if strings.HasPrefix(line, key) {
+			lines[i] = entry
+			return lines
+		}
+	}
+	return append(lines, entry)
+}
+
+func writePgpass(file, name string, info *ConnectionInfo, password string) (string, error) {
+	lines, err := readLines(file)
+	if err != nil {
+		return "", err
+	}
+	return "", writeLines(file, upsertPgpass(lines, info, password))
+}
+
+func myCnfGroup(name string) string {
+	return "client-" + name
+}
+
+// Replace the [client-<name>] group, or append a new one. Other groups are kept as is
+func upsertMyCnf(lines []string, name string, info *ConnectionInfo, password string) []string {
+	header := "[" + myCnfGroup(name) + "]"
+	group := []string{
+		header,
+		"host=" + info.Host,
+		"port=" + info.Port,
+		"user=" + info.User,
+		// Quoted so special characters are taken literally
+		"password=\"" + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(password) + "\"",
+	}
+	if info.Database != "" {
+		group = append(group, "database="+info.Database)
+	}
+
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start < 0 {
+			if trimmed == header {
+				start = i
+			}
+		} else if strings.HasPrefix(trimmed, "[") {
+			end = i
+			break
+		}
+	}
+
+	if start < 0 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return append(lines, group...)
+	}
+
+	// keep the blank line separating the next group
+	if end < len(lines) {
+		group = append(group, "")
+	}
+	result := append([]string{}, lines[:start]...)
+	result = append(result, group...)
+	return append(result, lines[end:]...)
+}
+
+func writeMyCnf(file, name string, info *ConnectionInfo, password string) (string, error) {
+	lines, err := readLines(file)
+	if err != nil {
+		return "", err
+	}
+	if err = writeLines(file, upsertMyCnf(lines, name, info, password)); err != nil {
+		return "", err
+	}
+
+	usage := fmt.Sprintf("mysql --defaults-group-suffix=-%s", name)
+	if home, err := os.UserHomeDir(); err != nil || filepath.Clean(file) != filepath.Join(home, ".my.cnf") {
+		usage = fmt.Sprintf("mysql --defaults-extra-file=%s --defaults-group-suffix=-%s", file, name)
+	}
+	return usage, nil
+}
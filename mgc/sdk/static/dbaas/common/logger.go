@@ -0,0 +1,5 @@
+package common
+
+import mgcLoggerPkg "github.com/MagaluCloud/magalu/mgc/core/logger"
+
+var logger = mgcLoggerPkg.NewLazy[ConnectionInfo]()
@@ -0,0 +1,23 @@
+package dbaas
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/dbaas/clusters"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/dbaas/instances"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/dbaas/replicas"
+)
+
+// Each subgroup adds connection-info to the dbaas OpenAPI group of the same name
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "dbaas"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				instances.GetGroup(), // dbaas instances
+				clusters.GetGroup(),  // dbaas clusters
+				replicas.GetGroup(),  // dbaas replicas
+			}
+		},
+	)
+})
@@ -0,0 +1,39 @@
+package instances
+
+import (
+	"context"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/dbaas/common"
+)
+
+type connectionInfoParams struct {
+	InstanceID string `json:"instance_id" jsonschema_description:"ID of the instance" mgc:"positional"`
+	common.ConnectionInfoParams
+}
+
+var getConnectionInfo = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	return common.NewConnectionInfoExecutor(core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "connection-info",
+			Summary: "Show how to connect to a database instance",
+			Description: `Print the connection URI, JDBC URL and environment variables for the engine
+of the instance. The password is never printed nor stored in the configuration:
+the environment variables reference --password-env, if given.
+
+With --write-credentials-file, the password is read from --password-env or prompted
+and written to ~/.pgpass (PostgreSQL) or ~/.my.cnf (MySQL).`,
+		},
+		func(ctx context.Context, params connectionInfoParams, cfg common.Config) (*common.ConnectionInfo, error) {
+			return common.GetConnectionInfo(
+				ctx,
+				[]string{"dbaas", "instances", "get"},
+				core.Parameters{"instance_id": params.InstanceID},
+				"",
+				params.ConnectionInfoParams,
+				cfg,
+			)
+		},
+	))
+})
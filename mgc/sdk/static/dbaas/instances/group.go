@@ -0,0 +1,17 @@
+package instances
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "instances"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				getConnectionInfo(), // dbaas instances connection-info
+			}
+		},
+	)
+})
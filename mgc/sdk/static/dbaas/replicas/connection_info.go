@@ -0,0 +1,39 @@
+package replicas
+
+import (
+	"context"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/dbaas/common"
+)
+
+type connectionInfoParams struct {
+	ReplicaID string `json:"replica_id" jsonschema_description:"ID of the replica" mgc:"positional"`
+	common.ConnectionInfoParams
+}
+
+var getConnectionInfo = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	return common.NewConnectionInfoExecutor(core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "connection-info",
+			Summary: "Show how to connect to a read replica",
+			Description: `Print the connection URI, JDBC URL and environment variables for the engine
+of the replica. The password is never printed nor stored in the configuration:
+the environment variables reference --password-env, if given.
+
+With --write-credentials-file, the password is read from --password-env or prompted
+and written to ~/.pgpass (PostgreSQL) or ~/.my.cnf (MySQL).`,
+		},
+		func(ctx context.Context, params connectionInfoParams, cfg common.Config) (*common.ConnectionInfo, error) {
+			return common.GetConnectionInfo(
+				ctx,
+				[]string{"dbaas", "replicas", "get"},
+				core.Parameters{"replica_id": params.ReplicaID},
+				"",
+				params.ConnectionInfoParams,
+				cfg,
+			)
+		},
+	))
+})
@@ -0,0 +1,17 @@
+package replicas
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "replicas"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				getConnectionInfo(), // dbaas replicas connection-info
+			}
+		},
+	)
+})
@@ -3,10 +3,14 @@ package static
 import (
 	"github.com/MagaluCloud/magalu/mgc/core"
 	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/audit"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/auth"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/config"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/dbaas"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/kubernetes"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/profile"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/virtual_machine"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/workspace"
 )
 
@@ -17,9 +21,13 @@ var GetGroup = utils.NewLazyLoader(func() core.Grouper {
 			return []core.Descriptor{
 				auth.GetGroup(),
 				config.GetGroup(),
+				kubernetes.GetGroup(),
 				object_storage.GetGroup(),
 				workspace.GetGroup(),
 				profile.GetGroup(),
+				virtual_machine.GetGroup(),
+				dbaas.GetGroup(),
+				audit.GetGroup(),
 			}
 		},
 	)
@@ -0,0 +1,18 @@
+package cluster
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "cluster"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				core.NewExecutorOverlay("kubeconfig", newKubeconfigExecutor), // kubernetes cluster kubeconfig
+				getKubeconfigRemove(), // kubernetes cluster kubeconfig-remove
+			}
+		},
+	)
+})
@@ -0,0 +1,160 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type kubeconfigMergeParams struct {
+	ClusterID         string `json:"cluster_id"`
+	Merge             bool   `json:"merge,omitempty" jsonschema_description:"Merge the cluster credentials into the user's kubeconfig instead of printing them. Use 'kubeconfig-remove' to undo, such as after the cluster is deleted"`
+	KubeconfigFile    string `json:"kubeconfig_file,omitempty" jsonschema_description:"Kubeconfig file to merge into. Defaults to the first file in $KUBECONFIG or ~/.kube/config"`
+	ContextName       string `json:"context_name,omitempty" jsonschema_description:"Name of the merged cluster, context and user. Defaults to the name used in a previous merge or 'mgc-' followed by the cluster name"`
+	SetCurrentContext bool   `json:"set_current_context,omitempty" jsonschema_description:"Make the merged context the current one. It's always done if there is no current context"`
+}
+
+type kubeconfigMergeResult struct {
+	File           string `json:"file"`
+	Backup         string `json:"backup,omitempty"`
+	Context        string `json:"context"`
+	CurrentContext string `json:"current_context"`
+}
+
+// Parameters added on top of the OpenAPI operation
+var mergeOnlyParams = []string{"merge", "kubeconfig_file", "context_name", "set_current_context"}
+
+var getMergeParamsSchema = utils.NewLazyLoaderWithError(mgcSchemaPkg.SchemaFromType[kubeconfigMergeParams])
+var getMergeResultSchema = utils.NewLazyLoaderWithError(mgcSchemaPkg.SchemaFromType[kubeconfigMergeResult])
+
+type kubeconfigExecutor struct {
+	core.Executor
+	parametersSchema *core.Schema
+}
+
+func newKubeconfigExecutor(base core.Executor) (core.Executor, error) {
+	mergeSchema, err := getMergeParamsSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	baseSchema := base.ParametersSchema()
+	if _, ok := baseSchema.Properties["cluster_id"]; !ok {
+		return nil, fmt.Errorf("kubeconfig operation is missing the 'cluster_id' parameter")
+	}
+
+	schema := *baseSchema
+	schema.Properties = maps.Clone(baseSchema.Properties)
+	for _, name := range mergeOnlyParams {
+		schema.Properties[name] = mergeSchema.Properties[name]
+	}
+
+	return &kubeconfigExecutor{base, &schema}, nil
+}
+
+func (e *kubeconfigExecutor) ParametersSchema() *core.Schema {
+	return e.parametersSchema
+}
+
+func (e *kubeconfigExecutor) Execute(ctx context.Context, parameters core.Parameters, configs core.Configs) (core.Result, error) {
+	p, err := utils.DecodeNewValue[kubeconfigMergeParams](parameters)
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	baseParameters := maps.Clone(parameters)
+	for _, name := range mergeOnlyParams {
+		delete(baseParameters, name)
+	}
+
+	result, err := e.Executor.Execute(ctx, baseParameters, configs)
+	if err != nil || !p.Merge {
+		return core.ExecutorWrapResult(e, result, err)
+	}
+
+	data, err := readKubeconfigResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := mergeKubeconfig(data, *p)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := getMergeResultSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	source := core.ResultSource{Executor: e, Context: ctx, Parameters: parameters, Configs: configs}
+	return core.NewResultWithDefaultOutputOptions(
+		core.NewSimpleResult(source, schema, value),
+		"template=Merged cluster {{.context}} into {{.file}}\n",
+	), nil
+}
+
+func (e *kubeconfigExecutor) Unwrap() core.Executor {
+	return e.Executor
+}
+
+func readKubeconfigResult(result core.Result) ([]byte, error) {
+	if r, ok := core.ResultAs[core.ResultWithReader](result); ok {
+		reader := r.Reader()
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+		return io.ReadAll(reader)
+	}
+	if r, ok := core.ResultAs[core.ResultWithValue](result); ok {
+		if s, ok := r.Value().(string); ok {
+			return []byte(s), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected kubeconfig result %T", result)
+}
+
+func mergeKubeconfig(data []byte, p kubeconfigMergeParams) (result *kubeconfigMergeResult, err error) {
+	src, err := parseKubeconfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster kubeconfig: %w", err)
+	}
+
+	files, err := resolveKubeconfigFiles(p.KubeconfigFile)
+	if err != nil {
+		return
+	}
+	file := files[0]
+
+	doc, err := loadKubeconfig(file)
+	if err != nil {
+		return
+	}
+
+	contextName, err := doc.merge(src, p.ClusterID, p.ContextName, p.SetCurrentContext)
+	if err != nil {
+		return
+	}
+
+	backup, err := saveKubeconfig(file, doc)
+	if err != nil {
+		return
+	}
+	logger().Debugw("merged cluster kubeconfig", "file", file, "backup", backup, "context", contextName)
+
+	currentContext, _ := doc["current-context"].(string)
+	return &kubeconfigMergeResult{
+		File:           file,
+		Backup:         backup,
+		Context:        contextName,
+		CurrentContext: currentContext,
+	}, nil
+}
+
+var _ core.Executor = (*kubeconfigExecutor)(nil)
+var _ core.ExecutorWrapper = (*kubeconfigExecutor)(nil)
@@ -0,0 +1,350 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/invopop/yaml"
+)
+
+const (
+	kubeconfigEnvVar = "KUBECONFIG"
+	// Added to the cluster, context and user entries so they can be found later, regardless of their names
+	kubeconfigExtensionName = "mgc.magalu.cloud/cluster"
+	kubeconfigNamePrefix    = "mgc-"
+	kubeconfigBackupSuffix  = ".bak"
+)
+
+// Kinds of named entries in a kubeconfig file: list key and the entry's value key
+var kubeconfigEntryKinds = []struct{ listKey, valueKey string }{
+	{"clusters", "cluster"},
+	{"contexts", "context"},
+	{"users", "user"},
+}
+
+// Kubeconfig is kept as a generic document, so unknown keys are preserved when saving
+type kubeconfig map[string]any
+
+// The kubeconfig files to be used, in order, following kubectl's behavior: $KUBECONFIG
+// may contain a list of files, otherwise ~/.kube/config is used.
+func defaultKubeconfigFiles() ([]string, error) {
+	if env := os.Getenv(kubeconfigEnvVar); env != "" {
+		var files []string
+		for _, f := range filepath.SplitList(env) {
+			if f != "" {
+				files = append(files, f)
+			}
+		}
+		if len(files) > 0 {
+			return files, nil
+		}
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the home directory: %w", err)
+	}
+	return []string{filepath.Join(homeDir, ".kube", "config")}, nil
+}
+
+func resolveKubeconfigFiles(file string) ([]string, error) {
+	if file != "" {
+		return []string{file}, nil
+	}
+	return defaultKubeconfigFiles()
+}
+
+func parseKubeconfig(data []byte) (kubeconfig, error) {
+	doc := kubeconfig{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = kubeconfig{}
+	}
+	return doc, nil
+}
+
+// Missing files are returned as empty documents
+func loadKubeconfig(file string) (kubeconfig, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return newKubeconfig(), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseKubeconfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig %q: %w", file, err)
+	}
+	return doc, nil
+}
+
+func newKubeconfig() kubeconfig {
+	return kubeconfig{
+		"apiVersion":      "v1",
+		"kind":            "Config",
+		"preferences":     map[string]any{},
+		"clusters":        []any{},
+		"contexts":        []any{},
+		"users":           []any{},
+		"current-context": "",
+	}
+}
+
+// Save the document, the previous file contents (if any) are copied to file + ".bak".
+// The backup file name is returned, if any.
+func saveKubeconfig(file string, doc kubeconfig) (backup string, err error) {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return
+	}
+
+	if previous, readErr := os.ReadFile(file); readErr == nil {
+		backup = file + kubeconfigBackupSuffix
+		if err = os.WriteFile(backup, previous, 0600); err != nil {
+			err = fmt.Errorf("unable to backup %q: %w", file, err)
+			return
+		}
+	} else if !errors.Is(readErr, os.ErrNotExist) {
+		err = readErr
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return
+	}
+
+	err = os.WriteFile(file, data, 0600)
+	return
+}
+
+func (k kubeconfig) entries(listKey string) []any {
+	list, _ := k[listKey].([]any)
+	return list
+}
+
+func entryName(entry any) string {
+	m, _ := entry.(map[string]any)
+	name, _ := m["name"].(string)
+	return name
+}
+
+func entryValue(entry any, valueKey string) map[string]any {
+	m, _ := entry.(map[string]any)
+	value, _ := m[valueKey].(map[string]any)
+	return value
+}
+
+func entryClusterID(entry any, valueKey string) string {
+	value := entryValue(entry, valueKey)
+	extensions, _ := value["extensions"].([]any)
+	for _, ext := range extensions {
+		m, _ := ext.(map[string]any)
+		if m["name"] != kubeconfigExtensionName {
+			continue
+		}
+		data, _ := m["extension"].(map[string]any)
+		id, _ := data["cluster_id"].(string)
+		return id
+	}
+	return ""
+}
+
+// The name is considered free if no entry uses it or if it's used by the same cluster
+func (k kubeconfig) isNameAvailable(name, clusterID string) bool {
+	for _, kind := range kubeconfigEntryKinds {
+		for _, entry := range k.entries(kind.listKey) {
+			if entryName(entry) == name && entryClusterID(entry, kind.valueKey) != clusterID {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (k kubeconfig) availableName(base, clusterID string) string {
+	name := base
+	for i := 2; !k.isNameAvailable(name, clusterID); i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	return name
+}
+
+// Name previously used by the cluster, if it was merged before
+func (k kubeconfig) clusterContextName(clusterID string) string {
+	for _, entry := range k.entries("contexts") {
+		if entryClusterID(entry, "context") == clusterID {
+			return entryName(entry)
+		}
+	}
+	return ""
+}
+
+type kubeconfigRemoved struct {
+	Clusters []string `json:"clusters"`
+	Contexts []string `json:"contexts"`
+	Users    []string `json:"users"`
+}
+
+func (r kubeconfigRemoved) isEmpty() bool {
+	return len(r.Clusters) == 0 && len(r.Contexts) == 0 && len(r.Users) == 0
+}
+
+// Names of the cluster and user entries used by the contexts of the given cluster. These are
+// removed with the contexts, even if the extension was dropped from them, such as when they
+// were rewritten by 'kubectl config set-cluster' or 'set-credentials'
+func (k kubeconfig) referencedNames(clusterID string) map[string][]string {
+	referenced := map[string][]string{}
+	var kept []map[string]any
+	for _, entry := range k.entries("contexts") {
+		if entryClusterID(entry, "context") == clusterID {
+			context := entryValue(entry, "context")
+			for _, key := range []string{"cluster", "user"} {
+				if name, _ := context[key].(string); name != "" {
+					referenced[key] = append(referenced[key], name)
+				}
+			}
+		} else {
+			kept = append(kept, entryValue(entry, "context"))
+		}
+	}
+
+	// entries still used by other contexts are kept
+	for _, context := range kept {
+		for _, key := range []string{"cluster", "user"} {
+			name, _ := context[key].(string)
+			referenced[key] = slices.DeleteFunc(referenced[key], func(n string) bool { return n == name })
+		}
+	}
+	return referenced
+}
+
+// Remove all the entries added by merge() for the given cluster. If the current-context
+// was removed, it's reset.
+func (k kubeconfig) remove(clusterID string) (removed kubeconfigRemoved) {
+	referenced := k.referencedNames(clusterID)
+	for _, kind := range kubeconfigEntryKinds {
+		var kept []any
+		var removedNames []string
+		for _, entry := range k.entries(kind.listKey) {
+			if entryClusterID(entry, kind.valueKey) == clusterID || slices.Contains(referenced[kind.valueKey], entryName(entry)) {
+				removedNames = append(removedNames, entryName(entry))
+			} else {
+				kept = append(kept, entry)
+			}
+		}
+		if len(removedNames) == 0 {
+			continue
+		}
+		if kept == nil {
+			kept = []any{}
+		}
+		k[kind.listKey] = kept
+
+		switch kind.listKey {
+		case "clusters":
+			removed.Clusters = removedNames
+		case "contexts":
+			removed.Contexts = removedNames
+		case "users":
+			removed.Users = removedNames
+		}
+	}
+
+	if current, _ := k["current-context"].(string); slices.Contains(removed.Contexts, current) {
+		k["current-context"] = ""
+	}
+
+	return
+}
+
+func singleEntryValue(src kubeconfig, kind struct{ listKey, valueKey string }) (map[string]any, error) {
+	entries := src.entries(kind.listKey)
+	if len(entries) != 1 {
+		return nil, fmt.Errorf("expected a single entry in the cluster kubeconfig %q, got %d", kind.listKey, len(entries))
+	}
+	value := entryValue(entries[0], kind.valueKey)
+	if value == nil {
+		return nil, fmt.Errorf("invalid cluster kubeconfig %q entry", kind.listKey)
+	}
+	return value, nil
+}
+
+func withClusterExtension(value map[string]any, clusterID string) map[string]any {
+	result := make(map[string]any, len(value)+1)
+	for k, v := range value {
+		result[k] = v
+	}
+
+	var extensions []any
+	existing, _ := value["extensions"].([]any)
+	for _, ext := range existing {
+		if m, _ := ext.(map[string]any); m["name"] != kubeconfigExtensionName {
+			extensions = append(extensions, ext)
+		}
+	}
+	result["extensions"] = append(extensions, map[string]any{
+		"name":      kubeconfigExtensionName,
+		"extension": map[string]any{"cluster_id": clusterID},
+	})
+	return result
+}
+
+// Merge the single cluster, context and user of src into k, renamed to avoid collisions.
+//
+// If name is empty, the name used by a previous merge of the same cluster is reused,
+// otherwise "mgc-" followed by the source cluster name is used.
+// Entries of previous merges of the same cluster are replaced.
+func (k kubeconfig) merge(src kubeconfig, clusterID, name string, setCurrent bool) (contextName string, err error) {
+	cluster, err := singleEntryValue(src, kubeconfigEntryKinds[0])
+	if err != nil {
+		return
+	}
+	context, err := singleEntryValue(src, kubeconfigEntryKinds[1])
+	if err != nil {
+		return
+	}
+	user, err := singleEntryValue(src, kubeconfigEntryKinds[2])
+	if err != nil {
+		return
+	}
+
+	if name == "" {
+		name = k.clusterContextName(clusterID)
+	}
+	if name == "" {
+		name = kubeconfigNamePrefix + entryName(src.entries("clusters")[0])
+	}
+
+	_ = k.remove(clusterID)
+	contextName = k.availableName(name, clusterID)
+
+	context = withClusterExtension(context, clusterID)
+	context["cluster"] = contextName
+	context["user"] = contextName
+
+	values := []map[string]any{withClusterExtension(cluster, clusterID), context, withClusterExtension(user, clusterID)}
+	for i, kind := range kubeconfigEntryKinds {
+		k[kind.listKey] = append(k.entries(kind.listKey), map[string]any{
+			"name":        contextName,
+			kind.valueKey: values[i],
+		})
+	}
+
+	if current, _ := k["current-context"].(string); setCurrent || current == "" {
+		k["current-context"] = contextName
+	}
+	if _, ok := k["apiVersion"]; !ok {
+		k["apiVersion"] = "v1"
+	}
+	if _, ok := k["kind"]; !ok {
+		k["kind"] = "Config"
+	}
+
+	return
+}
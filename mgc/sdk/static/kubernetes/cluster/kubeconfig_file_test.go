@@ -0,0 +1,205 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const clusterKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: my-cluster
+  cluster:
+    server: https://my-cluster.example.com
+contexts:
+- name: my-cluster-admin@my-cluster
+  context:
+    cluster: my-cluster
+    user: my-cluster-admin
+current-context: my-cluster-admin@my-cluster
+users:
+- name: my-cluster-admin
+  user:
+    token: secret
+`
+
+const userKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: mgc-my-cluster
+  cluster:
+    server: https://other.example.com
+contexts:
+- name: mgc-my-cluster
+  context:
+    cluster: mgc-my-cluster
+    user: other
+current-context: mgc-my-cluster
+users:
+- name: other
+  user:
+    token: other-secret
+`
+
+func entryNames(doc kubeconfig, listKey string) (names []string) {
+	for _, entry := range doc.entries(listKey) {
+		names = append(names, entryName(entry))
+	}
+	return
+}
+
+func mustParseKubeconfig(t *testing.T, data string) kubeconfig {
+	doc, err := parseKubeconfig([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return doc
+}
+
+func TestKubeconfigMerge(t *testing.T) {
+	src := mustParseKubeconfig(t, clusterKubeconfig)
+	doc := mustParseKubeconfig(t, userKubeconfig)
+
+	name, err := doc.merge(src, "cluster-id", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if name != "mgc-my-cluster-2" {
+		t.Errorf("expected name to avoid collision, got %q", name)
+	}
+	if doc["current-context"] != "mgc-my-cluster" {
+		t.Errorf("current-context should not change, got %q", doc["current-context"])
+	}
+
+	context := entryValue(doc.entries("contexts")[1], "context")
+	if context["cluster"] != name || context["user"] != name {
+		t.Errorf("context should point to the renamed entries, got %v", context)
+	}
+
+	// merging again replaces the previous entries and keeps the name
+	name2, err := doc.merge(src, "cluster-id", "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if name2 != name {
+		t.Errorf("expected name %q to be reused, got %q", name, name2)
+	}
+	for _, kind := range kubeconfigEntryKinds {
+		if n := len(doc.entries(kind.listKey)); n != 2 {
+			t.Errorf("%s: expected 2 entries, got %v", kind.listKey, entryNames(doc, kind.listKey))
+		}
+	}
+	if doc["current-context"] != name {
+		t.Errorf("expected current-context %q, got %q", name, doc["current-context"])
+	}
+
+	removed := doc.remove("cluster-id")
+	if len(removed.Clusters) != 1 || len(removed.Contexts) != 1 || len(removed.Users) != 1 {
+		t.Errorf("expected one entry of each kind to be removed, got %+v", removed)
+	}
+	if doc["current-context"] != "" {
+		t.Errorf("expected current-context to be reset, got %q", doc["current-context"])
+	}
+	for _, kind := range kubeconfigEntryKinds {
+		if n := len(doc.entries(kind.listKey)); n != 1 {
+			t.Errorf("%s: expected foreign entry to be kept, got %v", kind.listKey, entryNames(doc, kind.listKey))
+		}
+	}
+}
+
+func TestKubeconfigMergeInvalidSource(t *testing.T) {
+	src := mustParseKubeconfig(t, "apiVersion: v1\nclusters: []\n")
+	_, err := newKubeconfig().merge(src, "cluster-id", "", false)
+	if err == nil {
+		t.Errorf("expected error for kubeconfig without clusters")
+	}
+}
+
+func TestKubeconfigSave(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "kube", "config")
+
+	doc := newKubeconfig()
+	_, err := doc.merge(mustParseKubeconfig(t, clusterKubeconfig), "cluster-id", "custom", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	backup, err := saveKubeconfig(file, doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if backup != "" {
+		t.Errorf("expected no backup for new file, got %q", backup)
+	}
+
+	backup, err = saveKubeconfig(file, doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := os.Stat(backup); err != nil {
+		t.Errorf("expected backup file: %s", err)
+	}
+
+	loaded, err := loadKubeconfig(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if loaded.clusterContextName("cluster-id") != "custom" {
+		t.Errorf("expected merged context to be loaded, got %v", entryNames(loaded, "contexts"))
+	}
+	if loaded["current-context"] != "custom" {
+		t.Errorf("expected current-context to be set when empty, got %q", loaded["current-context"])
+	}
+}
+
+func TestKubeconfigRemoveMerged(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(file, []byte(userKubeconfig), 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	merged, err := mergeKubeconfig([]byte(clusterKubeconfig), kubeconfigMergeParams{ClusterID: "cluster-id", Merge: true, KubeconfigFile: file})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// tools rewriting the user entry may drop its extensions, it's still found by the context
+	doc, err := loadKubeconfig(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, entry := range doc.entries("users") {
+		if entryName(entry) == merged.Context {
+			delete(entryValue(entry, "user"), "extensions")
+		}
+	}
+	if _, err := saveKubeconfig(file, doc); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := removeKubeconfig(context.Background(), kubeconfigRemoveParams{ClusterID: "cluster-id", KubeconfigFile: file}, struct{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("expected the file to be changed, got %+v", result)
+	}
+	removed := result.Files[0].kubeconfigRemoved
+	for listKey, names := range map[string][]string{"clusters": removed.Clusters, "contexts": removed.Contexts, "users": removed.Users} {
+		if len(names) != 1 || names[0] != merged.Context {
+			t.Errorf("%s: expected %q to be removed, got %v", listKey, merged.Context, names)
+		}
+	}
+
+	loaded, err := loadKubeconfig(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, kind := range kubeconfigEntryKinds {
+		if n := len(loaded.entries(kind.listKey)); n != 1 {
+			t.Errorf("%s: expected only the foreign entry to be kept, got %v", kind.listKey, entryNames(loaded, kind.listKey))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type kubeconfigRemoveParams struct {
+	ClusterID      string `json:"cluster_id" jsonschema_description:"ID of the cluster previously merged with 'kubeconfig --merge'" mgc:"positional"`
+	KubeconfigFile string `json:"kubeconfig_file,omitempty" jsonschema_description:"Kubeconfig file to remove from. Defaults to all files in $KUBECONFIG or ~/.kube/config"`
+}
+
+type kubeconfigRemoveFileResult struct {
+	File   string `json:"file"`
+	Backup string `json:"backup"`
+	kubeconfigRemoved
+}
+
+type kubeconfigRemoveResult struct {
+	Files []kubeconfigRemoveFileResult `json:"files"`
+}
+
+var getKubeconfigRemove = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	var exec core.Executor = core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "kubeconfig-remove",
+			Summary: "Remove a merged cluster from the user's kubeconfig",
+			Description: `Remove the cluster, context and user entries added by 'kubeconfig --merge'.
+
+Entries are found by the cluster ID, even if they were renamed. The cluster and
+user entries of its contexts are removed as well, unless other contexts use them.
+If the current context is removed, it's unset. Files are backed up before being changed.
+Use this after deleting a cluster to keep the kubeconfig clean.`,
+		},
+		removeKubeconfig,
+	)
+
+	return core.NewExecuteResultOutputOptions(exec, func(exec core.Executor, result core.Result) string {
+		return "template={{if .files}}{{range .files}}Removed {{.contexts}} from {{.file}}\n{{end}}{{else}}Cluster not found in kubeconfig\n{{end}}"
+	})
+})
+
+func removeKubeconfig(_ context.Context, params kubeconfigRemoveParams, _ struct{}) (*kubeconfigRemoveResult, error) {
+	files, err := resolveKubeconfigFiles(params.KubeconfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &kubeconfigRemoveResult{Files: []kubeconfigRemoveFileResult{}}
+	for _, file := range files {
+		doc, err := loadKubeconfig(file)
+		if err != nil {
+			return nil, err
+		}
+
+		removed := doc.remove(params.ClusterID)
+		if removed.isEmpty() {
+			continue
+		}
+
+		backup, err := saveKubeconfig(file, doc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to save %q: %w", file, err)
+		}
+		logger().Debugw("removed cluster from kubeconfig", "file", file, "backup", backup, "removed", removed)
+
+		result.Files = append(result.Files, kubeconfigRemoveFileResult{file, backup, removed})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,5 @@
+package cluster
+
+import mgcLoggerPkg "github.com/MagaluCloud/magalu/mgc/core/logger"
+
+var logger = mgcLoggerPkg.NewLazy[kubeconfigMergeParams]()
@@ -0,0 +1,19 @@
+package kubernetes
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/kubernetes/cluster"
+)
+
+// Merged into the group of kubernetes.openapi.yaml by name, only the kubeconfig commands are static
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "kubernetes"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				cluster.GetGroup(), // kubernetes cluster
+			}
+		},
+	)
+})
@@ -47,6 +47,7 @@ func setACL(ctx context.Context, params setBucketACLParams, cfg common.Config) (
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
@@ -40,6 +40,7 @@ func deleteCors(ctx context.Context, params deleteBucketCorsParams, cfg common.C
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
@@ -45,6 +45,7 @@ func setCors(ctx context.Context, params setBucketCorsParams, cfg common.Config)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
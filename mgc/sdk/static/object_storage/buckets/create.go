@@ -111,6 +111,7 @@ func create(ctx context.Context, params createParams, cfg common.Config) (*creat
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
@@ -24,6 +24,7 @@ var GetGroup = utils.NewLazyLoader[core.Grouper](func() core.Grouper {
 				getList(),              // object-storage buckets list
 				getBucket(),            // object-storage buckets get
 				getPublicUrl(),         // object-storage objects public-url
+				getUsage(),             // object-storage buckets usage
 				acl.GetGroup(),         // object-storage buckets acl
 				versioning.GetGroup(),  // object-storage buckets versioning
 				policy.GetGroup(),      // object-storage buckets policy
@@ -57,6 +57,7 @@ func deleteLabels(ctx context.Context, params deleteBucketLabelParams, cfg commo
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
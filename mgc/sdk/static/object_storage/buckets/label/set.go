@@ -85,6 +85,7 @@ func setLabels(ctx context.Context, params setBucketLabelParams, cfg common.Conf
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
@@ -53,6 +53,7 @@ func setObjectLocking(ctx context.Context, params setBucketObjectLockParams, cfg
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
@@ -42,6 +42,7 @@ func unsetObjectLocking(ctx context.Context, params unsetBucketObjectLockParams,
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
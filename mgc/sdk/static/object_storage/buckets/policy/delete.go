@@ -40,6 +40,7 @@ func deletePolicy(ctx context.Context, params deleteBucketPolicyParams, cfg comm
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
@@ -44,6 +44,7 @@ func setPolicy(ctx context.Context, params setBucketPolicyParams, cfg common.Con
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
@@ -0,0 +1,57 @@
+package buckets
+
+import (
+	"context"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/buckets/versioning"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+type usageParams struct {
+	Destination mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Path of the bucket to report the usage of. A path inside the bucket limits the report to that prefix,example=bucket1" mgc:"positional"`
+	Depth       int              `json:"depth,omitempty" jsonschema:"description=Number of '/' separated levels used to group the objects by prefix,default=1,minimum=0"`
+}
+
+var getUsage = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	executor := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "usage",
+			Summary:     "Report the size and contents of a bucket",
+			Description: "Page through all objects of the bucket (and their versions, if versioning was ever enabled) and report the total size, object counts by prefix, storage class and age, the noncurrent version overhead and the incomplete multipart uploads",
+		},
+		bucketUsage,
+	)
+	return core.NewExecuteResultOutputOptions(executor, func(exec core.Executor, result core.Result) string {
+		return "yaml"
+	})
+})
+
+func bucketUsage(ctx context.Context, p usageParams, cfg common.Config) (*common.UsageReport, error) {
+	withVersions, err := versioning.BucketHasVersions(ctx, cfg, common.NewBucketNameFromURI(p.Destination))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := common.NewUsageAccumulator(p.Destination, withVersions, p.Depth, time.Now())
+	err = common.ListInventory(ctx, cfg, p.Destination, withVersions, func(obj common.InventoryObject) error {
+		usage.Add(obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = common.ListMultipartUploads(ctx, cfg, p.Destination, func(upload common.MultipartUpload) error {
+		usage.AddUpload(upload)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return usage.Report(), nil
+}
@@ -54,3 +54,13 @@ func newGetBucketVersioningRequest(ctx context.Context, bucketName common.Bucket
 
 	return http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
 }
+
+// Whether the bucket may hold more than one version of its objects. Suspended buckets keep
+// the versions created while versioning was enabled
+func BucketHasVersions(ctx context.Context, cfg common.Config, bucketName common.BucketName) (bool, error) {
+	result, err := GetBucketVersioning(ctx, GetBucketVersioningParams{Bucket: bucketName}, cfg)
+	if err != nil {
+		return false, err
+	}
+	return result.Status == "Enabled" || result.Status == "Suspended", nil
+}
@@ -44,10 +44,6 @@ func (u *bigFileCopier) newPreparationRequest(ctx context.Context) (*http.Reques
 	}
 	q := req.URL.Query()
 	q.Set("uploads", "")
-
-	if u.version != "" {
-		q.Set("versionId", u.version)
-	}
 	req.URL.RawQuery = q.Encode()
 
 	return req, nil
@@ -128,6 +124,7 @@ func (u *bigFileCopier) sendCompletionRequest(ctx context.Context, parts []compl
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	err = ExtractErr(resp, req)
 	if err != nil {
@@ -174,7 +171,32 @@ func (u *bigFileCopier) createPartSenderProcessor(cancel context.CancelCauseFunc
 	}
 }
 
-func (u *bigFileCopier) Copy(ctx context.Context) error {
+// The parts of an upload that is neither completed nor aborted are kept, and billed,
+// so failed copies must abort it
+func (u *bigFileCopier) abortUpload(ctx context.Context, uploadId string) {
+	req, err := newUploadRequest(ctx, u.cfg, u.dst, nil)
+	if err == nil {
+		req.Method = http.MethodDelete
+		q := req.URL.Query()
+		q.Set("uploadId", uploadId)
+		req.URL.RawQuery = q.Encode()
+
+		var resp *http.Response
+		if resp, err = SendRequest(ctx, req, u.cfg); err == nil {
+			defer resp.Body.Close()
+			err = ExtractErr(resp, req)
+		}
+	}
+
+	if err != nil {
+		bigfileUploaderLogger().Warnw("unable to abort the multipart upload", "dst", u.dst, "uploadId", uploadId, "error", err)
+		return
+	}
+	bigfileUploaderLogger().Debugw("aborted the multipart upload", "dst", u.dst, "uploadId", uploadId)
+	u.uploadId = ""
+}
+
+func (u *bigFileCopier) Copy(ctx context.Context) (err error) {
 	bigfileUploaderLogger().Debug("start")
 
 	name := "Preparing to copy " + u.src.String()
@@ -188,6 +210,11 @@ func (u *bigFileCopier) Copy(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			u.abortUpload(context.WithoutCancel(ctx), uploadId)
+		}
+	}()
 
 	chunkChan := pipeline.PrepareWriteChunks(ctx, nil, u.fileSize, int64(u.cfg.chunkSizeInBytes()))
 	partChan := pipeline.ParallelProcess(ctx, u.cfg.Workers, chunkChan, u.createPartSenderProcessor(cancel, uploadId), nil)
@@ -38,6 +38,7 @@ func (u *bigFileDownloader) createPartDownloaderProcessor(cancel context.CancelC
 			cancel(err)
 			return err, pipeline.ProcessAbort
 		}
+		defer resp.Body.Close()
 
 		err = ExtractErr(resp, req)
 		if err != nil {
@@ -45,7 +46,8 @@ func (u *bigFileDownloader) createPartDownloaderProcessor(cancel context.CancelC
 			return err, pipeline.ProcessAbort
 		}
 
-		reporterWriter := progress_report.NewReporterWriter(chunk.Writer, u.progressReporter.Report)
+		limits := getTransferLimits(cfg)
+		reporterWriter := limits.limitWriter(ctx, progress_report.NewReporterWriter(chunk.Writer, u.progressReporter.Report))
 
 		_, err = io.Copy(reporterWriter, resp.Body)
 		if err != nil {
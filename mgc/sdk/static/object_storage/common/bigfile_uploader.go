@@ -158,6 +158,7 @@ func (u *bigFileUploader) sendCompletionRequest(ctx context.Context, parts []com
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	err = ExtractErr(resp, req)
 	if err != nil {
@@ -188,6 +189,7 @@ func (u *bigFileUploader) createPartSenderProcessor(cancel context.CancelCauseFu
 			cancel(err)
 			return part, pipeline.ProcessAbort
 		}
+		defer res.Body.Close()
 
 		err = ExtractErr(res, req)
 		if err != nil {
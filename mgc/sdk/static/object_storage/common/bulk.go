@@ -0,0 +1,107 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	"github.com/MagaluCloud/magalu/mgc/core/pipeline"
+	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type BulkObjectFunc func(ctx context.Context, objURI mgcSchemaPkg.URI) error
+
+// Processes every object in the listing, so the failure of one object doesn't stop the others.
+// The failures are output as *ObjectError
+func createBulkObjectProcessor(bucketName BucketName, apply BulkObjectFunc, applied *atomic.Int64, progressReporter *progress_report.UnitsReporter) pipeline.Processor[pipeline.WalkDirEntry, error] {
+	rootURI := bucketName.AsURI()
+	return func(ctx context.Context, dirEntry pipeline.WalkDirEntry) (error, pipeline.ProcessStatus) {
+		var err error
+		defer func() { progressReporter.ReportItem(dirEntry.Path(), err) }()
+
+		objURI := rootURI.JoinPath(dirEntry.Path())
+		if dirEntry.Err() != nil {
+			err = &ObjectError{Url: objURI, Err: dirEntry.Err()}
+			return err, pipeline.ProcessOutput
+		}
+
+		if _, ok := dirEntry.DirEntry().(*BucketContent); !ok {
+			err = &ObjectError{Url: objURI, Err: fmt.Errorf("expected object, got directory")}
+			return err, pipeline.ProcessOutput
+		}
+
+		if err = apply(ctx, objURI); err != nil {
+			err = &ObjectError{Url: objURI, Err: err}
+			return err, pipeline.ProcessOutput
+		}
+
+		applied.Add(1)
+		return nil, pipeline.ProcessOutput
+	}
+}
+
+// Calls apply for every object under prefix, with up to cfg.Workers objects at a time. The
+// count of objects it succeeded for is returned, and the failures as a utils.MultiError of
+// *ObjectError, one per object
+func ApplyToObjects(ctx context.Context, cfg Config, prefix mgcSchemaPkg.URI, progressReportMsg string, apply BulkObjectFunc) (int64, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	listParams := ListObjectsParams{
+		Destination: prefix,
+		Recursive:   true,
+		PaginationParams: PaginationParams{
+			MaxItems: math.MaxInt64,
+		},
+	}
+
+	progressReporter := progress_report.NewUnitsReporter(ctx, progressReportMsg, 0)
+	progressReporter.Start()
+	defer progressReporter.End()
+
+	onNewPage := func(objCount uint64) {
+		progressReporter.Report(0, objCount, nil)
+	}
+
+	var applied atomic.Int64
+	objs := ListGenerator(ctx, listParams, cfg, onNewPage)
+	errChan := pipeline.ParallelProcess(ctx, cfg.Workers, objs, createBulkObjectProcessor(NewBucketNameFromURI(prefix), apply, &applied, progressReporter), nil)
+	errChan = pipeline.Filter(ctx, errChan, pipeline.FilterNonNil[error]{})
+
+	objErr, err := pipeline.SliceItemConsumer[utils.MultiError](ctx, errChan)
+	if err != nil {
+		progressReporter.Report(0, 0, err)
+		return applied.Load(), err
+	}
+	if len(objErr) > 0 {
+		progressReporter.Report(0, 0, objErr)
+		return applied.Load(), objErr
+	}
+
+	return applied.Load(), nil
+}
+
+// Number of objects under prefix that ApplyToObjects would apply to
+func CountObjects(ctx context.Context, cfg Config, prefix mgcSchemaPkg.URI) (count int64, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	listParams := ListObjectsParams{
+		Destination: prefix,
+		Recursive:   true,
+		PaginationParams: PaginationParams{
+			MaxItems: math.MaxInt64,
+		},
+	}
+
+	for dirEntry := range ListGenerator(ctx, listParams, cfg, nil) {
+		if err = dirEntry.Err(); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
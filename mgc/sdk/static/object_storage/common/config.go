@@ -6,6 +6,10 @@ type Config struct {
 	Workers   int    `json:"workers,omitempty" jsonschema:"description=Number of routines that spawn to do parallel operations within object_storage,default=5,minimum=1,required"`
 	ChunkSize uint64 `json:"chunkSize,omitempty" jsonschema:"description=Chunk size to consider when doing multipart requests. Specified in Mb,default=8,minimum=8,maximum=5120,required"`
 	Region    string `json:"region,omitempty" jsonschema:"description=Region to reach the service,default=br-se1"`
+	KeyPair   string `json:"keyPair,omitempty" jsonschema:"description=Name of the key pair to sign the requests with\\, instead of the one set for the bucket or the default one"`
+	// Limits shared by all the transfers of the process
+	MaxBandwidth uint64 `json:"maxBandwidth,omitempty" jsonschema:"description=Maximum transfer rate in bytes per second\\, combining uploads and downloads. 0 means unlimited,default=0,minimum=0"`
+	MaxInFlight  int    `json:"maxInFlight,omitempty" jsonschema:"description=Maximum number of requests in flight\\, combining the parallel files and the parts of each file. 0 means unlimited,default=0,minimum=0"`
 
 	// See more about the 'squash' directive here: https://pkg.go.dev/github.com/mitchellh/mapstructure#hdr-Embedded_Structs_and_Squashing
 	config.NetworkConfig `json:",squash"` // nolint
@@ -58,14 +58,13 @@ func newCopyRequest(ctx context.Context, cfg Config, src mgcSchemaPkg.URI, dst m
 		return nil, core.UsageError{Err: fmt.Errorf("badly specified source URI: %w", err)}
 	}
 
-	req.Header.Set("x-amz-copy-source", copySource)
-
+	// The version is of the source object, so it goes in its header instead of the URL query
 	if version != "" {
-		query := req.URL.Query()
-		query.Set("versionId", version)
-		req.URL.RawQuery = query.Encode()
+		copySource += "?versionId=" + url.QueryEscape(version)
 	}
 
+	req.Header.Set("x-amz-copy-source", copySource)
+
 	return req, nil
 }
 
@@ -75,7 +74,7 @@ func createObjectCopyProcessor(cfg Config, params CopyAllObjectsParams, progress
 		rootURI := bucketName.AsURI()
 		var err error
 
-		defer func() { progressReporter.Report(1, 0, err) }()
+		defer func() { progressReporter.ReportItem(dirEntry.Path(), err) }()
 		path := dirEntry.Path()
 		objURI := rootURI.JoinPath(path)
 
@@ -162,8 +161,19 @@ func CopySingleFile(ctx context.Context, cfg Config, src mgcSchemaPkg.URI, dst m
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	return ExtractErr(resp, req)
+	err = ExtractErr(resp, req)
+	if !isAccessDenied(err) || !usesDifferentKeyPairs(ctx, cfg, NewBucketNameFromURI(src), NewBucketNameFromURI(dst)) {
+		return err
+	}
+
+	copyAllLogger().Infow("Server-side copy denied, streaming the object", "src", src, "dst", dst, "error", err)
+	metadata, err := HeadFile(ctx, cfg, src, "")
+	if err != nil {
+		return err
+	}
+	return newStreamCopier(cfg, src, dst, metadata, "", storageClass).Copy(ctx)
 }
 
 func NewCopier(ctx context.Context, cfg Config, src mgcSchemaPkg.URI, dst mgcSchemaPkg.URI, version string, storageClass string) (copier, error) {
@@ -172,6 +182,17 @@ func NewCopier(ctx context.Context, cfg Config, src mgcSchemaPkg.URI, dst mgcSch
 		return nil, err
 	}
 
+	serverSide := newServerSideCopier(cfg, src, dst, metadata, version, storageClass)
+	if usesDifferentKeyPairs(ctx, cfg, NewBucketNameFromURI(src), NewBucketNameFromURI(dst)) {
+		return &fallbackCopier{
+			copier: serverSide,
+			stream: newStreamCopier(cfg, src, dst, metadata, version, storageClass),
+		}, nil
+	}
+	return serverSide, nil
+}
+
+func newServerSideCopier(cfg Config, src mgcSchemaPkg.URI, dst mgcSchemaPkg.URI, metadata HeadObjectResponse, version string, storageClass string) copier {
 	totalCopyParts := int(math.Ceil(float64(metadata.ContentLength) / float64(cfg.chunkSizeInBytes())))
 
 	if totalCopyParts > 1 {
@@ -181,14 +202,16 @@ func NewCopier(ctx context.Context, cfg Config, src mgcSchemaPkg.URI, dst mgcSch
 			dst:          dst,
 			fileSize:     metadata.ContentLength,
 			totalParts:   totalCopyParts,
+			version:      version,
 			storageClass: storageClass,
-		}, nil
+		}
 	} else {
 		return &smallFileCopier{
 			cfg:          cfg,
 			src:          src,
 			dst:          dst,
+			version:      version,
 			storageClass: storageClass,
-		}, nil
+		}
 	}
 }
@@ -39,6 +39,9 @@ type DeleteObjectsParams struct {
 	Destination mgcSchemaPkg.URI
 	ToDelete    <-chan pipeline.WalkDirEntry
 	BatchSize   int
+	// Delete the VersionID of each object permanently. Otherwise versioned buckets keep the
+	// objects and get delete markers for them
+	WithVersions bool
 }
 
 type DeleteAllObjectsInBucketParams struct {
@@ -88,6 +91,7 @@ func DeleteSingle(ctx context.Context, params DeleteObjectParams, cfg Config) er
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	err = ExtractErr(resp, req)
 	if err != nil {
@@ -144,7 +148,7 @@ func newDeleteBatchRequest(ctx context.Context, cfg Config, bucketName BucketNam
 
 // Deleting an object does not yield result except there is an error. So this processor will *Skip*
 // success results and *Output* errors
-func createObjectDeletionProcessor(cfg Config, bucketName BucketName, progressReporter *progress_report.UnitsReporter) pipeline.Processor[[]pipeline.WalkDirEntry, error] {
+func createObjectDeletionProcessor(cfg Config, bucketName BucketName, withVersions bool, progressReporter *progress_report.UnitsReporter) pipeline.Processor[[]pipeline.WalkDirEntry, error] {
 	return func(ctx context.Context, dirEntries []pipeline.WalkDirEntry) (error, pipeline.ProcessStatus) {
 		progressReporter.Report(0, uint64(len(dirEntries)), nil)
 
@@ -164,7 +168,11 @@ func createObjectDeletionProcessor(cfg Config, bucketName BucketName, progressRe
 				return &ObjectError{Err: err}, pipeline.ProcessAbort
 			}
 
-			objIdentifiers = append(objIdentifiers, objectIdentifier{Key: obj.Key})
+			identifier := objectIdentifier{Key: obj.Key}
+			if withVersions {
+				identifier.VersionId = obj.VersionID
+			}
+			objIdentifiers = append(objIdentifiers, identifier)
 		}
 
 		defer func() { progressReporter.Report(uint64(len(dirEntries)), 0, err) }()
@@ -178,6 +186,7 @@ func createObjectDeletionProcessor(cfg Config, bucketName BucketName, progressRe
 		if err != nil {
 			return &ObjectError{Url: mgcSchemaPkg.URI(bucketName), Err: err}, pipeline.ProcessOutput
 		}
+		defer resp.Body.Close()
 
 		err = ExtractErr(resp, req)
 		if err != nil {
@@ -219,7 +228,7 @@ func DeleteAllObjectsInBucket(ctx context.Context, params DeleteAllObjectsInBuck
 	}
 
 	objsBatch := pipeline.Batch(ctx, objs, params.BatchSize)
-	deleteObjectsErrorChan := pipeline.ParallelProcess(ctx, cfg.Workers, objsBatch, createObjectDeletionProcessor(cfg, params.BucketName, progressReporter), nil)
+	deleteObjectsErrorChan := pipeline.ParallelProcess(ctx, cfg.Workers, objsBatch, createObjectDeletionProcessor(cfg, params.BucketName, false, progressReporter), nil)
 	deleteObjectsErrorChan = pipeline.Filter(ctx, deleteObjectsErrorChan, pipeline.FilterNonNil[error]{})
 
 	objErr, err := pipeline.SliceItemConsumer[utils.MultiError](ctx, deleteObjectsErrorChan)
@@ -243,6 +252,7 @@ func DeleteBucket(ctx context.Context, params DeleteBucketParams, cfg Config) er
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	// GA - TEMP
 	if resp.StatusCode == 409 {
@@ -265,6 +275,7 @@ func Delete(ctx context.Context, params DeleteObjectParams, cfg Config) error {
 		if err != nil {
 			return err
 		}
+		defer resp.Body.Close()
 
 		err = ExtractErr(resp, req)
 		if err != nil {
@@ -293,7 +304,7 @@ func DeleteObjects(ctx context.Context, params DeleteObjectsParams, cfg Config)
 	}
 
 	objsBatch := pipeline.Batch(ctx, params.ToDelete, params.BatchSize)
-	deleteObjectsErrorChan := pipeline.ParallelProcess(ctx, cfg.Workers, objsBatch, createObjectDeletionProcessor(cfg, bucketName, progressReporter), nil)
+	deleteObjectsErrorChan := pipeline.ParallelProcess(ctx, cfg.Workers, objsBatch, createObjectDeletionProcessor(cfg, bucketName, params.WithVersions, progressReporter), nil)
 	deleteObjectsErrorChan = pipeline.Filter(ctx, deleteObjectsErrorChan, pipeline.FilterNonNil[error]{})
 
 	objErr, _ := pipeline.SliceItemConsumer[utils.MultiError](ctx, deleteObjectsErrorChan)
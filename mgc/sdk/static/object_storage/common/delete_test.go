@@ -0,0 +1,83 @@
+package common
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	"github.com/MagaluCloud/magalu/mgc/core/pipeline"
+)
+
+// Test server listing one object with a version, returning the bodies of the batch deletions
+func newDeleteTestServer(t *testing.T) (*httptest.Server, func() []string) {
+	var mutex sync.Mutex
+	var deletions []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = io.WriteString(w, `<ListBucketResult><Name>bucket1</Name><IsTruncated>false</IsTruncated>
+  <Contents><Key>a.txt</Key><VersionId>v1</VersionId><LastModified>2024-07-01T00:00:00.000Z</LastModified><Size>10</Size></Contents>
+</ListBucketResult>`)
+		case r.Method == http.MethodPost && r.URL.Query().Has("delete"):
+			data, _ := io.ReadAll(r.Body)
+			mutex.Lock()
+			deletions = append(deletions, string(data))
+			mutex.Unlock()
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = io.WriteString(w, "<DeleteResult></DeleteResult>")
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+
+	return server, func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return deletions
+	}
+}
+
+func TestDeleteAllObjectsInBucketKeepsVersions(t *testing.T) {
+	server, deletions := newDeleteTestServer(t)
+	defer server.Close()
+
+	cfg := Config{Workers: 1, Region: "br-se1", NetworkConfig: config.NetworkConfig{ServerUrl: server.URL}}
+	params := DeleteAllObjectsInBucketParams{BucketName: "bucket1", BatchSize: MaxBatchSize}
+	if err := DeleteAllObjectsInBucket(newTestServerContext(t, server), params, cfg); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got := deletions()
+	if len(got) != 1 || !strings.Contains(got[0], "<Key>a.txt</Key>") {
+		t.Fatalf("expected one deletion of a.txt, got %v", got)
+	}
+	if strings.Contains(got[0], "VersionId") {
+		t.Errorf("expected no VersionId, so versioned buckets get a delete marker, got %s", got[0])
+	}
+}
+
+func TestDeleteObjectsWithVersions(t *testing.T) {
+	server, deletions := newDeleteTestServer(t)
+	defer server.Close()
+
+	toDelete := make(chan pipeline.WalkDirEntry, 1)
+	toDelete <- pipeline.NewSimpleWalkDirEntry("a.txt", &BucketContent{Key: "a.txt", VersionID: "v1"}, nil)
+	close(toDelete)
+
+	cfg := Config{Workers: 1, Region: "br-se1", NetworkConfig: config.NetworkConfig{ServerUrl: server.URL}}
+	params := DeleteObjectsParams{Destination: "s3://bucket1", ToDelete: toDelete, BatchSize: MaxBatchSize, WithVersions: true}
+	if err := DeleteObjects(newTestServerContext(t, server), params, cfg); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := deletions(); len(got) != 1 || !strings.Contains(got[0], "<VersionId>v1</VersionId>") {
+		t.Errorf("expected the deletion of version v1, got %v", got)
+	}
+}
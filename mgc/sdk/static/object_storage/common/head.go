@@ -48,6 +48,7 @@ func HeadFile(ctx context.Context, cfg Config, dst mgcSchemaPkg.URI, version str
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = ExtractErr(resp, req)
 	if err != nil {
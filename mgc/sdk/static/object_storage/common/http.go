@@ -20,6 +20,8 @@ type XMLError struct {
 }
 
 func UnwrapResponse[T any](resp *http.Response, req *http.Request) (result T, err error) {
+	defer resp.Body.Close()
+
 	if err = ExtractErr(resp, req); err != nil {
 		return
 	}
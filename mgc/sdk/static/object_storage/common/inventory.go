@@ -0,0 +1,396 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+)
+
+const defaultStorageClass = "STANDARD"
+
+// One row of the inventory. All fields are always present, so every line of the JSONL
+// output has the same columns
+type InventoryObject struct {
+	Key            string `json:"key"`
+	VersionID      string `json:"version_id"`
+	IsLatest       bool   `json:"is_latest"`
+	IsDeleteMarker bool   `json:"is_delete_marker"`
+	Size           int64  `json:"size"`
+	StorageClass   string `json:"storage_class"`
+	LastModified   string `json:"last_modified"`
+	ETag           string `json:"etag"`
+}
+
+var InventoryColumns = []string{"key", "version_id", "is_latest", "is_delete_marker", "size", "storage_class", "last_modified", "etag"}
+
+func (o InventoryObject) Record() []string {
+	return []string{
+		o.Key,
+		o.VersionID,
+		fmt.Sprint(o.IsLatest),
+		fmt.Sprint(o.IsDeleteMarker),
+		fmt.Sprint(o.Size),
+		o.StorageClass,
+		o.LastModified,
+		o.ETag,
+	}
+}
+
+type MultipartUpload struct {
+	Key          string `xml:"Key" json:"key"`
+	UploadID     string `xml:"UploadId" json:"upload_id"`
+	Initiated    string `xml:"Initiated" json:"initiated"`
+	StorageClass string `xml:"StorageClass" json:"storage_class"`
+}
+
+type listVersionsPageResponse struct {
+	Versions            []*inventoryVersion `xml:"Version"`
+	DeleteMarkers       []*inventoryVersion `xml:"DeleteMarker"`
+	IsTruncated         bool                `xml:"IsTruncated"`
+	NextKeyMarker       string              `xml:"NextKeyMarker"`
+	NextVersionIdMarker string              `xml:"NextVersionIdMarker"`
+}
+
+type inventoryVersion struct {
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type listMultipartUploadsResponse struct {
+	Uploads            []*MultipartUpload `xml:"Upload"`
+	IsTruncated        bool               `xml:"IsTruncated"`
+	NextKeyMarker      string             `xml:"NextKeyMarker"`
+	NextUploadIdMarker string             `xml:"NextUploadIdMarker"`
+}
+
+func (r *listVersionsPageResponse) objects() []InventoryObject {
+	objects := make([]InventoryObject, 0, len(r.Versions)+len(r.DeleteMarkers))
+	for _, v := range r.Versions {
+		objects = append(objects, InventoryObject{
+			Key:          v.Key,
+			VersionID:    v.VersionID,
+			IsLatest:     v.IsLatest,
+			Size:         v.Size,
+			StorageClass: v.StorageClass,
+			LastModified: v.LastModified,
+			ETag:         strings.Trim(v.ETag, `"`),
+		})
+	}
+	for _, v := range r.DeleteMarkers {
+		objects = append(objects, InventoryObject{
+			Key:            v.Key,
+			VersionID:      v.VersionID,
+			IsLatest:       v.IsLatest,
+			IsDeleteMarker: true,
+			LastModified:   v.LastModified,
+		})
+	}
+	// versions and delete markers are interleaved in the response, newest first for each key,
+	// but they're decoded separately
+	sort.SliceStable(objects, func(i, j int) bool {
+		if objects[i].Key != objects[j].Key {
+			return objects[i].Key < objects[j].Key
+		}
+		return objects[i].LastModified > objects[j].LastModified
+	})
+	return objects
+}
+
+// Query parts must be sorted for sigv4, see newListRequest()
+func newMarkerListRequest(ctx context.Context, cfg Config, bucketURI mgcSchemaPkg.URI, listing string, markers map[string]string) (*http.Request, error) {
+	u, err := buildListRequestURL(cfg, bucketURI)
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	queryStringParts := []string{listing + "="}
+	if prefix := listPrefix(bucketURI); prefix != "" {
+		queryStringParts = append(queryStringParts, "prefix="+awsQueryEscape(prefix))
+	}
+	for k, v := range markers {
+		if v != "" {
+			queryStringParts = append(queryStringParts, k+"="+awsQueryEscape(v))
+		}
+	}
+
+	sort.Strings(queryStringParts)
+	u.RawQuery = strings.Join(queryStringParts, "&")
+	return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+}
+
+func sendListRequest[T any](ctx context.Context, cfg Config, req *http.Request) (result T, err error) {
+	resp, err := SendRequest(ctx, req, cfg)
+	if err != nil {
+		return
+	}
+	return UnwrapResponse[T](resp, req)
+}
+
+// Pages through all objects under the prefix of bucketURI, with ListObjectVersions if
+// withVersions, otherwise with ListObjectsV2 and only the current versions
+func ListInventory(ctx context.Context, cfg Config, bucketURI mgcSchemaPkg.URI, withVersions bool, cb func(obj InventoryObject) error) error {
+	if withVersions {
+		return listInventoryVersions(ctx, cfg, bucketURI, cb)
+	}
+
+	page := PaginationParams{MaxItems: ApiLimitMaxItems}
+	for {
+		req, err := newListRequest(ctx, cfg, bucketURI, page, true)
+		if err != nil {
+			return err
+		}
+		result, err := sendListRequest[listObjectsRequestResponse](ctx, cfg, req)
+		if err != nil {
+			return err
+		}
+
+		for _, content := range result.Contents {
+			err := cb(InventoryObject{
+				Key:          content.Key,
+				IsLatest:     true,
+				Size:         content.ContentSize,
+				StorageClass: content.StorageClass,
+				LastModified: content.LastModified,
+				ETag:         strings.Trim(content.ETag, `"`),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return nil
+		}
+		page.ContinuationToken = result.NextContinuationToken
+	}
+}
+
+func listInventoryVersions(ctx context.Context, cfg Config, bucketURI mgcSchemaPkg.URI, cb func(obj InventoryObject) error) error {
+	markers := map[string]string{"max-keys": fmt.Sprint(ApiLimitMaxItems)}
+	for {
+		req, err := newMarkerListRequest(ctx, cfg, bucketURI, "versions", markers)
+		if err != nil {
+			return err
+		}
+		result, err := sendListRequest[listVersionsPageResponse](ctx, cfg, req)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range result.objects() {
+			if err := cb(obj); err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated || result.NextKeyMarker == "" {
+			return nil
+		}
+		markers["key-marker"] = result.NextKeyMarker
+		markers["version-id-marker"] = result.NextVersionIdMarker
+	}
+}
+
+// Pages through the multipart uploads that were neither completed nor aborted
+func ListMultipartUploads(ctx context.Context, cfg Config, bucketURI mgcSchemaPkg.URI, cb func(upload MultipartUpload) error) error {
+	markers := map[string]string{"max-uploads": fmt.Sprint(ApiLimitMaxItems)}
+	for {
+		req, err := newMarkerListRequest(ctx, cfg, bucketURI, "uploads", markers)
+		if err != nil {
+			return err
+		}
+		result, err := sendListRequest[listMultipartUploadsResponse](ctx, cfg, req)
+		if err != nil {
+			return err
+		}
+
+		for _, upload := range result.Uploads {
+			if err := cb(*upload); err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated || result.NextKeyMarker == "" {
+			return nil
+		}
+		markers["key-marker"] = result.NextKeyMarker
+		markers["upload-id-marker"] = result.NextUploadIdMarker
+	}
+}
+
+type UsageGroup struct {
+	Name    string `json:"name"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+type IncompleteUploadsUsage struct {
+	Count           int64  `json:"count"`
+	OldestInitiated string `json:"oldest_initiated,omitempty"`
+}
+
+type UsageReport struct {
+	Bucket             string                 `json:"bucket"`
+	Prefix             string                 `json:"prefix,omitempty"`
+	Versioning         bool                   `json:"versioning"`
+	Objects            int64                  `json:"objects"`
+	Size               int64                  `json:"size"`
+	ByPrefix           []UsageGroup           `json:"by_prefix"`
+	ByStorageClass     []UsageGroup           `json:"by_storage_class"`
+	ByAge              []UsageGroup           `json:"by_age"`
+	NoncurrentVersions UsageGroup             `json:"noncurrent_versions"`
+	DeleteMarkers      int64                  `json:"delete_markers"`
+	IncompleteUploads  IncompleteUploadsUsage `json:"incomplete_multipart_uploads"`
+}
+
+type usageAge struct {
+	name   string
+	maxAge time.Duration
+}
+
+const day = 24 * time.Hour
+
+var usageAges = []usageAge{
+	{"< 1 day", day},
+	{"1-7 days", 7 * day},
+	{"7-30 days", 30 * day},
+	{"30-90 days", 90 * day},
+	{"90-365 days", 365 * day},
+	{"> 365 days", 0},
+}
+
+const usageRootPrefix = "(root)"
+
+// Sums the objects of an inventory into a UsageReport. The current versions are grouped by
+// prefix, up to depth "/" separated levels, storage class and age
+type UsageAccumulator struct {
+	report         UsageReport
+	depth          int
+	now            time.Time
+	byPrefix       map[string]*UsageGroup
+	byStorageClass map[string]*UsageGroup
+	byAge          []UsageGroup
+	oldestUpload   time.Time
+}
+
+func NewUsageAccumulator(bucketURI mgcSchemaPkg.URI, versioning bool, depth int, now time.Time) *UsageAccumulator {
+	a := &UsageAccumulator{
+		report: UsageReport{
+			Bucket:     NewBucketNameFromURI(bucketURI).String(),
+			Prefix:     listPrefix(bucketURI),
+			Versioning: versioning,
+		},
+		depth:          depth,
+		now:            now,
+		byPrefix:       map[string]*UsageGroup{},
+		byStorageClass: map[string]*UsageGroup{},
+		byAge:          make([]UsageGroup, len(usageAges)),
+	}
+	a.report.NoncurrentVersions.Name = "noncurrent"
+	for i, age := range usageAges {
+		a.byAge[i].Name = age.name
+	}
+	return a
+}
+
+func addToUsageGroup(groups map[string]*UsageGroup, name string, size int64) {
+	g := groups[name]
+	if g == nil {
+		g = &UsageGroup{Name: name}
+		groups[name] = g
+	}
+	g.Objects++
+	g.Size += size
+}
+
+func (a *UsageAccumulator) prefixOf(key string) string {
+	key = strings.TrimPrefix(key, a.report.Prefix)
+	parts := strings.Split(key, delimiter)
+	// the last part is the object name
+	n := min(a.depth, len(parts)-1)
+	if n <= 0 {
+		return usageRootPrefix
+	}
+	return a.report.Prefix + strings.Join(parts[:n], delimiter) + delimiter
+}
+
+func (a *UsageAccumulator) ageIndex(lastModified string) int {
+	modTime, err := time.Parse(time.RFC3339, lastModified)
+	if err != nil {
+		return len(usageAges) - 1
+	}
+	age := a.now.Sub(modTime)
+	for i, bucket := range usageAges {
+		if bucket.maxAge == 0 || age < bucket.maxAge {
+			return i
+		}
+	}
+	return len(usageAges) - 1
+}
+
+func (a *UsageAccumulator) Add(obj InventoryObject) {
+	switch {
+	case obj.IsDeleteMarker:
+		a.report.DeleteMarkers++
+		return
+	case !obj.IsLatest:
+		a.report.NoncurrentVersions.Objects++
+		a.report.NoncurrentVersions.Size += obj.Size
+		return
+	}
+
+	a.report.Objects++
+	a.report.Size += obj.Size
+
+	storageClass := obj.StorageClass
+	if storageClass == "" {
+		storageClass = defaultStorageClass
+	}
+	addToUsageGroup(a.byPrefix, a.prefixOf(obj.Key), obj.Size)
+	addToUsageGroup(a.byStorageClass, storageClass, obj.Size)
+
+	age := &a.byAge[a.ageIndex(obj.LastModified)]
+	age.Objects++
+	age.Size += obj.Size
+}
+
+func (a *UsageAccumulator) AddUpload(upload MultipartUpload) {
+	a.report.IncompleteUploads.Count++
+	initiated, err := time.Parse(time.RFC3339, upload.Initiated)
+	if err != nil {
+		return
+	}
+	if a.oldestUpload.IsZero() || initiated.Before(a.oldestUpload) {
+		a.oldestUpload = initiated
+		a.report.IncompleteUploads.OldestInitiated = upload.Initiated
+	}
+}
+
+func sortedUsageGroups(groups map[string]*UsageGroup) []UsageGroup {
+	result := make([]UsageGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	slices.SortFunc(result, func(a, b UsageGroup) int { return strings.Compare(a.Name, b.Name) })
+	return result
+}
+
+func (a *UsageAccumulator) Report() *UsageReport {
+	report := a.report
+	report.ByPrefix = sortedUsageGroups(a.byPrefix)
+	report.ByStorageClass = sortedUsageGroups(a.byStorageClass)
+	report.ByAge = slices.DeleteFunc(slices.Clone(a.byAge), func(g UsageGroup) bool { return g.Objects == 0 })
+	return &report
+}
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+)
+
+const listVersionsPage = `<?xml version="1.0" encoding="UTF-8"?>
+<ListVersionsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Name>bucket1</Name>
+  <IsTruncated>true</IsTruncated>
+  <NextKeyMarker>b.txt</NextKeyMarker>
+  <NextVersionIdMarker>v3</NextVersionIdMarker>
+  <Version>
+    <Key>a.txt</Key><VersionId>v1</VersionId><IsLatest>true</IsLatest>
+    <LastModified>2024-07-01T00:00:00.000Z</LastModified><ETag>"abc"</ETag><Size>10</Size><StorageClass>STANDARD</StorageClass>
+  </Version>
+  <DeleteMarker>
+    <Key>b.txt</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest><LastModified>2024-07-02T00:00:00.000Z</LastModified>
+  </DeleteMarker>
+  <Version>
+    <Key>b.txt</Key><VersionId>v2</VersionId><IsLatest>false</IsLatest>
+    <LastModified>2024-07-01T00:00:00.000Z</LastModified><ETag>"def"</ETag><Size>20</Size><StorageClass>COLD_INSTANT</StorageClass>
+  </Version>
+</ListVersionsResult>`
+
+func TestListVersionsPageObjects(t *testing.T) {
+	var page listVersionsPageResponse
+	if err := xml.Unmarshal([]byte(listVersionsPage), &page); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !page.IsTruncated || page.NextKeyMarker != "b.txt" || page.NextVersionIdMarker != "v3" {
+		t.Errorf("unexpected pagination: %#v", page)
+	}
+
+	expected := []InventoryObject{
+		{Key: "a.txt", VersionID: "v1", IsLatest: true, Size: 10, StorageClass: "STANDARD", LastModified: "2024-07-01T00:00:00.000Z", ETag: "abc"},
+		{Key: "b.txt", VersionID: "v3", IsLatest: true, IsDeleteMarker: true, LastModified: "2024-07-02T00:00:00.000Z"},
+		{Key: "b.txt", VersionID: "v2", Size: 20, StorageClass: "COLD_INSTANT", LastModified: "2024-07-01T00:00:00.000Z", ETag: "def"},
+	}
+	if got := page.objects(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+}
+
+func TestListInventoryCurrentVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list-type") != "2" {
+			t.Errorf("expected a ListObjectsV2 request, got %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = io.WriteString(w, `<ListBucketResult><Name>bucket1</Name><IsTruncated>false</IsTruncated>
+  <Contents><Key>a.txt</Key><LastModified>2024-07-01T00:00:00.000Z</LastModified><ETag>"abc"</ETag><Size>10</Size><StorageClass>STANDARD</StorageClass></Contents>
+</ListBucketResult>`)
+	}))
+	defer server.Close()
+
+	cfg := Config{Region: "br-se1", NetworkConfig: config.NetworkConfig{ServerUrl: server.URL}}
+	var got []InventoryObject
+	err := ListInventory(newTestServerContext(t, server), cfg, mgcSchemaPkg.URI("s3://bucket1"), false, func(obj InventoryObject) error {
+		got = append(got, obj)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []InventoryObject{
+		{Key: "a.txt", IsLatest: true, Size: 10, StorageClass: "STANDARD", LastModified: "2024-07-01T00:00:00.000Z", ETag: "abc"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+}
+
+func TestUsageAccumulator(t *testing.T) {
+	now := time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC)
+	usage := NewUsageAccumulator("bucket1/logs", true, 1, now)
+
+	for _, obj := range []InventoryObject{
+		{Key: "logs/a.txt", IsLatest: true, Size: 1, LastModified: "2024-07-09T12:00:00Z"},
+		{Key: "logs/2024/01/b.txt", IsLatest: true, Size: 2, StorageClass: "COLD_INSTANT", LastModified: "2024-07-01T00:00:00Z"},
+		{Key: "logs/2024/02/c.txt", IsLatest: true, Size: 4, LastModified: "2023-01-01T00:00:00Z"},
+		{Key: "logs/2024/02/c.txt", Size: 8, LastModified: "2022-01-01T00:00:00Z"},
+		{Key: "logs/d.txt", IsLatest: true, IsDeleteMarker: true, LastModified: "2024-07-01T00:00:00Z"},
+	} {
+		usage.Add(obj)
+	}
+	usage.AddUpload(MultipartUpload{Key: "logs/e.txt", Initiated: "2024-07-05T00:00:00Z"})
+	usage.AddUpload(MultipartUpload{Key: "logs/f.txt", Initiated: "2024-07-03T00:00:00Z"})
+
+	expected := &UsageReport{
+		Bucket:     "bucket1",
+		Prefix:     "logs/",
+		Versioning: true,
+		Objects:    3,
+		Size:       7,
+		ByPrefix: []UsageGroup{
+			{Name: "(root)", Objects: 1, Size: 1},
+			{Name: "logs/2024/", Objects: 2, Size: 6},
+		},
+		ByStorageClass: []UsageGroup{
+			{Name: "COLD_INSTANT", Objects: 1, Size: 2},
+			{Name: "STANDARD", Objects: 2, Size: 5},
+		},
+		ByAge: []UsageGroup{
+			{Name: "< 1 day", Objects: 1, Size: 1},
+			{Name: "7-30 days", Objects: 1, Size: 2},
+			{Name: "> 365 days", Objects: 1, Size: 4},
+		},
+		NoncurrentVersions: UsageGroup{Name: "noncurrent", Objects: 1, Size: 8},
+		DeleteMarkers:      1,
+		IncompleteUploads:  IncompleteUploadsUsage{Count: 2, OldestInitiated: "2024-07-03T00:00:00Z"},
+	}
+	if got := usage.Report(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+}
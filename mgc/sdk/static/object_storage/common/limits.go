@@ -0,0 +1,110 @@
+package common
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
+)
+
+// Limits shared by all the transfers of the process, such as every file of upload-dir
+// and the parts of each of them, so the configured values are never exceeded when
+// operations run in parallel.
+type transferLimits struct {
+	// nil if unlimited
+	bandwidth *progress_report.TokenBucket
+	// nil if unlimited
+	inFlight chan struct{}
+}
+
+type transferLimitsKey struct {
+	maxBandwidth uint64
+	maxInFlight  int
+}
+
+var (
+	transferLimitsMutex sync.Mutex
+	transferLimitsByKey = map[transferLimitsKey]*transferLimits{}
+)
+
+func getTransferLimits(cfg Config) *transferLimits {
+	key := transferLimitsKey{maxBandwidth: cfg.MaxBandwidth, maxInFlight: max(cfg.MaxInFlight, 0)}
+
+	transferLimitsMutex.Lock()
+	defer transferLimitsMutex.Unlock()
+
+	if limits, ok := transferLimitsByKey[key]; ok {
+		return limits
+	}
+
+	limits := &transferLimits{}
+	if key.maxBandwidth > 0 {
+		limits.bandwidth = progress_report.NewTokenBucket(key.maxBandwidth)
+	}
+	if key.maxInFlight > 0 {
+		limits.inFlight = make(chan struct{}, key.maxInFlight)
+	}
+	transferLimitsByKey[key] = limits
+	return limits
+}
+
+func noopRelease() {}
+
+// Wait for a free in-flight request slot. The returned function must be called to release it
+func (l *transferLimits) acquire(ctx context.Context) (release func(), err error) {
+	if l.inFlight == nil {
+		return noopRelease, nil
+	}
+
+	select {
+	case l.inFlight <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-l.inFlight }) }, nil
+	case <-ctx.Done():
+		return noopRelease, ctx.Err()
+	}
+}
+
+type releasingReadCloser struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releasingReadCloser) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
+
+// The in-flight slot of a request is only released once its response body is closed
+func releaseOnClose(r io.ReadCloser, release func()) io.ReadCloser {
+	return &releasingReadCloser{ReadCloser: r, release: release}
+}
+
+func (l *transferLimits) limitReader(ctx context.Context, r io.ReadCloser) io.ReadCloser {
+	if l.bandwidth == nil {
+		return r
+	}
+	return progress_report.NewLimitedReader(ctx, r, l.bandwidth)
+}
+
+func (l *transferLimits) limitWriter(ctx context.Context, w io.Writer) io.Writer {
+	if l.bandwidth == nil {
+		return w
+	}
+	return progress_report.NewLimitedWriter(ctx, w, l.bandwidth)
+}
+
+// Request bodies are limited every time they're created, as they may be sent again
+func (l *transferLimits) limitNewReader(ctx context.Context, newReader func() (io.ReadCloser, error)) func() (io.ReadCloser, error) {
+	if l.bandwidth == nil || newReader == nil {
+		return newReader
+	}
+	return func() (io.ReadCloser, error) {
+		r, err := newReader()
+		if err != nil {
+			return nil, err
+		}
+		return l.limitReader(ctx, r), nil
+	}
+}
@@ -88,9 +88,11 @@ type paginationResponseInfo struct {
 
 type BucketContent struct {
 	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId" json:",omitempty"`
 	LastModified string `xml:"LastModified"`
 	ContentSize  int64  `xml:"Size"`
 	StorageClass string `xml:"StorageClass"`
+	ETag         string `xml:"ETag" json:",omitempty"`
 }
 
 type BucketContentDirEntry = *pipeline.SimpleWalkDirEntry[*BucketContent]
@@ -154,30 +156,8 @@ func newListRequest(ctx context.Context, cfg Config, bucketURI mgcSchemaPkg.URI,
 
 	var queryStringParts []string
 
-	if prefix := bucketURI.Path(); prefix != "" {
-		lastChar := string(prefix[len(prefix)-1])
-		if lastChar != delimiter {
-			prefix += delimiter
-		}
-
-		queryEscapedValue := url.QueryEscape(prefix)
-
-		// How for the "fun" part: the aws uri encoding scheme is not the same as go's.
-		//
-		// From the docs:
-		// URI encode every byte. UriEncode() must enforce the following rules:
-		//
-		//   - URI encode every byte except the unreserved characters: 'A'-'Z', 'a'-'z', '0'-'9', '-', '.', '_', and '~'.
-		//   - The space character is a reserved character and must be encoded as "%20" (and not as "+").
-		//   - Each URI encoded byte is formed by a '%' and the two-digit hexadecimal value of the byte.
-		//   - Letters in the hexadecimal value must be uppercase, for example "%1A".
-		//   - Encode the forward slash character, '/', everywhere except in the object key name. For example, if the object key name is photos/Jan/sample.jpg, the forward slash in the key name is not encoded.
-		//
-		// Source: https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html#example-signature-calculations
-		awsEscapedValue := strings.ReplaceAll(queryEscapedValue, "+", "%20")
-		awsEscapedValue = strings.ReplaceAll(awsEscapedValue, "*", "%2A")
-		awsEscapedValue = strings.ReplaceAll(awsEscapedValue, "%7E", "~")
-		queryStringParts = append(queryStringParts, "prefix="+awsEscapedValue)
+	if prefix := listPrefix(bucketURI); prefix != "" {
+		queryStringParts = append(queryStringParts, "prefix="+awsQueryEscape(prefix))
 	}
 
 	queryStringParts = append(queryStringParts, "list-type=2")
@@ -202,6 +182,35 @@ func newListRequest(ctx context.Context, cfg Config, bucketURI mgcSchemaPkg.URI,
 	return http.NewRequestWithContext(ctx, http.MethodGet, finalUrl.String(), nil)
 }
 
+// The prefix of bucketURI, always ending with the delimiter so only the contents of the
+// "directory" are listed
+func listPrefix(bucketURI mgcSchemaPkg.URI) string {
+	prefix := bucketURI.Path()
+	if prefix != "" && !strings.HasSuffix(prefix, delimiter) {
+		prefix += delimiter
+	}
+	return prefix
+}
+
+// Escapes query values for sigv4, as the aws uri encoding scheme is not the same as go's.
+//
+// From the docs:
+// URI encode every byte. UriEncode() must enforce the following rules:
+//
+//   - URI encode every byte except the unreserved characters: 'A'-'Z', 'a'-'z', '0'-'9', '-', '.', '_', and '~'.
+//   - The space character is a reserved character and must be encoded as "%20" (and not as "+").
+//   - Each URI encoded byte is formed by a '%' and the two-digit hexadecimal value of the byte.
+//   - Letters in the hexadecimal value must be uppercase, for example "%1A".
+//   - Encode the forward slash character, '/', everywhere except in the object key name. For example, if the object key name is photos/Jan/sample.jpg, the forward slash in the key name is not encoded.
+//
+// Source: https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html#example-signature-calculations
+func awsQueryEscape(value string) string {
+	awsEscapedValue := strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	awsEscapedValue = strings.ReplaceAll(awsEscapedValue, "*", "%2A")
+	awsEscapedValue = strings.ReplaceAll(awsEscapedValue, "%7E", "~")
+	return awsEscapedValue
+}
+
 func buildListRequestURL(cfg Config, bucketURI mgcSchemaPkg.URI) (*url.URL, error) {
 	u, err := BuildBucketHostURL(cfg, NewBucketNameFromURI(bucketURI))
 	if err != nil {
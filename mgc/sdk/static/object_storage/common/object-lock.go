@@ -58,3 +58,24 @@ var DefaultObjectLockingBody = ObjectLockingBody{
 		},
 	},
 }
+
+type ObjectLegalHoldStatus string
+
+const (
+	ObjectLegalHoldStatusOn  = ObjectLegalHoldStatus("ON")
+	ObjectLegalHoldStatusOff = ObjectLegalHoldStatus("OFF")
+)
+
+// Object legal hold [Object]
+type ObjectLegalHold struct {
+	XMLName   xml.Name `xml:"LegalHold"`
+	Namespace string   `xml:"xmlns,attr,omitempty"`
+	Status    ObjectLegalHoldStatus
+}
+
+func NewObjectLegalHoldBody(status ObjectLegalHoldStatus) ObjectLegalHold {
+	return ObjectLegalHold{
+		Namespace: namespace,
+		Status:    status,
+	}
+}
@@ -0,0 +1,136 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+)
+
+const postPolicyExpirationFormat = "2006-01-02T15:04:05.000Z"
+
+// Constraints of a browser form upload, enforced by the server through the signed policy.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
+type PostPolicyConditions struct {
+	// Exact key of the object, or the prefix the key must start with when ending in "/"
+	Key string
+	// Content length range in bytes. Not checked when ContentLengthMax is 0
+	ContentLengthMin int64
+	ContentLengthMax int64
+	// Exact Content-Type, or the prefix it must start with when ending in "/"
+	ContentType string
+}
+
+type PresignedPost struct {
+	URL        string            `json:"url"`
+	Fields     map[string]string `json:"fields"`
+	Expiration string            `json:"expiration"`
+}
+
+type postPolicy struct {
+	Expiration string `json:"expiration"`
+	Conditions []any  `json:"conditions"`
+}
+
+// A condition either matches exactly or, ending in "/", checks the prefix. An empty prefix
+// allows any value
+func postPolicyMatchCondition(field, value string) any {
+	if value == "" || strings.HasSuffix(value, delimiter) {
+		return []any{"starts-with", "$" + field, value}
+	}
+	return map[string]string{field: value}
+}
+
+func (c PostPolicyConditions) validate() error {
+	if c.ContentLengthMin < 0 || c.ContentLengthMax < 0 {
+		return fmt.Errorf("content length range must not be negative")
+	}
+	if c.ContentLengthMax > 0 && c.ContentLengthMin > c.ContentLengthMax {
+		return fmt.Errorf("content length min %d is greater than max %d", c.ContentLengthMin, c.ContentLengthMax)
+	}
+	return nil
+}
+
+func buildPostPolicy(bucketName BucketName, params SignatureParameters, expiration time.Time, c PostPolicyConditions) postPolicy {
+	conditions := []any{
+		map[string]string{"bucket": bucketName.String()},
+		postPolicyMatchCondition("key", c.Key),
+	}
+	if c.ContentLengthMax > 0 {
+		conditions = append(conditions, []any{"content-length-range", c.ContentLengthMin, c.ContentLengthMax})
+	}
+	if c.ContentType != "" {
+		conditions = append(conditions, postPolicyMatchCondition("Content-Type", c.ContentType))
+	}
+	conditions = append(conditions,
+		map[string]string{"x-amz-algorithm": params.Algorithm},
+		map[string]string{"x-amz-credential": params.Credential},
+		map[string]string{"x-amz-date": params.Date},
+	)
+
+	return postPolicy{
+		Expiration: expiration.UTC().Format(postPolicyExpirationFormat),
+		Conditions: conditions,
+	}
+}
+
+// The base64 encoded policy is the string to sign of POST uploads, with the same key derivation
+// used to sign requests
+func signPostPolicy(encodedPolicy, secretKey, shortDate, region string) string {
+	signKey := deriveKey(secretKey, shortDate, region)
+	return hex.EncodeToString(core.HMACSHA256String(signKey, encodedPolicy))
+}
+
+// Builds the URL and form fields of a browser upload to the bucket, valid from signingTime
+// for expiresIn. The form must have the fields before the "file" one, as S3 requires
+func NewPresignedPost(
+	bucketURL *url.URL,
+	bucketName BucketName,
+	accessKey, secretKey, region string,
+	signingTime time.Time,
+	expiresIn time.Duration,
+	conditions PostPolicyConditions,
+) (*PresignedPost, error) {
+	if err := conditions.validate(); err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	signingTime = signingTime.UTC()
+	params := NewSignatureParameters(accessKey, signingTime, "", nil, region)
+	expiration := signingTime.Add(expiresIn)
+
+	policyJSON, err := json.Marshal(buildPostPolicy(bucketName, params, expiration, conditions))
+	if err != nil {
+		return nil, err
+	}
+	encodedPolicy := base64.StdEncoding.EncodeToString(policyJSON)
+
+	key := conditions.Key
+	if key == "" || strings.HasSuffix(key, delimiter) {
+		// replaced by the server with the name of the uploaded file
+		key += "${filename}"
+	}
+
+	fields := map[string]string{
+		"key":              key,
+		"policy":           encodedPolicy,
+		"x-amz-algorithm":  params.Algorithm,
+		"x-amz-credential": params.Credential,
+		"x-amz-date":       params.Date,
+		"x-amz-signature":  signPostPolicy(encodedPolicy, secretKey, params.ShortDate, region),
+	}
+	if conditions.ContentType != "" && !strings.HasSuffix(conditions.ContentType, delimiter) {
+		fields["Content-Type"] = conditions.ContentType
+	}
+
+	return &PresignedPost{
+		URL:        bucketURL.String(),
+		Fields:     fields,
+		Expiration: expiration.Format(time.RFC3339),
+	}, nil
+}
@@ -8,8 +8,10 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/MagaluCloud/magalu/mgc/core"
 	"github.com/MagaluCloud/magalu/mgc/core/auth"
 	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	"github.com/MagaluCloud/magalu/mgc/core/telemetry"
 )
 
 // note: must be in HTTP Header Canonical format (Title-Case-With-Dashes)
@@ -98,7 +100,30 @@ func BuildBucketHostWithPathURL(cfg Config, bucketName BucketName, path string)
 	return url.Parse(string(bucketHost))
 }
 
+// Name of the bucket the request is sent to, empty for requests to the service root
+func requestBucket(req *http.Request, cfg Config) string {
+	hostURL, err := BuildHostURL(cfg)
+	if err != nil {
+		return ""
+	}
+	path, ok := strings.CutPrefix(req.URL.EscapedPath(), hostURL.EscapedPath())
+	if !ok {
+		return ""
+	}
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if unescaped, err := url.PathUnescape(bucket); err == nil {
+		bucket = unescaped
+	}
+	return bucket
+}
+
 func SendRequestWithIgnoredHeaders(ctx context.Context, req *http.Request, cfg Config, ignoredHeaders map[string]struct{}) (res *http.Response, err error) {
+	return sendRequest(ctx, req, cfg, ignoredHeaders, false)
+}
+
+// Requests sent while another one holds their in-flight slot, like the source of a
+// streamed part being read as its body, are unlimited or they would wait for themselves
+func sendRequest(ctx context.Context, req *http.Request, cfg Config, ignoredHeaders map[string]struct{}, unlimited bool) (res *http.Response, err error) {
 	httpClient := mgcHttpPkg.ClientFromContext(ctx)
 	if httpClient == nil {
 		err = fmt.Errorf("couldn't get http client from context")
@@ -110,7 +135,11 @@ func SendRequestWithIgnoredHeaders(ctx context.Context, req *http.Request, cfg C
 		unsignedPayload = true
 	}
 
-	accesskeyId, accessSecretKey := auth.FromContext(ctx).AccessKeyPair()
+	accesskeyId, accessSecretKey, err := auth.FromContext(ctx).AccessKeyPairFor(cfg.KeyPair, requestBucket(req, cfg))
+	if err != nil {
+		err = core.UsageError{Err: err}
+		return
+	}
 	if accesskeyId == "" || accessSecretKey == "" {
 		err = fmt.Errorf("api-key not set, see how to set it with \"mgc object-storage api-key -h\"")
 		return
@@ -120,11 +149,24 @@ func SendRequestWithIgnoredHeaders(ctx context.Context, req *http.Request, cfg C
 		return
 	}
 
+	release := noopRelease
+	if !unlimited {
+		// The body of the response is still being received, so the slot is held until it's closed
+		release, err = getTransferLimits(cfg).acquire(ctx)
+		if err != nil {
+			return
+		}
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = telemetry.CountObjectStorageBytes(ctx, req.Body, telemetry.DirectionUpload)
+	}
 	res, err = httpClient.Do(req)
 	if err != nil {
+		release()
 		err = fmt.Errorf("error to send HTTP request: %w", err)
 		return
 	}
+	res.Body = releaseOnClose(telemetry.CountObjectStorageBytes(ctx, res.Body, telemetry.DirectionDownload), release)
 
 	return
 }
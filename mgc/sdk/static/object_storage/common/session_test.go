@@ -0,0 +1,87 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core/auth"
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	"github.com/MagaluCloud/magalu/mgc/core/profile_manager"
+)
+
+func TestRequestBucket(t *testing.T) {
+	tests := []struct {
+		serverUrl string
+		url       string
+		expected  string
+	}{
+		{"", "https://br-se1.magaluobjects.com/bucket-b/dir/file.txt", "bucket-b"},
+		{"", "https://br-se1.magaluobjects.com/bucket-b?versioning=", "bucket-b"},
+		{"", "https://br-se1.magaluobjects.com/", ""},
+		{"http://localhost:8080/s3", "http://localhost:8080/s3/bucket-b/file.txt", "bucket-b"},
+		{"http://localhost:8080/s3", "http://localhost:8080/other/bucket-b", ""},
+		{"http://localhost:8080", "http://localhost:8080/my%20bucket/file.txt", "my bucket"},
+	}
+
+	for _, tt := range tests {
+		cfg := Config{Region: "br-se1", NetworkConfig: config.NetworkConfig{ServerUrl: tt.serverUrl}}
+		req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := requestBucket(req, cfg); got != tt.expected {
+			t.Errorf("requestBucket(%q) = %q, want %q", tt.url, got, tt.expected)
+		}
+	}
+}
+
+// Context to send signed requests to the test server
+func newTestServerContext(t *testing.T, server *httptest.Server) context.Context {
+	m, _ := profile_manager.NewInMemoryProfileManager()
+	a := auth.New(map[string]auth.Config{}, server.Client(), m, config.New(m))
+	if err := a.SetAccessKey("KEY", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := auth.NewContext(context.Background(), a)
+	return mgcHttpPkg.NewClientContext(ctx, mgcHttpPkg.NewClient(server.Client().Transport))
+}
+
+func TestSendRequestHoldsSlotUntilBodyClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "content")
+	}))
+	defer server.Close()
+
+	ctx := newTestServerContext(t, server)
+
+	cfg := Config{MaxInFlight: 1, Region: "br-se1", NetworkConfig: config.NetworkConfig{ServerUrl: server.URL}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/bucket/file.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := SendRequest(ctx, req, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if release, err := getTransferLimits(cfg).acquire(waitCtx); err == nil {
+		release()
+		t.Fatal("expected the slot to be held while the body is open")
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	release, err := getTransferLimits(cfg).acquire(ctx)
+	if err != nil {
+		t.Fatalf("expected the slot to be released once the body is closed: %v", err)
+	}
+	release()
+}
@@ -30,6 +30,7 @@ func (u *smallFileCopier) Copy(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	return ExtractErr(resp, req)
 }
@@ -40,7 +40,8 @@ func (u *smallFileDownloader) Download(ctx context.Context) error {
 	progressReporter.Start()
 	defer progressReporter.End()
 
-	resp.Body = progress_report.NewReporterReader(resp.Body, progressReporter.Report)
+	limits := getTransferLimits(u.cfg)
+	resp.Body = limits.limitReader(ctx, progress_report.NewReporterReader(resp.Body, progressReporter.Report))
 
 	dir := path.Dir(u.dst.String())
 	if len(dir) != 0 {
@@ -49,6 +49,7 @@ func (u *smallFileUploader) Upload(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	err = ExtractErr(resp, req)
 	return err
@@ -0,0 +1,215 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/MagaluCloud/magalu/mgc/core/auth"
+	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	"github.com/MagaluCloud/magalu/mgc/core/pipeline"
+	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+)
+
+// Copies by downloading the source and uploading it to the destination, each request
+// signed with the key pair of its own bucket. Used when the key pair of the destination
+// can't read the source, like buckets of different tenants. The upload preparation and
+// completion are the same as the multipart copy
+type streamCopier struct {
+	bigFileCopier
+	contentType string
+}
+
+var _ copier = (*streamCopier)(nil)
+
+// The Content-MD5 would need the source downloaded twice, once to compute it
+var streamCopierExcludedHeaders = bigFileCopierExcludedHeaders
+
+func newStreamCopier(cfg Config, src mgcSchemaPkg.URI, dst mgcSchemaPkg.URI, metadata HeadObjectResponse, version string, storageClass string) *streamCopier {
+	return &streamCopier{
+		bigFileCopier: bigFileCopier{
+			cfg:          cfg,
+			src:          src,
+			dst:          dst,
+			fileSize:     metadata.ContentLength,
+			totalParts:   int(math.Ceil(float64(metadata.ContentLength) / float64(cfg.chunkSizeInBytes()))),
+			version:      version,
+			storageClass: storageClass,
+		},
+		contentType: metadata.ContentType,
+	}
+}
+
+// Each call downloads the range again, so the upload can be retried. The download is
+// part of the upload, so it uses the in-flight slot of the upload instead of its own
+func (u *streamCopier) newSourceReader(ctx context.Context, startOffset int64, endOffset int64) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		req, err := NewDownloadRequest(ctx, u.cfg, u.src, u.version)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", startOffset, endOffset))
+
+		resp, err := sendRequest(ctx, req, u.cfg, excludedHeaders, true)
+		if err != nil {
+			return nil, err
+		}
+		if err = ExtractErr(resp, req); err != nil {
+			return nil, err
+		}
+		return resp.Body, nil
+	}
+}
+
+func (u *streamCopier) newPartRequest(ctx context.Context, uploadId string, partNumber int, startOffset int64, endOffset int64) (*http.Request, error) {
+	req, err := newUploadRequest(ctx, u.cfg, u.dst, u.newSourceReader(ctx, startOffset, endOffset))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = endOffset - startOffset + 1
+
+	if uploadId != "" {
+		q := req.URL.Query()
+		q.Set("uploadId", uploadId)
+		q.Set("partNumber", fmt.Sprint(partNumber))
+		req.URL.RawQuery = q.Encode()
+	}
+	return req, nil
+}
+
+func (u *streamCopier) copySingle(ctx context.Context) error {
+	var req *http.Request
+	var err error
+	if u.fileSize == 0 {
+		req, err = newUploadRequest(ctx, u.cfg, u.dst, nil)
+	} else {
+		req, err = u.newPartRequest(ctx, "", 0, 0, u.fileSize-1)
+	}
+	if err != nil {
+		return err
+	}
+
+	if u.contentType != "" {
+		req.Header.Set("Content-Type", u.contentType)
+	}
+	if u.storageClass != "" {
+		req.Header.Set("X-Amz-Storage-Class", u.storageClass)
+	}
+
+	resp, err := SendRequestWithIgnoredHeaders(ctx, req, u.cfg, streamCopierExcludedHeaders)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return ExtractErr(resp, req)
+}
+
+func (u *streamCopier) createPartStreamProcessor(cancel context.CancelCauseFunc, uploadId string) pipeline.Processor[pipeline.WriteableChunk, completionPart] {
+	return func(ctx context.Context, chunk pipeline.WriteableChunk) (part completionPart, status pipeline.ProcessStatus) {
+		var err error
+		defer func() { u.progressReporter.Report(0, err) }()
+
+		partNumber := int(chunk.StartOffset/int64(u.cfg.chunkSizeInBytes())) + 1
+		req, err := u.newPartRequest(ctx, uploadId, partNumber, chunk.StartOffset, chunk.EndOffset)
+		if err != nil {
+			cancel(err)
+			return part, pipeline.ProcessAbort
+		}
+
+		bigfileUploaderLogger().Debugw("Streaming part", "part", partNumber, "total", u.totalParts)
+		res, err := SendRequestWithIgnoredHeaders(ctx, req, u.cfg, streamCopierExcludedHeaders)
+		if err != nil {
+			cancel(err)
+			return part, pipeline.ProcessAbort
+		}
+		defer res.Body.Close()
+
+		err = ExtractErr(res, req)
+		if err != nil {
+			cancel(err)
+			return part, pipeline.ProcessAbort
+		}
+
+		u.progressReporter.Report(uint64(chunk.EndOffset-chunk.StartOffset+1), nil)
+		return NewCompletionPart(partNumber, res.Header.Get("etag")), pipeline.ProcessOutput
+	}
+}
+
+func (u *streamCopier) Copy(ctx context.Context) (err error) {
+	if u.totalParts <= 1 {
+		return u.copySingle(ctx)
+	}
+
+	name := "Streaming " + u.src.String()
+	u.progressReporter = progress_report.NewBytesReporter(ctx, name, uint64(u.fileSize))
+	u.progressReporter.Start()
+	defer u.progressReporter.End()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	uploadId, err := u.getUploadId(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			u.abortUpload(context.WithoutCancel(ctx), uploadId)
+		}
+	}()
+
+	chunkChan := pipeline.PrepareWriteChunks(ctx, nil, u.fileSize, int64(u.cfg.chunkSizeInBytes()))
+	partChan := pipeline.ParallelProcess(ctx, u.cfg.Workers, chunkChan, u.createPartStreamProcessor(cancel, uploadId), nil)
+
+	parts, err := pipeline.SliceItemConsumer[[]completionPart](ctx, partChan)
+	if err != nil {
+		return err
+	}
+
+	return u.sendCompletionRequest(ctx, parts, uploadId)
+}
+
+// Tries the server-side copy, then streams the object if the key pair of the
+// destination isn't allowed to read the source. The multipart upload of a denied
+// server-side copy is aborted by it, before streaming
+type fallbackCopier struct {
+	copier
+	stream *streamCopier
+}
+
+func (c *fallbackCopier) Copy(ctx context.Context) error {
+	err := c.copier.Copy(ctx)
+	if !isAccessDenied(err) {
+		return err
+	}
+
+	copyAllLogger().Infow("Server-side copy denied, streaming the object", "src", c.stream.src, "dst", c.stream.dst, "error", err)
+	return c.stream.Copy(ctx)
+}
+
+func isAccessDenied(err error) bool {
+	var httpErr *mgcHttpPkg.HttpError
+	return errors.As(err, &httpErr) && httpErr.Code == http.StatusForbidden
+}
+
+// Whether the requests to the buckets are signed with different key pairs, then a
+// server-side copy between them may not be allowed
+func usesDifferentKeyPairs(ctx context.Context, cfg Config, src BucketName, dst BucketName) bool {
+	a := auth.FromContext(ctx)
+	if a == nil {
+		return false
+	}
+
+	srcKeyId, _, err := a.AccessKeyPairFor(cfg.KeyPair, src.String())
+	if err != nil {
+		return false
+	}
+	dstKeyId, _, err := a.AccessKeyPairFor(cfg.KeyPair, dst.String())
+	if err != nil {
+		return false
+	}
+	return srcKeyId != dstKeyId
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+)
+
+func TestFallbackCopierAbortsDeniedUpload(t *testing.T) {
+	var mutex sync.Mutex
+	var requests []string
+	var uploaded string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			requests = append(requests, "create upload")
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = io.WriteString(w, "<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>")
+		case r.Method == http.MethodPut && r.Header.Get("x-amz-copy-source") != "":
+			requests = append(requests, "copy part")
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+		case r.Method == http.MethodDelete:
+			requests = append(requests, "abort "+q.Get("uploadId"))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet:
+			requests = append(requests, "download "+r.URL.Path)
+			_, _ = io.WriteString(w, "content")
+		case r.Method == http.MethodPut:
+			requests = append(requests, "upload "+r.URL.Path)
+			data, _ := io.ReadAll(r.Body)
+			uploaded = string(data)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer server.Close()
+
+	ctx := newTestServerContext(t, server)
+
+	cfg := Config{Workers: 1, MaxInFlight: 1, Region: "br-se1", NetworkConfig: config.NetworkConfig{ServerUrl: server.URL}}
+	src := mgcSchemaPkg.URI("s3://src/a.txt")
+	dst := mgcSchemaPkg.URI("s3://dst/a.txt")
+	metadata := HeadObjectResponse{ContentLength: int64(len("content"))}
+
+	c := &fallbackCopier{
+		copier: &bigFileCopier{cfg: cfg, src: src, dst: dst, fileSize: metadata.ContentLength, totalParts: 2},
+		stream: newStreamCopier(cfg, src, dst, metadata, "", ""),
+	}
+	if err := c.Copy(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"create upload", "copy part", "abort upload-1", "download /src/a.txt", "upload /dst/a.txt"}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+	if uploaded != "content" {
+		t.Errorf("expected the source content to be uploaded, got %q", uploaded)
+	}
+}
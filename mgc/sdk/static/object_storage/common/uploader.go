@@ -63,6 +63,7 @@ func newUploadRequest(ctx context.Context, cfg Config, dst mgcSchemaPkg.URI, new
 
 	var body io.ReadCloser
 
+	newReader = getTransferLimits(cfg).limitNewReader(ctx, newReader)
 	if newReader != nil {
 		body, err = newReader()
 		if err != nil {
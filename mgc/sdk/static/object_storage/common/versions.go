@@ -0,0 +1,81 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parses durations such as "30d" or "12h". Days are accepted on top of time.ParseDuration units,
+// as version retention is usually counted in days
+func ParseAge(age string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q, expected a number of days such as 30d", age)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(age)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q, expected a duration such as 30d or 12h", age)
+	}
+	return d, nil
+}
+
+// Chooses which versions of objects, as listed by ListInventory with versions, are to be
+// purged: the noncurrent versions after the newest keep ones of each key and the noncurrent
+// delete markers. A delete marker that's the current version is only purged when nothing else
+// is left of its key. With olderThan > 0, only versions last modified before that are purged.
+//
+// Objects must be sorted by key and then newest first, as ListInventory does.
+func PlanPurgeVersions(objects []InventoryObject, keep int, olderThan time.Duration, now time.Time) []InventoryObject {
+	isOld := func(obj InventoryObject) bool {
+		if olderThan <= 0 {
+			return true
+		}
+		modTime, err := time.Parse(time.RFC3339, obj.LastModified)
+		return err == nil && !modTime.After(now.Add(-olderThan))
+	}
+
+	var plan []InventoryObject
+	for start := 0; start < len(objects); {
+		end := start + 1
+		for end < len(objects) && objects[end].Key == objects[start].Key {
+			end++
+		}
+
+		var currentMarker *InventoryObject
+		noncurrent, remaining := 0, 0
+		for i := start; i < end; i++ {
+			obj := objects[i]
+			switch {
+			case obj.IsLatest && obj.IsDeleteMarker:
+				currentMarker = &objects[i]
+			case obj.IsLatest:
+				remaining++
+			case obj.IsDeleteMarker:
+				if isOld(obj) {
+					plan = append(plan, obj)
+				} else {
+					remaining++
+				}
+			default:
+				noncurrent++
+				if noncurrent > keep && isOld(obj) {
+					plan = append(plan, obj)
+				} else {
+					remaining++
+				}
+			}
+		}
+
+		if currentMarker != nil && remaining == 0 && isOld(*currentMarker) {
+			plan = append(plan, *currentMarker)
+		}
+		start = end
+	}
+	return plan
+}
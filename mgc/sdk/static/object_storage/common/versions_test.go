@@ -0,0 +1,75 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age      string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"12h", 12 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"-1d", 0, true},
+		{"30", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAge(tt.age)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAge(%q): unexpected error %v", tt.age, err)
+		} else if got != tt.expected {
+			t.Errorf("ParseAge(%q) = %s, want %s", tt.age, got, tt.expected)
+		}
+	}
+}
+
+func TestPlanPurgeVersions(t *testing.T) {
+	now := time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)
+	objects := []InventoryObject{
+		{Key: "a.txt", VersionID: "a4", IsLatest: true, LastModified: "2024-07-30T00:00:00Z"},
+		{Key: "a.txt", VersionID: "a3", IsDeleteMarker: true, LastModified: "2024-07-20T00:00:00Z"},
+		{Key: "a.txt", VersionID: "a2", LastModified: "2024-07-10T00:00:00Z"},
+		{Key: "a.txt", VersionID: "a1", LastModified: "2024-06-01T00:00:00Z"},
+		{Key: "b.txt", VersionID: "b2", IsLatest: true, IsDeleteMarker: true, LastModified: "2024-05-01T00:00:00Z"},
+		{Key: "b.txt", VersionID: "b1", LastModified: "2024-04-01T00:00:00Z"},
+		{Key: "c.txt", VersionID: "c2", IsLatest: true, IsDeleteMarker: true, LastModified: "2024-07-30T00:00:00Z"},
+		{Key: "c.txt", VersionID: "c1", LastModified: "2024-04-01T00:00:00Z"},
+	}
+
+	versionIDs := func(plan []InventoryObject) []string {
+		ids := []string{}
+		for _, obj := range plan {
+			ids = append(ids, obj.VersionID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name      string
+		keep      int
+		olderThan time.Duration
+		expected  []string
+	}{
+		{"all noncurrent", 0, 0, []string{"a3", "a2", "a1", "b1", "b2", "c1", "c2"}},
+		{"keep one", 1, 0, []string{"a3", "a1"}},
+		{"older than 30 days", 0, 30 * 24 * time.Hour, []string{"a1", "b1", "b2", "c1"}},
+		{"keep one older than 15 days", 1, 15 * 24 * time.Hour, []string{"a1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := versionIDs(PlanPurgeVersions(objects, tt.keep, tt.olderThan, now))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/MagaluCloud/magalu/mgc/core/utils"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/api_key"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/buckets"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/key_pairs"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/objects"
 )
 
@@ -17,9 +18,10 @@ var GetGroup = utils.NewLazyLoader(func() core.Grouper {
 		},
 		func() []core.Descriptor {
 			return []core.Descriptor{
-				buckets.GetGroup(), // object-storage buckets
-				objects.GetGroup(), // object-storage objects
-				api_key.GetGroup(), // object-storage api-keys
+				buckets.GetGroup(),   // object-storage buckets
+				objects.GetGroup(),   // object-storage objects
+				api_key.GetGroup(),   // object-storage api-keys
+				key_pairs.GetGroup(), // object-storage key-pairs
 			}
 		},
 	)
@@ -0,0 +1,49 @@
+package key_pairs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcAuthPkg "github.com/MagaluCloud/magalu/mgc/core/auth"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type addParams struct {
+	Name          string `json:"name" jsonschema_description:"Name to refer to the key pair" mgc:"positional"`
+	KeyPairID     string `json:"keyId" jsonschema_description:"ID of the api key" mgc:"positional"`
+	KeyPairSecret string `json:"keySecret" jsonschema_description:"Secret of the api key" mgc:"positional"`
+}
+
+var getAdd = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	executor := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "add",
+			Summary:     "Add or replace a named key pair",
+			Description: "Store the key pair under the name in the current workspace. The default key pair is not changed",
+		},
+		add,
+	)
+
+	return core.NewExecuteResultOutputOptions(executor, func(exec core.Executor, result core.Result) string {
+		return "template=Key pair {{.name}} added\n"
+	})
+})
+
+func add(ctx context.Context, params addParams, _ struct{}) (*keyPairResult, error) {
+	auth := mgcAuthPkg.FromContext(ctx)
+	if auth == nil {
+		return nil, fmt.Errorf("programming error: could not get auth configuration from context")
+	}
+
+	if params.Name == "" || params.KeyPairID == "" || params.KeyPairSecret == "" {
+		return nil, core.UsageError{Err: fmt.Errorf("name, key ID and key secret are required")}
+	}
+
+	pair := mgcAuthPkg.KeyPair{AccessKeyId: params.KeyPairID, SecretAccessKey: params.KeyPairSecret}
+	if err := auth.SetKeyPair(params.Name, pair); err != nil {
+		return nil, err
+	}
+
+	return &keyPairResult{Name: params.Name, AccessKeyId: params.KeyPairID}, nil
+}
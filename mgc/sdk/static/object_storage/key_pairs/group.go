@@ -0,0 +1,45 @@
+package key_pairs
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{
+			Name:    "key-pairs",
+			Summary: "Manage named Object Storage key pairs and the buckets using them",
+			Description: `Key pairs are stored in the current workspace under a name. Requests to a bucket
+set to a key pair are signed with it, other requests use the default key pair
+from "mgc object-storage api-key". The --key-pair flag selects a key pair for
+all the requests of a command.
+
+This allows working with buckets owned by different tenants:
+
+    mgc object-storage key-pairs add tenant-b <key-id> <key-secret>
+    mgc object-storage key-pairs set-bucket bucket-b tenant-b
+    mgc object-storage objects copy bucket-a/file.txt bucket-b/file.txt`,
+		},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				getAdd(),
+				getList(),
+				getRemove(),
+				getSetBucket(),
+				getUnsetBucket(),
+			}
+		},
+	)
+})
+
+type keyPairResult struct {
+	Name        string   `json:"name"`
+	AccessKeyId string   `json:"access_key_id"`
+	Buckets     []string `json:"buckets,omitempty"`
+}
+
+type bucketKeyPairResult struct {
+	Bucket  string `json:"bucket"`
+	KeyPair string `json:"key_pair,omitempty"`
+}
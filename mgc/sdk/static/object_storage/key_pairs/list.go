@@ -0,0 +1,54 @@
+package key_pairs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcAuthPkg "github.com/MagaluCloud/magalu/mgc/core/auth"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var getList = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	var exec core.Executor = core.NewStaticExecuteSimple(
+		core.DescriptorSpec{
+			Name:        "list",
+			Summary:     "List the named key pairs and their buckets",
+			Description: "List the key pairs of the current workspace. Secrets are not shown",
+		},
+		list,
+	)
+
+	exec = core.NewHumanIdentifiableFieldsExecutor(exec, []string{"name"})
+
+	return exec
+})
+
+func list(ctx context.Context) ([]*keyPairResult, error) {
+	auth := mgcAuthPkg.FromContext(ctx)
+	if auth == nil {
+		return nil, fmt.Errorf("programming error: could not get auth configuration from context")
+	}
+
+	result := []*keyPairResult{}
+	for name, pair := range auth.KeyPairs() {
+		result = append(result, &keyPairResult{Name: name, AccessKeyId: pair.AccessKeyId})
+	}
+	slices.SortFunc(result, func(a, b *keyPairResult) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for bucket, name := range auth.BucketKeyPairs() {
+		i := slices.IndexFunc(result, func(r *keyPairResult) bool { return r.Name == name })
+		if i >= 0 {
+			result[i].Buckets = append(result[i].Buckets, bucket)
+		}
+	}
+	for _, r := range result {
+		slices.Sort(r.Buckets)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package key_pairs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcAuthPkg "github.com/MagaluCloud/magalu/mgc/core/auth"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type removeParams struct {
+	Name string `json:"name" jsonschema_description:"Name of the key pair" mgc:"positional"`
+}
+
+var getRemove = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	var exec core.Executor = core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "remove",
+			Summary:     "Remove a named key pair",
+			Description: "Remove the key pair from the current workspace. The buckets set to it go back to the default key pair",
+		},
+		remove,
+	)
+
+	msg := "This operation will remove the key pair {{.parameters.name}} and unset it from its buckets. Do you wish to continue?"
+
+	cExecutor := core.NewConfirmableExecutor(
+		exec,
+		core.ConfirmPromptWithTemplate(msg),
+	)
+
+	return core.NewExecuteResultOutputOptions(cExecutor, func(exec core.Executor, result core.Result) string {
+		return "template=Key pair {{.name}} removed\n"
+	})
+})
+
+func remove(ctx context.Context, params removeParams, _ struct{}) (*keyPairResult, error) {
+	auth := mgcAuthPkg.FromContext(ctx)
+	if auth == nil {
+		return nil, fmt.Errorf("programming error: could not get auth configuration from context")
+	}
+
+	pair, ok := auth.KeyPairs()[params.Name]
+	if !ok {
+		return nil, core.UsageError{Err: fmt.Errorf("key pair %q not found", params.Name)}
+	}
+	if err := auth.UnsetKeyPair(params.Name); err != nil {
+		return nil, err
+	}
+
+	return &keyPairResult{Name: params.Name, AccessKeyId: pair.AccessKeyId}, nil
+}
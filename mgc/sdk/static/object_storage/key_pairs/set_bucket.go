@@ -0,0 +1,47 @@
+package key_pairs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcAuthPkg "github.com/MagaluCloud/magalu/mgc/core/auth"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+type setBucketParams struct {
+	Bucket  common.BucketName `json:"bucket" jsonschema:"description=Name of the bucket,example=my-bucket" mgc:"positional"`
+	KeyPair string            `json:"keyPairName" jsonschema_description:"Name of the key pair to use for the bucket" mgc:"positional"`
+}
+
+var getSetBucket = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	executor := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "set-bucket",
+			Summary:     "Use a named key pair for the requests to a bucket",
+			Description: "Requests to the bucket are signed with the key pair, unless --key-pair is given",
+		},
+		setBucket,
+	)
+
+	return core.NewExecuteResultOutputOptions(executor, func(exec core.Executor, result core.Result) string {
+		return "template=Bucket {{.bucket}} uses key pair {{.key_pair}}\n"
+	})
+})
+
+func setBucket(ctx context.Context, params setBucketParams, _ struct{}) (*bucketKeyPairResult, error) {
+	auth := mgcAuthPkg.FromContext(ctx)
+	if auth == nil {
+		return nil, fmt.Errorf("programming error: could not get auth configuration from context")
+	}
+
+	if _, ok := auth.KeyPairs()[params.KeyPair]; !ok {
+		return nil, core.UsageError{Err: fmt.Errorf("key pair %q not found, add it with \"mgc object-storage key-pairs add\"", params.KeyPair)}
+	}
+	if err := auth.SetBucketKeyPair(params.Bucket.String(), params.KeyPair); err != nil {
+		return nil, err
+	}
+
+	return &bucketKeyPairResult{Bucket: params.Bucket.String(), KeyPair: params.KeyPair}, nil
+}
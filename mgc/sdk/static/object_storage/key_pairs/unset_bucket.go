@@ -0,0 +1,46 @@
+package key_pairs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcAuthPkg "github.com/MagaluCloud/magalu/mgc/core/auth"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+type unsetBucketParams struct {
+	Bucket common.BucketName `json:"bucket" jsonschema:"description=Name of the bucket,example=my-bucket" mgc:"positional"`
+}
+
+var getUnsetBucket = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	executor := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "unset-bucket",
+			Summary:     "Go back to the default key pair for the requests to a bucket",
+			Description: "The named key pair itself is kept",
+		},
+		unsetBucket,
+	)
+
+	return core.NewExecuteResultOutputOptions(executor, func(exec core.Executor, result core.Result) string {
+		return "template=Bucket {{.bucket}} uses the default key pair\n"
+	})
+})
+
+func unsetBucket(ctx context.Context, params unsetBucketParams, _ struct{}) (*bucketKeyPairResult, error) {
+	auth := mgcAuthPkg.FromContext(ctx)
+	if auth == nil {
+		return nil, fmt.Errorf("programming error: could not get auth configuration from context")
+	}
+
+	if _, ok := auth.BucketKeyPairs()[params.Bucket.String()]; !ok {
+		return nil, core.UsageError{Err: fmt.Errorf("bucket %q has no key pair set", params.Bucket)}
+	}
+	if err := auth.UnsetBucketKeyPair(params.Bucket.String()); err != nil {
+		return nil, err
+	}
+
+	return &bucketKeyPairResult{Bucket: params.Bucket.String()}, nil
+}
@@ -49,6 +49,7 @@ func set(ctx context.Context, p setObjectACLParams, cfg common.Config) (result c
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
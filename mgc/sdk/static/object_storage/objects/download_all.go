@@ -53,7 +53,7 @@ func createObjectDownloadProcessor(
 		rootURI := bucketName.AsURI()
 		var err error
 
-		defer func() { progressReporter.Report(1, 0, err) }()
+		defer func() { progressReporter.ReportItem(dirEntry.Path(), err) }()
 
 		objURI := rootURI.JoinPath(dirEntry.Path())
 
@@ -4,6 +4,7 @@ import (
 	"github.com/MagaluCloud/magalu/mgc/core"
 	"github.com/MagaluCloud/magalu/mgc/core/utils"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/objects/acl"
+	legal_hold "github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/objects/legal-hold"
 	object_lock "github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/objects/object-lock"
 )
 
@@ -23,6 +24,8 @@ var GetGroup = utils.NewLazyLoader[core.Grouper](func() core.Grouper {
 				getDownload(),          // object-storage objects download
 				getDownloadAll(),       // object-storage objects download-all
 				getHead(),              // object-storage objects head
+				getInventory(),         // object-storage objects inventory
+				legal_hold.GetGroup(),  // object-storage objects legal-hold
 				getList(),              // object-storage objects list
 				getMoveDir(),           // object-storage objects move-dir
 				getMove(),              // object-storage objects move
@@ -31,7 +34,10 @@ var GetGroup = utils.NewLazyLoader[core.Grouper](func() core.Grouper {
 				getUpload(),            // object-storage objects upload
 				getUploadDir(),         // object-storage objects upload-dir
 				getPresign(),           // object-storage objects presigned
+				getPresignPost(),       // object-storage objects presign-post
+				getPurgeVersions(),     // object-storage objects purge-versions
 				getPublicUrl(),         // object-storage objects public-url
+				getRestore(),           // object-storage objects restore
 				getVersions(),          // object-storage objects versions
 			}
 		},
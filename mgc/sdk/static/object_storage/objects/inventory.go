@@ -0,0 +1,123 @@
+package objects
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/buckets/versioning"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+const (
+	inventoryFormatCSV   = "csv"
+	inventoryFormatJSONL = "jsonl"
+)
+
+type inventoryParams struct {
+	Destination mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Path of the bucket to take the inventory of. A path inside the bucket limits the inventory to that prefix,example=bucket1" mgc:"positional"`
+	Format      string           `json:"format,omitempty" jsonschema:"description=csv writes a header followed by one row per object. jsonl writes one flat JSON object per line with the same columns,enum=csv,enum=jsonl,default=csv"`
+	File        string           `json:"file,omitempty" jsonschema:"description=File to write the inventory to. Defaults to the standard output"`
+}
+
+type inventoryResult struct {
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+	File    string `json:"file"`
+}
+
+var getInventory = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	executor := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "inventory",
+			Summary:     "Write the list of all objects of a bucket as CSV or JSON lines",
+			Description: "Page through all objects of the bucket and write one row per object, with its key, version, size, storage class, modification time and ETag. When versioning was ever enabled for the bucket, all versions and delete markers are included",
+		},
+		inventory,
+	)
+	return core.NewExecuteResultOutputOptions(executor, func(exec core.Executor, result core.Result) string {
+		return "template=Wrote {{.objects}} objects to {{.file}}\n"
+	})
+})
+
+func inventory(ctx context.Context, p inventoryParams, cfg common.Config) (result *inventoryResult, err error) {
+	if p.Format == "" {
+		p.Format = inventoryFormatCSV
+	}
+	if p.Format != inventoryFormatCSV && p.Format != inventoryFormatJSONL {
+		return nil, core.UsageError{Err: fmt.Errorf("unknown format %q", p.Format)}
+	}
+
+	withVersions, err := versioning.BucketHasVersions(ctx, cfg, common.NewBucketNameFromURI(p.Destination))
+	if err != nil {
+		return nil, err
+	}
+
+	var w io.Writer = os.Stdout
+	if p.File != "" {
+		f, err := os.OpenFile(p.File, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, utils.FILE_PERMISSION)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			err = errors.Join(err, f.Close())
+		}()
+		w = f
+	}
+
+	result = &inventoryResult{File: p.File}
+	write := newInventoryWriter(w, p.Format)
+	err = common.ListInventory(ctx, cfg, p.Destination, withVersions, func(obj common.InventoryObject) error {
+		result.Objects++
+		result.Size += obj.Size
+		return write(&obj)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := write(nil); err != nil {
+		return nil, err
+	}
+
+	// the summary would be mixed with the inventory in the standard output
+	if p.File == "" {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// The returned function writes one object, or flushes the output when called with nil
+func newInventoryWriter(w io.Writer, format string) func(obj *common.InventoryObject) error {
+	if format == inventoryFormatJSONL {
+		encoder := json.NewEncoder(w)
+		return func(obj *common.InventoryObject) error {
+			if obj == nil {
+				return nil
+			}
+			return encoder.Encode(obj)
+		}
+	}
+
+	csvWriter := csv.NewWriter(w)
+	header := true
+	return func(obj *common.InventoryObject) error {
+		if header {
+			header = false
+			if err := csvWriter.Write(common.InventoryColumns); err != nil {
+				return err
+			}
+		}
+		if obj == nil {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+		return csvWriter.Write(obj.Record())
+	}
+}
@@ -0,0 +1,69 @@
+package legal_hold
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+type getLegalHoldParams struct {
+	Object  mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Specifies the object whose legal hold is being requested" mgc:"positional"`
+	Version string           `json:"obj_version,omitempty" jsonschema:"description=Version of the object, defaults to the current one"`
+}
+
+type legalHoldResponse struct {
+	Status common.ObjectLegalHoldStatus
+}
+
+var getGet = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	var exec core.Executor = core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "get",
+			Description: "Get the legal hold status of the specified object",
+		},
+		getLegalHold,
+	)
+	exec = core.NewExecuteResultOutputOptions(exec, func(exec core.Executor, result core.Result) string {
+		return "json"
+	})
+	return exec
+})
+
+func getLegalHold(ctx context.Context, params getLegalHoldParams, cfg common.Config) (result legalHoldResponse, err error) {
+	req, err := newLegalHoldRequest(ctx, cfg, http.MethodGet, params.Object, params.Version)
+	if err != nil {
+		return
+	}
+
+	res, err := common.SendRequest(ctx, req, cfg)
+	if err != nil {
+		return
+	}
+
+	return common.UnwrapResponse[legalHoldResponse](res, req)
+}
+
+func newLegalHoldRequest(ctx context.Context, cfg common.Config, method string, obj mgcSchemaPkg.URI, version string) (*http.Request, error) {
+	url, err := common.BuildBucketHostWithPath(cfg, common.NewBucketNameFromURI(obj), obj.Path())
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, string(url), nil)
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	query := req.URL.Query()
+	query.Add("legal-hold", "")
+	if version != "" {
+		query.Set("versionId", version)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	return req, nil
+}
@@ -0,0 +1,21 @@
+package legal_hold
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{
+			Name:        "legal-hold",
+			Description: "Object legal hold commands. Objects under legal hold can't be deleted or overwritten until it's removed, regardless of their retention",
+		},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				getGet(), // object-storage objects legal-hold get
+				getSet(), // object-storage objects legal-hold set
+			}
+		},
+	)
+})
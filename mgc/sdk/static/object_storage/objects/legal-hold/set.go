@@ -0,0 +1,68 @@
+package legal_hold
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+type setLegalHoldParams struct {
+	Object  mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Specifies the object whose legal hold is being set" mgc:"positional"`
+	Status  string           `json:"status" jsonschema:"description=Legal hold status,enum=ON,enum=OFF"`
+	Version string           `json:"obj_version,omitempty" jsonschema:"description=Version of the object, defaults to the current one"`
+}
+
+var getSet = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	var exec core.Executor = core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "set",
+			Description: "Place or remove a legal hold on the specified object",
+		},
+		setLegalHold,
+	)
+
+	exec = core.NewExecuteFormat(exec, func(exec core.Executor, result core.Result) string {
+		return fmt.Sprintf("Successfully set legal hold %s for object %q", result.Source().Parameters["status"], result.Source().Parameters["dst"])
+	})
+
+	return exec
+})
+
+func setLegalHold(ctx context.Context, params setLegalHoldParams, cfg common.Config) (result core.Value, err error) {
+	status := common.ObjectLegalHoldStatus(params.Status)
+	if status != common.ObjectLegalHoldStatusOn && status != common.ObjectLegalHoldStatusOff {
+		return nil, core.UsageError{Err: fmt.Errorf("invalid status %q, must be ON or OFF", params.Status)}
+	}
+
+	req, err := newLegalHoldRequest(ctx, cfg, http.MethodPut, params.Object, params.Version)
+	if err != nil {
+		return
+	}
+
+	body, err := xml.Marshal(common.NewObjectLegalHoldBody(status))
+	if err != nil {
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+
+	resp, err := common.SendRequest(ctx, req, cfg)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	err = common.ExtractErr(resp, req)
+	return
+}
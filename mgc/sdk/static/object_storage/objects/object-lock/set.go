@@ -19,6 +19,12 @@ type setObjectLockParams struct {
 	Object          mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Specifies the object whose lock is being requested" mgc:"positional"`
 	RetainUntilDate string           `json:"retain_until_date" jsonschema:"description=Timestamp in ISO 8601 format,example=2025-10-03T00:00:00"`
 	Mode            string           `json:"mode,omitempty" jsonschema:"description=Lock mode,enum=COMPLIANCE,enum=GOVERNANCE,default=COMPLIANCE,required" mgc:"hidden"`
+	Prefix          bool             `json:"prefix,omitempty" jsonschema:"description=Set the lock of all objects under dst instead of a single object. Objects are processed in parallel according to the workers config and the failed ones are reported at the end,default=false"`
+}
+
+type setObjectLockPrefixResult struct {
+	Prefix  mgcSchemaPkg.URI `json:"prefix"`
+	Objects int64            `json:"objects"`
 }
 
 var getSet = utils.NewLazyLoader(func() core.Executor {
@@ -30,7 +36,43 @@ var getSet = utils.NewLazyLoader(func() core.Executor {
 		setObjectLocking,
 	)
 
+	// Locking a prefix may lock many objects, and COMPLIANCE locks can't be removed by anyone
+	exec = core.NewContextConfirmableExecutor(exec, func(ctx context.Context, parameters core.Parameters, configs core.Configs) (string, error) {
+		if prefix, _ := parameters["prefix"].(bool); !prefix {
+			return "", nil
+		}
+
+		var params setObjectLockParams
+		var cfg common.Config
+		if err := utils.DecodeValue(parameters, &params); err != nil {
+			return "", err
+		}
+		if err := utils.DecodeValue(configs, &cfg); err != nil {
+			return "", err
+		}
+		if params.Mode == "" {
+			params.Mode = "COMPLIANCE"
+		}
+		if _, err := parseISODate(params.RetainUntilDate); err != nil {
+			return "", core.UsageError{Err: err}
+		}
+
+		count, err := common.CountObjects(ctx, cfg, params.Object)
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return "", nil
+		}
+		return fmt.Sprintf("This will lock %d objects under %q in %s mode until %s. Do you wish to continue?", count, params.Object, params.Mode, params.RetainUntilDate), nil
+	})
+
 	exec = core.NewExecuteFormat(exec, func(exec core.Executor, result core.Result) string {
+		if r, ok := core.ResultAs[core.ResultWithValue](result); ok {
+			if prefixResult, ok := r.Value().(*setObjectLockPrefixResult); ok {
+				return fmt.Sprintf("Successfully set Object Locking for %d objects under %q", prefixResult.Objects, prefixResult.Prefix)
+			}
+		}
 		return fmt.Sprintf("Successfully set Object Locking for object %q", result.Source().Parameters["dst"])
 	})
 
@@ -38,6 +80,30 @@ var getSet = utils.NewLazyLoader(func() core.Executor {
 })
 
 func setObjectLocking(ctx context.Context, params setObjectLockParams, cfg common.Config) (result core.Value, err error) {
+	if !params.Prefix {
+		err = setObjectRetention(ctx, params, cfg)
+		return
+	}
+
+	// Fail once for an invalid date, rather than once per object
+	if _, err = parseISODate(params.RetainUntilDate); err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	msg := fmt.Sprintf("Setting Object Locking for objects under %q", params.Object)
+	count, err := common.ApplyToObjects(ctx, cfg, params.Object, msg, func(ctx context.Context, objURI mgcSchemaPkg.URI) error {
+		objParams := params
+		objParams.Object = objURI
+		return setObjectRetention(ctx, objParams, cfg)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set Object Locking for some objects, %d succeeded: %w", count, err)
+	}
+
+	return &setObjectLockPrefixResult{Prefix: params.Object, Objects: count}, nil
+}
+
+func setObjectRetention(ctx context.Context, params setObjectLockParams, cfg common.Config) (err error) {
 	req, err := newSetObjectLockingRequest(ctx, params, cfg)
 	if err != nil {
 		return
@@ -47,6 +113,7 @@ func setObjectLocking(ctx context.Context, params setObjectLockParams, cfg commo
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	err = common.ExtractErr(resp, req)
 	if err != nil {
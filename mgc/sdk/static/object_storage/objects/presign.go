@@ -48,7 +48,10 @@ func presign(ctx context.Context, p presignObjectParams, cfg common.Config) (pre
 		return nil, fmt.Errorf("programming error: unable to get auth from context")
 	}
 
-	accessKey, accessSecretKey := auth.AccessKeyPair()
+	accessKey, accessSecretKey, err := auth.AccessKeyPairFor(cfg.KeyPair, common.NewBucketNameFromURI(p.Destination).String())
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
 
 	if p.Expiry == "" {
 		p.Expiry = "5m"
@@ -0,0 +1,83 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcAuthPkg "github.com/MagaluCloud/magalu/mgc/core/auth"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+type presignPostParams struct {
+	Destination      mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Path of the object to be uploaded. Ending in '/' allows any key with that prefix,example=bucket1/uploads/" mgc:"positional"`
+	Expiry           string           `json:"expires-in,omitempty" jsonschema_description:"Expiration time for the upload form. Valid time units are 'ns, 'us' (or 'µs'), 'ms', 's',  'm', and 'h'.default=5m" jsonschema:"example=2h"`
+	ContentLengthMin int64            `json:"content-length-min,omitempty" jsonschema:"description=Minimum size of the uploaded file in bytes,minimum=0"`
+	ContentLengthMax int64            `json:"content-length-max,omitempty" jsonschema:"description=Maximum size of the uploaded file in bytes. The size isn't checked if not set,minimum=0"`
+	ContentType      string           `json:"content-type,omitempty" jsonschema:"description=Content-Type of the uploaded file. Ending in '/' allows any type with that prefix,example=image/"`
+}
+
+var getPresignPost = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	executor := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "presign-post",
+			Summary: "Generate a pre-signed POST policy for browser form uploads",
+			Description: `Generate the URL and the form fields of an HTML form that uploads a file straight to
+the bucket, without sharing credentials. The server rejects uploads that don't match the
+key, size and content type constraints, or that happen after the form expires.
+
+The fields must come before the "file" one in the form.`,
+		},
+		presignPost,
+	)
+	return core.NewExecuteResultOutputOptions(executor, func(exec core.Executor, result core.Result) string {
+		return "json"
+	})
+})
+
+func presignPost(ctx context.Context, p presignPostParams, cfg common.Config) (*common.PresignedPost, error) {
+	auth := mgcAuthPkg.FromContext(ctx)
+	if auth == nil {
+		return nil, fmt.Errorf("programming error: unable to get auth from context")
+	}
+	bucketName := common.NewBucketNameFromURI(p.Destination)
+	accessKey, accessSecretKey, err := auth.AccessKeyPairFor(cfg.KeyPair, bucketName.String())
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	if p.Expiry == "" {
+		p.Expiry = "5m"
+	}
+	expiresIn, err := time.ParseDuration(p.Expiry)
+	if err != nil {
+		return nil, core.UsageError{Err: fmt.Errorf("error when parsing the expiration time for presigned post: %w", err)}
+	}
+	if expiresIn < time.Second || expiresIn > maxPresignExpiry {
+		return nil, core.UsageError{Err: fmt.Errorf("expiration time for presigned post should be between 1 second and 7 days")}
+	}
+
+	bucketURL, err := common.BuildBucketHostURL(cfg, bucketName)
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	key := p.Destination.Path()
+	if key != "" && strings.HasSuffix(p.Destination.String(), "/") {
+		// Path() drops the trailing "/" that makes the key a prefix
+		key += "/"
+	}
+
+	return common.NewPresignedPost(bucketURL, bucketName, accessKey, accessSecretKey, cfg.Region, time.Now(), expiresIn, common.PostPolicyConditions{
+		Key:              key,
+		ContentLengthMin: p.ContentLengthMin,
+		ContentLengthMax: p.ContentLengthMax,
+		ContentType:      p.ContentType,
+	})
+}
@@ -0,0 +1,132 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/pipeline"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+type purgeVersionsParams struct {
+	Destination mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Path of the bucket or prefix to purge the versions from,example=bucket1/logs/" mgc:"positional"`
+	Keep        int              `json:"keep,omitempty" jsonschema:"description=Number of noncurrent versions to keep for each object,default=0,minimum=0"`
+	OlderThan   string           `json:"older_than,omitempty" jsonschema:"description=Only purge versions last modified longer ago than this. Accepts days or Go durations,example=30d"`
+	DryRun      bool             `json:"dry_run,omitempty" jsonschema:"description=Only list the versions that would be purged,default=false"`
+	BatchSize   int              `json:"batch_size,omitempty" jsonschema:"description=Limit of items per batch to delete,default=1000,minimum=1,maximum=1000" example:"1000"`
+}
+
+type purgeVersionsResult struct {
+	Destination mgcSchemaPkg.URI         `json:"dst"`
+	Versions    int                      `json:"versions"`
+	Size        int64                    `json:"size"`
+	DryRun      bool                     `json:"dry_run"`
+	Plan        []common.InventoryObject `json:"plan,omitempty"`
+}
+
+var getPurgeVersions = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	exec := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "purge-versions",
+			Summary: "Delete the noncurrent versions and delete markers of objects",
+			Description: `Delete the noncurrent versions of the objects under the given path, except for
+the newest --keep ones of each object, and their noncurrent delete markers. Delete markers
+left without any version are deleted as well. With --older-than, only versions last modified
+before that are deleted.
+
+Use --dry-run to list what would be deleted first, as the deletion is NOT reversible.`,
+		},
+		purgeVersions,
+	)
+
+	confirm := core.ConfirmPromptWithTemplate("This will permanently delete the noncurrent versions of the objects at {{.parameters.dst}}{{if .parameters.keep}}, keeping the {{.parameters.keep}} newest ones{{end}}{{if .parameters.older_than}}, last modified more than {{.parameters.older_than}} ago{{end}}. Do you wish to continue?")
+	cExecutor := core.NewConfirmableExecutor(exec, func(parameters core.Parameters, configs core.Configs) string {
+		if dryRun, _ := parameters["dry_run"].(bool); dryRun {
+			return ""
+		}
+		return confirm(parameters, configs)
+	})
+
+	return core.NewExecuteResultOutputOptions(cExecutor, func(exec core.Executor, result core.Result) string {
+		return "yaml"
+	})
+})
+
+func purgeVersions(ctx context.Context, p purgeVersionsParams, cfg common.Config) (*purgeVersionsResult, error) {
+	var olderThan time.Duration
+	if p.OlderThan != "" {
+		var err error
+		if olderThan, err = common.ParseAge(p.OlderThan); err != nil {
+			return nil, core.UsageError{Err: err}
+		}
+	}
+	if p.Keep < 0 {
+		return nil, core.UsageError{Err: fmt.Errorf("keep must not be negative: %d", p.Keep)}
+	}
+	if p.BatchSize == 0 {
+		p.BatchSize = common.MaxBatchSize
+	}
+
+	var objects []common.InventoryObject
+	err := common.ListInventory(ctx, cfg, p.Destination, true, func(obj common.InventoryObject) error {
+		objects = append(objects, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := common.PlanPurgeVersions(objects, p.Keep, olderThan, time.Now())
+	result := &purgeVersionsResult{Destination: p.Destination, Versions: len(plan), DryRun: p.DryRun}
+	for _, obj := range plan {
+		result.Size += obj.Size
+	}
+
+	if p.DryRun {
+		result.Plan = plan
+		return result, nil
+	}
+	if len(plan) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err = common.DeleteObjects(ctx, common.DeleteObjectsParams{
+		Destination:  p.Destination,
+		ToDelete:     versionsToWalkDirEntry(ctx, plan),
+		BatchSize:    p.BatchSize,
+		WithVersions: true,
+	}, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func versionsToWalkDirEntry(ctx context.Context, versions []common.InventoryObject) <-chan pipeline.WalkDirEntry {
+	out := make(chan pipeline.WalkDirEntry)
+	go func() {
+		defer close(out)
+		for _, v := range versions {
+			entry := pipeline.NewSimpleWalkDirEntry(v.Key, &common.BucketContent{
+				Key:          v.Key,
+				VersionID:    v.VersionID,
+				LastModified: v.LastModified,
+				ContentSize:  v.Size,
+				StorageClass: v.StorageClass,
+			}, nil)
+			select {
+			case <-ctx.Done():
+				return
+			case out <- entry:
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,82 @@
+package objects
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
+)
+
+type restoreParams struct {
+	Destination mgcSchemaPkg.URI `json:"dst" jsonschema:"description=Path of the object to restore,example=bucket1/file.txt" mgc:"positional"`
+	VersionID   string           `json:"version_id" jsonschema:"description=Version of the object to restore. See 'objects versions' for the versions of an object"`
+	DryRun      bool             `json:"dry_run,omitempty" jsonschema:"description=Only show what would be restored,default=false"`
+}
+
+type restoreResult struct {
+	Destination  mgcSchemaPkg.URI `json:"dst"`
+	VersionID    string           `json:"version_id"`
+	Size         int64            `json:"size"`
+	LastModified string           `json:"last_modified"`
+	DryRun       bool             `json:"dry_run"`
+}
+
+var getRestore = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	exec := core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:        "restore",
+			Summary:     "Restore an older version of an object",
+			Description: "Copy the given version of the object over itself, so it becomes the current version. The other versions, including the one that was current, are kept",
+		},
+		restore,
+	)
+
+	confirm := core.ConfirmPromptWithTemplate("This will replace the current version of {{.parameters.dst}} with version {{.parameters.version_id}}. Do you wish to continue?")
+	cExecutor := core.NewConfirmableExecutor(exec, func(parameters core.Parameters, configs core.Configs) string {
+		if dryRun, _ := parameters["dry_run"].(bool); dryRun {
+			return ""
+		}
+		return confirm(parameters, configs)
+	})
+
+	return core.NewExecuteResultOutputOptions(cExecutor, func(exec core.Executor, result core.Result) string {
+		return "template={{if .dry_run}}Would restore{{else}}Restored{{end}} {{.dst}} to version {{.version_id}} ({{.size}} bytes, last modified at {{.last_modified}})\n"
+	})
+})
+
+func restore(ctx context.Context, p restoreParams, cfg common.Config) (*restoreResult, error) {
+	if p.Destination.Filename() == "" {
+		return nil, core.UsageError{Err: fmt.Errorf("destination must be a URI to an object")}
+	}
+	if p.VersionID == "" {
+		return nil, core.UsageError{Err: fmt.Errorf("version_id is required")}
+	}
+
+	metadata, err := common.HeadFile(ctx, cfg, p.Destination, p.VersionID)
+	if err != nil {
+		return nil, fmt.Errorf("error validating version %q: %w", p.VersionID, err)
+	}
+
+	result := &restoreResult{
+		Destination:  p.Destination,
+		VersionID:    p.VersionID,
+		Size:         metadata.ContentLength,
+		LastModified: metadata.LastModified,
+		DryRun:       p.DryRun,
+	}
+	if p.DryRun {
+		return result, nil
+	}
+
+	copier, err := common.NewCopier(ctx, cfg, p.Destination, p.Destination, p.VersionID, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := copier.Copy(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
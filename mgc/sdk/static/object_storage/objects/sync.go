@@ -10,11 +10,10 @@ import (
 
 	"github.com/MagaluCloud/magalu/mgc/core"
 	"github.com/MagaluCloud/magalu/mgc/core/pipeline"
+	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
 	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
 	"github.com/MagaluCloud/magalu/mgc/core/utils"
-	"github.com/MagaluCloud/magalu/mgc/sdk/openapi"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
-	"github.com/pterm/pterm"
 )
 
 type UploadCounter struct {
@@ -92,26 +91,19 @@ func sync(ctx context.Context, params syncParams, cfg common.Config) (result cor
 		return nil, err
 	}
 
-	totalFiles := len(files)
-	progressBar := pterm.DefaultProgressbar.
-		WithTotal(totalFiles).
-		WithTitle("Syncing files").
-		WithRemoveWhenDone(true)
-
-	if !openapi.GetRawOutputFlag(ctx) {
-		progressBar, _ = progressBar.Start()
-	}
+	progressReportMsg := fmt.Sprintf("Syncing %q to %q", params.Local, params.Bucket)
+	progressReporter := progress_report.NewUnitsReporter(ctx, progressReportMsg, uint64(len(files)))
+	progressReporter.Start()
+	defer progressReporter.End()
 
 	fillBucketFiles(ctx, params, cfg)
 
-	err = processSyncFiles(ctx, cfg, params.Local, params.Bucket, basePath.String(), files, progressBar)
+	err = processSyncFiles(ctx, cfg, params.Local, params.Bucket, basePath.String(), files, progressReporter)
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, _ = progressBar.Stop()
-
 	deletedFiles := make([]string, 0, len(allBucketFiles))
 
 	if params.Delete {
@@ -218,7 +210,7 @@ func (c *UploadCounter) Value() uint64 {
 	return c.v
 }
 
-func processSyncFiles(ctx context.Context, cfg common.Config, source, destination mgcSchemaPkg.URI, basePath string, files []string, progressBar *pterm.ProgressbarPrinter) error {
+func processSyncFiles(ctx context.Context, cfg common.Config, source, destination mgcSchemaPkg.URI, basePath string, files []string, progressReporter *progress_report.UnitsReporter) error {
 	results := make(chan error, cfg.Workers)
 	filesChan := make(chan string, cfg.Workers)
 
@@ -227,7 +219,7 @@ func processSyncFiles(ctx context.Context, cfg common.Config, source, destinatio
 	for i := 0; i < cfg.Workers; i++ {
 		go func() {
 			defer wg.Done()
-			syncWorker(ctx, cfg, source, destination, basePath, filesChan, results, progressBar)
+			syncWorker(ctx, cfg, source, destination, basePath, filesChan, results, progressReporter)
 		}()
 	}
 
@@ -256,14 +248,14 @@ func processSyncFiles(ctx context.Context, cfg common.Config, source, destinatio
 	return nil
 }
 
-func syncWorker(ctx context.Context, cfg common.Config, source, destination mgcSchemaPkg.URI, basePath string, files <-chan string, results chan<- error, progressBar *pterm.ProgressbarPrinter) {
+func syncWorker(ctx context.Context, cfg common.Config, source, destination mgcSchemaPkg.URI, basePath string, files <-chan string, results chan<- error, progressReporter *progress_report.UnitsReporter) {
 	for {
 		select {
 		case file, ok := <-files:
 			if !ok {
 				return
 			}
-			err := processSyncFile(ctx, cfg, source, destination, basePath, file, progressBar)
+			err := processSyncFile(ctx, cfg, source, destination, basePath, file, progressReporter)
 			if err != nil {
 				select {
 				case results <- err:
@@ -277,7 +269,7 @@ func syncWorker(ctx context.Context, cfg common.Config, source, destination mgcS
 	}
 }
 
-func processSyncFile(ctx context.Context, cfg common.Config, source, destination mgcSchemaPkg.URI, basePath, file string, progressBar *pterm.ProgressbarPrinter) error {
+func processSyncFile(ctx context.Context, cfg common.Config, source, destination mgcSchemaPkg.URI, basePath, file string, progressReporter *progress_report.UnitsReporter) error {
 	normalizedSource, err := common.GetAbsSystemURI(mgcSchemaPkg.URI(file))
 	if err != nil {
 		logger().Debugw("error with path", "error", err)
@@ -305,16 +297,18 @@ func processSyncFile(ctx context.Context, cfg common.Config, source, destination
 	isLocalOlderThenBucket := info.ModTime().Unix() < fileStats.SourceModTime
 	if err == nil && isSameSize && isLocalOlderThenBucket {
 		logger().Debug("Skipping file [%s] - no change", normalizedSource)
-		progressBar.Increment()
+		progressReporter.ReportItem(file, nil)
 		return nil
 	}
 
 	err = uploadFile(ctx, normalizedSource, normalizedDestination, cfg)
 	if err != nil {
-		return &common.ObjectError{Url: mgcSchemaPkg.URI(normalizedSource.Path()), Err: err}
+		err = &common.ObjectError{Url: mgcSchemaPkg.URI(normalizedSource.Path()), Err: err}
+		progressReporter.ReportItem(file, err)
+		return err
 	}
 
 	uploadFiles.Increment()
-	progressBar.Increment()
+	progressReporter.ReportItem(file, nil)
 	return nil
 }
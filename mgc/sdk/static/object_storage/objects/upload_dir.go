@@ -8,11 +8,10 @@ import (
 	syncer "sync"
 
 	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/progress_report"
 	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
 	"github.com/MagaluCloud/magalu/mgc/core/utils"
-	"github.com/MagaluCloud/magalu/mgc/sdk/openapi"
 	"github.com/MagaluCloud/magalu/mgc/sdk/static/object_storage/common"
-	"github.com/pterm/pterm"
 )
 
 type uploadDirParams struct {
@@ -60,31 +59,24 @@ func uploadDir(ctx context.Context, params uploadDirParams, cfg common.Config) (
 		return nil, err
 	}
 
-	totalFiles := len(files)
-	progressBar := pterm.DefaultProgressbar.
-		WithTotal(totalFiles).
-		WithTitle("Uploading files").
-		WithRemoveWhenDone(true)
+	progressReportMsg := fmt.Sprintf("Uploading %q to %q", basePath.String(), params.Destination)
+	progressReporter := progress_report.NewUnitsReporter(ctx, progressReportMsg, uint64(len(files)))
+	progressReporter.Start()
+	defer progressReporter.End()
 
-	if !openapi.GetRawOutputFlag(ctx) {
-		progressBar, _ = progressBar.Start()
-	}
-
-	err = processCurrentAndSubfolders(ctx, cfg, params.Destination, params.StorageClass, basePath.String(), files, progressBar)
+	err = processCurrentAndSubfolders(ctx, cfg, params.Destination, params.StorageClass, basePath.String(), files, progressReporter)
 
 	if err != nil {
 		return &uploadDirResult{}, err
 	}
 
-	_, _ = progressBar.Stop()
-
 	return &uploadDirResult{
 		URI: params.Destination.String(),
 		Dir: basePath.String(),
 	}, nil
 }
 
-func processFile(ctx context.Context, cfg common.Config, destination mgcSchemaPkg.URI, basePath string, storageClass string, file string, progressBar *pterm.ProgressbarPrinter) error {
+func processFile(ctx context.Context, cfg common.Config, destination mgcSchemaPkg.URI, basePath string, storageClass string, file string, progressReporter *progress_report.UnitsReporter) error {
 
 	relPath := common.GetRelativePath(basePath, file)
 
@@ -98,21 +90,22 @@ func processFile(ctx context.Context, cfg common.Config, destination mgcSchemaPk
 
 	if err != nil {
 		err = &common.ObjectError{Url: mgcSchemaPkg.URI(dst), Err: err}
+		progressReporter.ReportItem(file, err)
 		return err
 	}
 
-	progressBar.Increment()
+	progressReporter.ReportItem(file, nil)
 	return nil
 }
 
-func worker(ctx context.Context, cfg common.Config, destination mgcSchemaPkg.URI, basePath string, storageClass string, files <-chan string, results chan<- error, progressBar *pterm.ProgressbarPrinter) {
+func worker(ctx context.Context, cfg common.Config, destination mgcSchemaPkg.URI, basePath string, storageClass string, files <-chan string, results chan<- error, progressReporter *progress_report.UnitsReporter) {
 	for {
 		select {
 		case file, ok := <-files:
 			if !ok {
 				return
 			}
-			err := processFile(ctx, cfg, destination, basePath, storageClass, file, progressBar)
+			err := processFile(ctx, cfg, destination, basePath, storageClass, file, progressReporter)
 			if err != nil {
 				select {
 				case results <- err:
@@ -126,7 +119,7 @@ func worker(ctx context.Context, cfg common.Config, destination mgcSchemaPkg.URI
 	}
 }
 
-func processCurrentAndSubfolders(ctx context.Context, cfg common.Config, destination mgcSchemaPkg.URI, storageClass string, path string, files []string, progressBar *pterm.ProgressbarPrinter) error {
+func processCurrentAndSubfolders(ctx context.Context, cfg common.Config, destination mgcSchemaPkg.URI, storageClass string, path string, files []string, progressReporter *progress_report.UnitsReporter) error {
 	results := make(chan error, cfg.Workers)
 	filesChan := make(chan string, cfg.Workers)
 
@@ -135,7 +128,7 @@ func processCurrentAndSubfolders(ctx context.Context, cfg common.Config, destina
 	for i := 0; i < cfg.Workers; i++ {
 		go func() {
 			defer wg.Done()
-			worker(ctx, cfg, destination, path, storageClass, filesChan, results, progressBar)
+			worker(ctx, cfg, destination, path, storageClass, filesChan, results, progressReporter)
 		}()
 	}
 
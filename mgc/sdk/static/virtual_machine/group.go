@@ -0,0 +1,19 @@
+package virtual_machine
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+	"github.com/MagaluCloud/magalu/mgc/sdk/static/virtual_machine/instances"
+)
+
+// Has the same name of the virtual-machine OpenAPI group, so its description and operations are kept
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "virtual-machine"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				instances.GetGroup(), // virtual-machine instances
+			}
+		},
+	)
+})
@@ -0,0 +1,329 @@
+package instances
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/config"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type Config struct {
+	Region string `json:"region,omitempty" jsonschema:"description=Region to reach the service,default=br-se1,enum=br-ne1,enum=br-se1,enum=br-mgl1"`
+	Env    string `json:"env,omitempty" jsonschema:"description=Environment to use,default=prod,enum=prod,enum=pre-prod"`
+
+	// See more about the 'squash' directive here: https://pkg.go.dev/github.com/mitchellh/mapstructure#hdr-Embedded_Structs_and_Squashing
+	config.NetworkConfig `json:",squash"` // nolint
+}
+
+// Options shared by ssh and scp to reach the instance
+type connectionParams struct {
+	User         string `json:"user,omitempty" jsonschema_description:"Remote user. Defaults to the user of the instance image, such as 'ubuntu'"`
+	IdentityFile string `json:"identity_file,omitempty" jsonschema_description:"Private key file. Defaults to the file in ~/.ssh named after the instance ssh_key_name, if any"`
+	IPv6         bool   `json:"ipv6,omitempty" jsonschema_description:"Use the public IPv6 address, even if there is a public IPv4"`
+	Private      bool   `json:"private,omitempty" jsonschema_description:"Use the private IPv4 address, such as from inside the VPC or a VPN"`
+}
+
+// Path in the SDK tree of the virtual-machine instances group
+var instancesGroupPath = []string{"virtual-machine", "instances"}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Default users of the public images, by image name prefix, after the "cloud-" prefix
+var imageUsers = []struct{ prefix, user string }{
+	{"ubuntu", "ubuntu"},
+	{"debian", "debian"},
+	{"rocky", "rocky"},
+	{"almalinux", "almalinux"},
+	{"centos", "centos"},
+	{"fedora", "fedora"},
+	{"oraclelinux", "opc"},
+	{"opensuse", "opensuse"},
+}
+
+// Only the fields used to connect, as returned by get/list with expand=network,image
+type instance struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	State      string  `json:"state"`
+	SshKeyName *string `json:"ssh_key_name"`
+	Image      *struct {
+		Name     string `json:"name"`
+		Platform string `json:"platform"`
+	} `json:"image"`
+	Network *struct {
+		// Newer API responses
+		Interfaces []struct {
+			Primary              *bool   `json:"primary"`
+			AssociatedPublicIPv4 *string `json:"associated_public_ipv4"`
+			IPAddresses          *struct {
+				PrivateIPv4 *string `json:"private_ipv4"`
+				PublicIPv6  *string `json:"public_ipv6"`
+			} `json:"ip_addresses"`
+		} `json:"interfaces"`
+		// Older API responses
+		Ports []struct {
+			IPAddresses *struct {
+				PublicIPAddress  *string `json:"publicIpAddress"`
+				PrivateIPAddress *string `json:"privateIpAddress"`
+				IPv6Address      *string `json:"ipV6Address"`
+			} `json:"ipAddresses"`
+		} `json:"ports"`
+	} `json:"network"`
+}
+
+// Where and how to connect to an instance
+type sshTarget struct {
+	User         string
+	Address      string
+	IdentityFile string
+}
+
+func (t sshTarget) isIPv6() bool {
+	return strings.Contains(t.Address, ":")
+}
+
+// user@host for ssh
+func (t sshTarget) destination() string {
+	return t.User + "@" + t.Address
+}
+
+// user@host for scp, IPv6 addresses must be bracketed to not be mistaken by the path separator
+func (t sshTarget) scpDestination() string {
+	if t.isIPv6() {
+		return t.User + "@[" + t.Address + "]"
+	}
+	return t.destination()
+}
+
+func (t sshTarget) identityArgs() []string {
+	if t.IdentityFile == "" {
+		return nil
+	}
+	return []string{"-i", t.IdentityFile}
+}
+
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+type instanceAddresses struct {
+	PublicIPv4  []string
+	PublicIPv6  []string
+	PrivateIPv4 []string
+}
+
+// Primary interfaces come first
+func (i *instance) addresses() (result instanceAddresses) {
+	if i.Network == nil {
+		return
+	}
+
+	add := func(list *[]string, value *string, primary bool) {
+		v := strValue(value)
+		if v == "" {
+			return
+		}
+		if primary {
+			*list = append([]string{v}, *list...)
+		} else {
+			*list = append(*list, v)
+		}
+	}
+
+	for _, iface := range i.Network.Interfaces {
+		primary := iface.Primary == nil || *iface.Primary
+		add(&result.PublicIPv4, iface.AssociatedPublicIPv4, primary)
+		if iface.IPAddresses != nil {
+			add(&result.PublicIPv6, iface.IPAddresses.PublicIPv6, primary)
+			add(&result.PrivateIPv4, iface.IPAddresses.PrivateIPv4, primary)
+		}
+	}
+	for _, port := range i.Network.Ports {
+		if port.IPAddresses != nil {
+			add(&result.PublicIPv4, port.IPAddresses.PublicIPAddress, false)
+			add(&result.PublicIPv6, port.IPAddresses.IPv6Address, false)
+			add(&result.PrivateIPv4, port.IPAddresses.PrivateIPAddress, false)
+		}
+	}
+	return
+}
+
+// Public IPv4 is preferred, then public IPv6
+func (i *instance) address(p connectionParams) (string, error) {
+	addrs := i.addresses()
+
+	var candidates []string
+	switch {
+	case p.Private:
+		candidates = addrs.PrivateIPv4
+	case p.IPv6:
+		candidates = addrs.PublicIPv6
+	default:
+		candidates = slices.Concat(addrs.PublicIPv4, addrs.PublicIPv6)
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("instance %q has no suitable address, public IPs: %v, private IPs: %v", i.Name, slices.Concat(addrs.PublicIPv4, addrs.PublicIPv6), addrs.PrivateIPv4)
+	}
+	return candidates[0], nil
+}
+
+func (i *instance) user() (string, error) {
+	if i.Image == nil {
+		return "", fmt.Errorf("unknown image of instance %q, use --user", i.Name)
+	}
+	if strings.EqualFold(i.Image.Platform, "windows") {
+		return "", fmt.Errorf("instance %q runs Windows, which is not reachable with ssh", i.Name)
+	}
+
+	name := strings.TrimPrefix(strings.ToLower(i.Image.Name), "cloud-")
+	for _, iu := range imageUsers {
+		if strings.HasPrefix(name, iu.prefix) {
+			return iu.user, nil
+		}
+	}
+	return "", fmt.Errorf("unable to find the default user of image %q, use --user", i.Image.Name)
+}
+
+// Keys are usually saved as the name given when they were registered, with or without extensions
+func findIdentityFile(sshKeyName string) string {
+	if sshKeyName == "" {
+		return ""
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		logger().Debugw("unable to find the home directory", "error", err)
+		return ""
+	}
+
+	dir := filepath.Join(homeDir, ".ssh")
+	for _, name := range []string{sshKeyName, sshKeyName + ".pem", "id_" + sshKeyName} {
+		file := filepath.Join(dir, name)
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() {
+			return file
+		}
+	}
+
+	logger().Debugw("no local private key for ssh_key_name, ssh will use its defaults", "sshKeyName", sshKeyName, "dir", dir)
+	return ""
+}
+
+func (i *instance) target(p connectionParams) (target sshTarget, err error) {
+	if i.State != "" && i.State != "running" {
+		logger().Warnw("instance is not running", "name", i.Name, "state", i.State)
+	}
+
+	target.Address, err = i.address(p)
+	if err != nil {
+		return
+	}
+
+	target.User = p.User
+	if target.User == "" {
+		if target.User, err = i.user(); err != nil {
+			return
+		}
+	}
+
+	target.IdentityFile = p.IdentityFile
+	if target.IdentityFile == "" {
+		target.IdentityFile = findIdentityFile(strValue(i.SshKeyName))
+	}
+
+	return
+}
+
+func executeInstances[T any](ctx context.Context, name string, parameters core.Parameters, cfg Config) (*T, error) {
+	configs := core.Configs{}
+	if cfg.Region != "" {
+		configs["region"] = cfg.Region
+	}
+	if cfg.Env != "" {
+		configs["env"] = cfg.Env
+	}
+	if cfg.ServerUrl != "" {
+		configs["serverUrl"] = cfg.ServerUrl
+	}
+
+	result, err := core.ExecuteByPath(ctx, append(instancesGroupPath, name), parameters, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	resultWithValue, ok := core.ResultAs[core.ResultWithValue](result)
+	if !ok {
+		return nil, fmt.Errorf("instances %s returned no value", name)
+	}
+	return utils.DecodeNewValue[T](resultWithValue.Value())
+}
+
+// Instance IDs are used as-is, otherwise it's searched by name
+func findInstance(ctx context.Context, nameOrID string, cfg Config) (*instance, error) {
+	expand := []any{"network", "image"}
+
+	if uuidRe.MatchString(nameOrID) {
+		return executeInstances[instance](ctx, "get", core.Parameters{"id": nameOrID, "expand": expand}, cfg)
+	}
+
+	list, err := executeInstances[struct {
+		Instances []instance `json:"instances"`
+	}](ctx, "list", core.Parameters{"name": nameOrID, "expand": expand}, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []instance
+	for _, i := range list.Instances {
+		if i.Name == nameOrID {
+			found = append(found, i)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no instance named %q", nameOrID)
+	case 1:
+		return &found[0], nil
+	default:
+		ids := make([]string, len(found))
+		for i, inst := range found {
+			ids[i] = inst.ID
+		}
+		return nil, fmt.Errorf("multiple instances named %q, use one of the IDs instead: %s", nameOrID, strings.Join(ids, ", "))
+	}
+}
+
+// Run the system program attached to the current terminal. Its exit code is kept in the error
+func runSystemCommand(ctx context.Context, program string, args []string) error {
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return fmt.Errorf("%q must be installed: %w", program, err)
+	}
+
+	logger().Debugw("running", "program", path, "args", args)
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return core.ProgramExitError{Program: program, Code: exitErr.ExitCode()}
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package instances
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func parseInstance(t *testing.T, data string) *instance {
+	inst := &instance{}
+	if err := json.Unmarshal([]byte(data), inst); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return inst
+}
+
+func TestInstanceAddress(t *testing.T) {
+	inst := parseInstance(t, `{
+		"name": "my-vm",
+		"network": {
+			"interfaces": [
+				{"primary": false, "associated_public_ipv4": "200.0.0.2", "ip_addresses": {"private_ipv4": "10.0.0.2"}},
+				{"primary": true, "associated_public_ipv4": null, "ip_addresses": {"private_ipv4": "10.0.0.1", "public_ipv6": "2001:db8::1"}}
+			]
+		}
+	}`)
+
+	tests := []struct {
+		params   connectionParams
+		expected string
+	}{
+		{connectionParams{}, "200.0.0.2"},
+		{connectionParams{IPv6: true}, "2001:db8::1"},
+		{connectionParams{Private: true}, "10.0.0.1"},
+	}
+	for _, tc := range tests {
+		got, err := inst.address(tc.params)
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %s", tc.params, err)
+		}
+		if got != tc.expected {
+			t.Errorf("%+v: expected %q, got %q", tc.params, tc.expected, got)
+		}
+	}
+
+	_, err := parseInstance(t, `{"name": "no-network"}`).address(connectionParams{})
+	if err == nil {
+		t.Errorf("expected error for instance without addresses")
+	}
+}
+
+func TestInstanceAddressOldPorts(t *testing.T) {
+	inst := parseInstance(t, `{"network": {"ports": [{"ipAddresses": {"publicIpAddress": "200.0.0.1", "privateIpAddress": "10.0.0.1"}}]}}`)
+	got, err := inst.address(connectionParams{})
+	if err != nil || got != "200.0.0.1" {
+		t.Errorf("expected public IPv4 of ports, got %q (%v)", got, err)
+	}
+}
+
+func TestInstanceUser(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+		err      bool
+	}{
+		{`{"name": "cloud-ubuntu-24.04 LTS"}`, "ubuntu", false},
+		{`{"name": "my-custom-image"}`, "", true},
+		{`{"name": "ubuntu-24.04"}`, "ubuntu", false},
+		{`{"name": "Debian-12", "platform": "linux"}`, "debian", false},
+		{`{"name": "rocky-9"}`, "rocky", false},
+		{`{"name": "windows-server-2022", "platform": "windows"}`, "", true},
+	}
+	for _, tc := range tests {
+		inst := parseInstance(t, fmt.Sprintf(`{"name": "vm", "image": %s}`, tc.image))
+		got, err := inst.user()
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error result: %v", tc.image, err)
+		}
+		if got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.image, tc.expected, got)
+		}
+	}
+}
+
+func TestSplitRemotePath(t *testing.T) {
+	tests := []struct {
+		input    string
+		instance string
+		ok       bool
+	}{
+		{"my-vm:/tmp/file", "my-vm", true},
+		{"my-vm:", "my-vm", true},
+		{"./local:file", "", false},
+		{"/abs/path:file", "", false},
+		{`C:\Users\file`, "", false},
+		{"local-file", "", false},
+	}
+	for _, tc := range tests {
+		instance, _, ok := splitRemotePath(tc.input)
+		if instance != tc.instance || ok != tc.ok {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tc.input, tc.instance, tc.ok, instance, ok)
+		}
+	}
+}
+
+func TestScpArgs(t *testing.T) {
+	resolved := 0
+	resolve := func(instance string) (sshTarget, error) {
+		resolved++
+		if instance == "v6" {
+			return sshTarget{User: "debian", Address: "2001:db8::1"}, nil
+		}
+		return sshTarget{User: "ubuntu", Address: "200.0.0.1", IdentityFile: "/keys/" + instance}, nil
+	}
+
+	got, err := scpArgs([]string{"my-vm:/a", "my-vm:/b", "v6:/c", "./dst"}, true, resolve)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []string{"-i", "/keys/my-vm", "-r", "ubuntu@200.0.0.1:/a", "ubuntu@200.0.0.1:/b", "debian@[2001:db8::1]:/c", "./dst"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if resolved != 2 {
+		t.Errorf("expected each instance to be resolved once, got %d", resolved)
+	}
+
+	_, err = scpArgs([]string{"./a", "./b"}, false, resolve)
+	if err == nil {
+		t.Errorf("expected error without remote paths")
+	}
+}
@@ -0,0 +1,19 @@
+package instances
+
+import (
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+var GetGroup = utils.NewLazyLoader(func() core.Grouper {
+	return core.NewStaticGroup(
+		core.DescriptorSpec{Name: "instances"},
+		func() []core.Descriptor {
+			return []core.Descriptor{
+				getSsh(), // virtual-machine instances ssh
+				getScp(), // virtual-machine instances scp
+				core.NewExecutorOverlay("init-logs", newInitLogsExecutor), // virtual-machine instances init-logs
+			}
+		},
+	)
+})
@@ -0,0 +1,257 @@
+package instances
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	mgcSchemaPkg "github.com/MagaluCloud/magalu/mgc/core/schema"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type initLogsFollowParams struct {
+	Follow         bool          `json:"follow,omitempty" jsonschema_description:"Keep polling and print the new lines until cloud-init finishes or the timeout. The lines are printed as they arrive, regardless of the output format"`
+	Interval       time.Duration `json:"interval,omitempty" jsonschema:"type=string,format=duration,example=5s" jsonschema_description:"Time between the polls with --follow, such as 10s. Defaults to 5s"`
+	Timeout        time.Duration `json:"timeout,omitempty" jsonschema:"type=string,format=duration,example=15m" jsonschema_description:"Give up waiting for cloud-init to finish after this time with --follow, such as 30m, exiting with the timeout exit code. Defaults to 15m. Unlike the global --cli.timeout, which limits the whole command and stops it first if shorter, it only limits the wait for cloud-init"`
+	FailurePattern string        `json:"failure_pattern,omitempty" jsonschema_description:"Regular expression that stops --follow with an error when a line matches it, such as '(?i)failed|error'"`
+}
+
+var instanceGetPath = []string{"virtual-machine", "instances", "get"}
+
+// Parameters added on top of the OpenAPI operation
+var followOnlyParams = []string{"follow", "interval", "timeout", "failure_pattern"}
+
+// Maximum allowed by the API, the larger the window the less likely lines are lost between polls
+const initLogsMaxLines = 5000
+
+// Last line written by cloud-init, such as:
+// Cloud-init v. 24.1.3-0ubuntu1~22.04.1 finished at Tue, 16 Jul 2024 12:00:00 +0000. Datasource DataSourceOpenStackLocal.  Up 20.50 seconds
+var cloudInitFinishedRe = regexp.MustCompile(`Cloud-init v\. \S+ finished at`)
+
+var getFollowParamsSchema = utils.NewLazyLoaderWithError(mgcSchemaPkg.SchemaFromType[initLogsFollowParams])
+
+type initLogsExecutor struct {
+	core.Executor
+	parametersSchema *core.Schema
+}
+
+func newInitLogsExecutor(base core.Executor) (core.Executor, error) {
+	followSchema, err := getFollowParamsSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	baseSchema := base.ParametersSchema()
+	if _, ok := baseSchema.Properties["id"]; !ok {
+		return nil, fmt.Errorf("init-logs operation is missing the 'id' parameter")
+	}
+
+	schema := *baseSchema
+	schema.Properties = maps.Clone(baseSchema.Properties)
+	for _, name := range followOnlyParams {
+		schema.Properties[name] = followSchema.Properties[name]
+	}
+
+	return &initLogsExecutor{base, &schema}, nil
+}
+
+func (e *initLogsExecutor) ParametersSchema() *core.Schema {
+	return e.parametersSchema
+}
+
+func (e *initLogsExecutor) Execute(ctx context.Context, parameters core.Parameters, configs core.Configs) (core.Result, error) {
+	p, err := utils.DecodeNewValue[initLogsFollowParams](parameters)
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	baseParameters := maps.Clone(parameters)
+	for _, name := range followOnlyParams {
+		delete(baseParameters, name)
+	}
+
+	if !p.Follow {
+		result, err := e.Executor.Execute(ctx, baseParameters, configs)
+		return core.ExecutorWrapResult(e, result, err)
+	}
+
+	options, err := p.followOptions()
+	if err != nil {
+		return nil, core.UsageError{Err: err}
+	}
+
+	if _, ok := baseParameters["max-lines-count"]; !ok {
+		baseParameters["max-lines-count"] = initLogsMaxLines
+	}
+
+	fetch := func(ctx context.Context) ([]string, error) {
+		result, err := e.Executor.Execute(ctx, baseParameters, configs)
+		if err != nil {
+			return nil, err
+		}
+		resultWithValue, ok := core.ResultAs[core.ResultWithValue](result)
+		if !ok {
+			return nil, fmt.Errorf("init-logs returned no value")
+		}
+		logs, err := utils.DecodeNewValue[struct {
+			Logs []string `json:"logs"`
+		}](resultWithValue.Value())
+		if err != nil {
+			return nil, err
+		}
+		return logs.Logs, nil
+	}
+
+	// The logs are not found both for instances still being created and for unknown ones
+	checkInstance := func(ctx context.Context) error {
+		_, err := core.ExecuteByPath(ctx, instanceGetPath, core.Parameters{"id": parameters["id"]}, configs)
+		if isNotFoundError(err) {
+			return fmt.Errorf("instance %v not found: %w", parameters["id"], err)
+		}
+		return nil
+	}
+
+	if err := followInitLogs(ctx, fetch, checkInstance, os.Stdout, options); err != nil {
+		return nil, err
+	}
+
+	// The lines were already printed
+	source := core.ResultSource{Executor: e, Context: ctx, Parameters: parameters, Configs: configs}
+	return core.NewSimpleResult(source, e.ResultSchema(), nil), nil
+}
+
+func (e *initLogsExecutor) Unwrap() core.Executor {
+	return e.Executor
+}
+
+type followOptions struct {
+	Interval       time.Duration
+	Timeout        time.Duration
+	FailurePattern *regexp.Regexp
+}
+
+func (p *initLogsFollowParams) followOptions() (options followOptions, err error) {
+	options.Interval = 5 * time.Second
+	if p.Interval != 0 {
+		options.Interval = p.Interval
+	}
+
+	options.Timeout = 15 * time.Minute
+	if p.Timeout != 0 {
+		options.Timeout = p.Timeout
+	}
+
+	if options.Interval < 0 || options.Timeout < 0 {
+		return options, fmt.Errorf("interval and timeout must be positive")
+	}
+
+	if p.FailurePattern != "" {
+		if options.FailurePattern, err = regexp.Compile(p.FailurePattern); err != nil {
+			return options, fmt.Errorf("invalid failure pattern: %w", err)
+		}
+	}
+	return
+}
+
+// Poll the logs and write the lines not seen before, until cloud-init finishes. Failures to
+// get the logs while the instance is still being created are retried, checkInstance is
+// called on the first logs not found to fail at once for unknown instances
+func followInitLogs(ctx context.Context, fetch func(context.Context) ([]string, error), checkInstance func(context.Context) error, w io.Writer, options followOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	var previous []string
+	instanceChecked := false
+	for {
+		lines, err := fetch(ctx)
+		switch {
+		case err == nil:
+			newLines := newLogLines(previous, lines)
+			if len(previous) > 0 && len(newLines) == len(lines) {
+				logger().Warnw("no overlap with the previous init logs, lines may be missing. Use a shorter --interval or a larger --max-lines-count", "lines", len(lines))
+			}
+			finished, err := writeNewLogLines(w, newLines, options.FailurePattern)
+			if err != nil || finished {
+				return err
+			}
+			previous = lines
+		case ctx.Err() != nil:
+			return followContextErr(ctx, options.Timeout)
+		case isRetryableInitLogsError(err):
+			if isNotFoundError(err) && !instanceChecked {
+				if err := checkInstance(ctx); err != nil {
+					return err
+				}
+				instanceChecked = true
+			}
+			logger().Infow("init logs not available yet, retrying", "interval", options.Interval, "error", err)
+		default:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return followContextErr(ctx, options.Timeout)
+		case <-time.After(options.Interval):
+		}
+	}
+}
+
+func writeNewLogLines(w io.Writer, lines []string, failurePattern *regexp.Regexp) (finished bool, err error) {
+	for _, line := range lines {
+		if _, err = fmt.Fprintln(w, line); err != nil {
+			return
+		}
+		if failurePattern != nil && failurePattern.MatchString(line) {
+			return false, fmt.Errorf("init logs matched the failure pattern: %q", line)
+		}
+		if cloudInitFinishedRe.MatchString(line) {
+			finished = true
+		}
+	}
+	return
+}
+
+func followContextErr(ctx context.Context, timeout time.Duration) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("cloud-init did not finish within %s: %w", timeout, context.DeadlineExceeded)
+	}
+	return ctx.Err()
+}
+
+func isNotFoundError(err error) bool {
+	var httpErr *mgcHttpPkg.HttpError
+	return errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound
+}
+
+func isRetryableInitLogsError(err error) bool {
+	var httpErr *mgcHttpPkg.HttpError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.Code == http.StatusNotFound || httpErr.Code == http.StatusConflict || httpErr.Code >= 500
+}
+
+// Both are the last lines of the logs, the current ones start with a suffix of the
+// previous ones when there are few new lines. The longest overlap is skipped, without
+// any overlap all the lines are new, as more lines were written than the window
+func newLogLines(previous, current []string) []string {
+	for n := min(len(previous), len(current)); n > 0; n-- {
+		if slices.Equal(previous[len(previous)-n:], current[:n]) {
+			return current[n:]
+		}
+	}
+	return current
+}
+
+var _ core.Executor = (*initLogsExecutor)(nil)
+var _ core.ExecutorWrapper = (*initLogsExecutor)(nil)
@@ -0,0 +1,129 @@
+package instances
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	mgcHttpPkg "github.com/MagaluCloud/magalu/mgc/core/http"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+func TestNewLogLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []string
+		current  []string
+		expected []string
+	}{
+		{"first poll", nil, []string{"a", "b"}, []string{"a", "b"}},
+		{"no new lines", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{"appended", []string{"a", "b"}, []string{"a", "b", "c"}, []string{"c"}},
+		{"window moved", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"}, []string{"d", "e"}},
+		{"repeated lines", []string{"x", "x"}, []string{"x", "x", "x"}, []string{"x"}},
+		{"no overlap", []string{"a", "b"}, []string{"c", "d"}, []string{"c", "d"}},
+	}
+
+	for _, tt := range tests {
+		if got := newLogLines(tt.previous, tt.current); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: newLogLines(%v, %v) = %v, want %v", tt.name, tt.previous, tt.current, got, tt.expected)
+		}
+	}
+}
+
+// Each call returns the next snapshot, the last one is repeated
+func fakeInitLogs(snapshots ...[]string) func(context.Context) ([]string, error) {
+	i := 0
+	return func(context.Context) ([]string, error) {
+		if i == 0 {
+			i++
+			return nil, &mgcHttpPkg.IdentifiableHttpError{HttpError: &mgcHttpPkg.HttpError{Code: 404}}
+		}
+		s := snapshots[min(i-1, len(snapshots)-1)]
+		i++
+		return s, nil
+	}
+}
+
+func instanceFound(context.Context) error {
+	return nil
+}
+
+func TestFollowInitLogs(t *testing.T) {
+	finished := "Cloud-init v. 24.1.3 finished at Tue, 16 Jul 2024 12:00:00 +0000. Datasource DataSourceOpenStackLocal.  Up 20.50 seconds"
+	snapshots := [][]string{
+		{"booting", "running modules"},
+		{"running modules", "installing nginx"},
+		{"installing nginx", "Failed to start nginx.service", finished},
+	}
+	options := followOptions{Interval: time.Millisecond, Timeout: time.Second}
+
+	var out bytes.Buffer
+	if err := followInitLogs(context.Background(), fakeInitLogs(snapshots...), instanceFound, &out, options); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := strings.Join([]string{"booting", "running modules", "installing nginx", "Failed to start nginx.service", finished}, "\n") + "\n"
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", out.String(), expected)
+	}
+
+	out.Reset()
+	options.FailurePattern = regexp.MustCompile(`Failed to start`)
+	err := followInitLogs(context.Background(), fakeInitLogs(snapshots...), instanceFound, &out, options)
+	if err == nil || !strings.Contains(err.Error(), "nginx.service") {
+		t.Errorf("expected failure pattern error, found: %v", err)
+	}
+	if strings.Contains(out.String(), "finished") {
+		t.Errorf("expected to stop at the failure, found:\n%s", out.String())
+	}
+
+	options = followOptions{Interval: time.Millisecond, Timeout: 20 * time.Millisecond}
+	err = followInitLogs(context.Background(), fakeInitLogs(snapshots[0]), instanceFound, &out, options)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected timeout, found: %v", err)
+	}
+}
+
+func TestFollowInitLogsUnknownInstance(t *testing.T) {
+	notFound := &mgcHttpPkg.IdentifiableHttpError{HttpError: &mgcHttpPkg.HttpError{Code: 404}}
+	calls := 0
+	fetch := func(context.Context) ([]string, error) {
+		calls++
+		return nil, notFound
+	}
+	checkInstance := func(context.Context) error {
+		return notFound
+	}
+
+	options := followOptions{Interval: time.Millisecond, Timeout: time.Second}
+	err := followInitLogs(context.Background(), fetch, checkInstance, &bytes.Buffer{}, options)
+	if !errors.Is(err, notFound) {
+		t.Errorf("expected the instance not found error, found: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected to fail at the first poll, got %d", calls)
+	}
+}
+
+func TestInitLogsFollowOptions(t *testing.T) {
+	p, err := utils.DecodeNewValue[initLogsFollowParams](map[string]any{"interval": "10s", "timeout": "30m"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	options, err := p.followOptions()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if options.Interval != 10*time.Second || options.Timeout != 30*time.Minute {
+		t.Errorf("unexpected options: %#v", options)
+	}
+
+	if _, err := utils.DecodeNewValue[initLogsFollowParams](map[string]any{"interval": "soon"}); err == nil {
+		t.Errorf("expected an error for an invalid duration")
+	}
+}
@@ -0,0 +1,99 @@
+package instances
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type scpParams struct {
+	Paths     []string `json:"paths" jsonschema_description:"Sources followed by the destination. Remote paths are given as INSTANCE:PATH, where INSTANCE is the instance name or ID" jsonschema:"minItems=2" mgc:"positional" jsonschema_extras:"x-mgc-verbatim-args=true"`
+	Recursive bool     `json:"recursive,omitempty" jsonschema_description:"Copy directories recursively"`
+	connectionParams
+}
+
+var getScp = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	return core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "scp",
+			Summary: "Copy files from or to instances with the system scp",
+			Description: `Copy files with the system 'scp', where remote paths use the instance name or ID.
+
+The instance address, user and private key are found the same way as the ssh command,
+for example:
+
+    mgc virtual-machine instances scp ./app.tar.gz my-vm:/tmp/
+    mgc virtual-machine instances scp --recursive my-vm:/var/log/app ./logs
+
+Paths after '--' are taken as given, such as those with commas.`,
+		},
+		runScp,
+	)
+})
+
+// Splits "INSTANCE:PATH", local paths such as "./a:b", "/a:b" or "C:\a" are not remote
+func splitRemotePath(p string) (instance string, path string, ok bool) {
+	instance, path, ok = strings.Cut(p, ":")
+	if !ok || instance == "" || strings.ContainsAny(instance, `/\`) || len(instance) == 1 {
+		return "", p, false
+	}
+	return
+}
+
+// Replace the instances in remote paths by their addresses, the identity file of the
+// first instance is used
+func scpArgs(paths []string, recursive bool, resolve func(instance string) (sshTarget, error)) ([]string, error) {
+	targets := map[string]sshTarget{}
+	var identityArgs []string
+	scpPaths := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		instance, path, ok := splitRemotePath(p)
+		if !ok {
+			scpPaths = append(scpPaths, p)
+			continue
+		}
+
+		target, found := targets[instance]
+		if !found {
+			var err error
+			if target, err = resolve(instance); err != nil {
+				return nil, err
+			}
+			targets[instance] = target
+			if identityArgs == nil {
+				identityArgs = target.identityArgs()
+			}
+		}
+		scpPaths = append(scpPaths, target.scpDestination()+":"+path)
+	}
+
+	if len(targets) == 0 {
+		return nil, core.UsageError{Err: fmt.Errorf("at least one path must be remote, given as INSTANCE:PATH")}
+	}
+
+	var options []string
+	if recursive {
+		options = []string{"-r"}
+	}
+	return slices.Concat(identityArgs, options, scpPaths), nil
+}
+
+func runScp(ctx context.Context, params scpParams, cfg Config) (core.Value, error) {
+	args, err := scpArgs(params.Paths, params.Recursive, func(name string) (sshTarget, error) {
+		inst, err := findInstance(ctx, name, cfg)
+		if err != nil {
+			return sshTarget{}, err
+		}
+		return inst.target(params.connectionParams)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, runSystemCommand(ctx, "scp", args)
+}
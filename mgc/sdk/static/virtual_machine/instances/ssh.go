@@ -0,0 +1,49 @@
+package instances
+
+import (
+	"context"
+	"slices"
+
+	"github.com/MagaluCloud/magalu/mgc/core"
+	"github.com/MagaluCloud/magalu/mgc/core/utils"
+)
+
+type sshParams struct {
+	Instance string   `json:"instance" jsonschema_description:"Name or ID of the instance" mgc:"positional"`
+	Args     []string `json:"args,omitempty" jsonschema_description:"Extra arguments given to ssh after the destination, such as options or a remote command. Use '--' before them" mgc:"positional" jsonschema_extras:"x-mgc-verbatim-args=true"`
+	connectionParams
+}
+
+var getSsh = utils.NewLazyLoader[core.Executor](func() core.Executor {
+	return core.NewStaticExecute(
+		core.DescriptorSpec{
+			Name:    "ssh",
+			Summary: "Connect to an instance with the system ssh",
+			Description: `Find the instance by name or ID and run the system 'ssh' to its public address.
+
+The user is chosen by the instance image, the private key is looked up in ~/.ssh by
+the instance ssh_key_name. Arguments after '--' are given to ssh, for example:
+
+    mgc virtual-machine instances ssh my-vm -- -L 8080:localhost:80 uptime`,
+		},
+		runSsh,
+	)
+})
+
+func sshArgs(target sshTarget, extraArgs []string) []string {
+	return slices.Concat(target.identityArgs(), []string{target.destination()}, extraArgs)
+}
+
+func runSsh(ctx context.Context, params sshParams, cfg Config) (core.Value, error) {
+	inst, err := findInstance(ctx, params.Instance, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := inst.target(params.connectionParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, runSystemCommand(ctx, "ssh", sshArgs(target, params.Args))
+}
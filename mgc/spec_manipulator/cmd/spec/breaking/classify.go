@@ -0,0 +1,478 @@
+package breaking
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+type Kind string
+
+const (
+	OperationRemoved     Kind = "operation-removed"
+	OperationAdded       Kind = "operation-added"
+	CommandRenamed       Kind = "command-renamed"
+	ParameterRemoved     Kind = "parameter-removed"
+	ParameterRequired    Kind = "parameter-required"
+	ParameterAdded       Kind = "parameter-added"
+	FlagRenamed          Kind = "flag-renamed"
+	TypeChanged          Kind = "type-changed"
+	EnumChanged          Kind = "enum-changed"
+	ResponseFieldRemoved Kind = "response-field-removed"
+	ResponseTypeChanged  Kind = "response-type-changed"
+)
+
+type Change struct {
+	Kind      Kind
+	Breaking  bool
+	Operation string
+	// CLI commands affected by the change, as in the old spec
+	Commands []string
+	Flag     string
+	Message  string
+}
+
+func (c Change) String() string {
+	level := "info"
+	if c.Breaking {
+		level = "BREAKING"
+	}
+	commands := strings.Join(c.Commands, ", ")
+	if c.Flag != "" {
+		commands += " --" + c.Flag
+	}
+	return fmt.Sprintf("%s [%s] %s: %s (%s)", level, c.Kind, commands, c.Message, c.Operation)
+}
+
+func HasBreaking(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(c Change) bool { return c.Breaking })
+}
+
+func LoadDocument(data []byte) (*v3.Document, error) {
+	document, err := libopenapi.NewDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	model, errs := document.BuildV3Model()
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("unable to build the OpenAPI model: %w", errs[0])
+	}
+	return &model.Model, nil
+}
+
+// Compare the specs of a module and report the changes that affect its CLI commands.
+// Removals and new requirements on what's sent are breaking, as are removals and type
+// changes on what's received
+func Classify(module string, oldDoc, newDoc *v3.Document) []Change {
+	oldCommands := commandsByOperation(module, oldDoc)
+	newCommands := commandsByOperation(module, newDoc)
+	oldOps := operationsByKey(oldDoc)
+	newOps := operationsByKey(newDoc)
+
+	var changes []Change
+	for _, key := range sortedKeys(oldOps) {
+		commands := oldCommands[key]
+		newOp, ok := newOps[key]
+		if !ok {
+			changes = append(changes, Change{Kind: OperationRemoved, Breaking: true, Operation: key, Commands: commands, Message: "operation removed"})
+			continue
+		}
+
+		if renamed := newCommands[key]; len(commands) > 0 && !slices.Equal(commands, renamed) {
+			changes = append(changes, Change{
+				Kind:      CommandRenamed,
+				Breaking:  true,
+				Operation: key,
+				Commands:  commands,
+				Message:   fmt.Sprintf("command renamed to %s", strings.Join(renamed, ", ")),
+			})
+		}
+
+		changes = append(changes, compareInputs(key, commands, inputFields(oldOps[key]), inputFields(newOp))...)
+		changes = append(changes, compareOutputs(key, commands, outputFields(oldOps[key]), outputFields(newOp))...)
+	}
+
+	for _, key := range sortedKeys(newOps) {
+		if _, ok := oldOps[key]; !ok {
+			changes = append(changes, Change{Kind: OperationAdded, Operation: key, Commands: newCommands[key], Message: "operation added"})
+		}
+	}
+	return changes
+}
+
+func operationsByKey(doc *v3.Document) map[string]*opWithPath {
+	result := map[string]*opWithPath{}
+	if doc.Paths == nil {
+		return result
+	}
+	for pair := doc.Paths.PathItems.Oldest(); pair != nil; pair = pair.Next() {
+		ops := pair.Value.GetOperations()
+		for _, method := range httpMethods {
+			if op, ok := ops.Get(method); ok && op != nil {
+				result[operationKey(method, pair.Key)] = &opWithPath{op: op, pathItem: pair.Value}
+			}
+		}
+	}
+	return result
+}
+
+type opWithPath struct {
+	op       *v3.Operation
+	pathItem *v3.PathItem
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// A parameter, body property or response field. The ID doesn't change with x-mgc-name,
+// so renamed flags are detected
+type field struct {
+	id       string
+	flag     string
+	required bool
+	types    string
+	enum     []string
+}
+
+func compareInputs(key string, commands []string, oldFields, newFields map[string]*field) []Change {
+	var changes []Change
+	add := func(kind Kind, breaking bool, flag, message string, args ...any) {
+		changes = append(changes, Change{Kind: kind, Breaking: breaking, Operation: key, Commands: commands, Flag: flag, Message: fmt.Sprintf(message, args...)})
+	}
+
+	for _, id := range sortedKeys(oldFields) {
+		oldField := oldFields[id]
+		newField, ok := newFields[id]
+		if !ok {
+			add(ParameterRemoved, true, oldField.flag, "%s removed", id)
+			continue
+		}
+
+		if oldField.flag != newField.flag {
+			add(FlagRenamed, true, oldField.flag, "flag renamed to --%s", newField.flag)
+		}
+		if !oldField.required && newField.required {
+			add(ParameterRequired, true, oldField.flag, "%s is now required", id)
+		}
+		if oldField.types != newField.types {
+			add(TypeChanged, true, oldField.flag, "%s type changed from %s to %s", id, oldField.types, newField.types)
+		}
+		if removed := removedEnumValues(oldField.enum, newField.enum); len(removed) > 0 {
+			add(EnumChanged, true, oldField.flag, "%s no longer accepts %s", id, strings.Join(removed, ", "))
+		} else if len(oldField.enum) == 0 && len(newField.enum) > 0 {
+			add(EnumChanged, true, oldField.flag, "%s now only accepts %s", id, strings.Join(newField.enum, ", "))
+		}
+	}
+
+	for _, id := range sortedKeys(newFields) {
+		if _, ok := oldFields[id]; ok {
+			continue
+		}
+		newField := newFields[id]
+		if newField.required {
+			add(ParameterRequired, true, newField.flag, "new required %s", id)
+		} else {
+			add(ParameterAdded, false, newField.flag, "new optional %s", id)
+		}
+	}
+	return changes
+}
+
+func compareOutputs(key string, commands []string, oldFields, newFields map[string]*field) []Change {
+	var changes []Change
+	for _, id := range sortedKeys(oldFields) {
+		oldField := oldFields[id]
+		newField, ok := newFields[id]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: ResponseFieldRemoved, Breaking: true, Operation: key, Commands: commands, Message: fmt.Sprintf("response field %s removed", id)})
+		case oldField.types != newField.types:
+			changes = append(changes, Change{
+				Kind:      ResponseTypeChanged,
+				Breaking:  true,
+				Operation: key,
+				Commands:  commands,
+				Message:   fmt.Sprintf("response field %s type changed from %s to %s", id, oldField.types, newField.types),
+			})
+		}
+	}
+	return changes
+}
+
+func removedEnumValues(oldEnum, newEnum []string) []string {
+	if len(newEnum) == 0 {
+		return nil
+	}
+	var removed []string
+	for _, value := range oldEnum {
+		if !slices.Contains(newEnum, value) {
+			removed = append(removed, value)
+		}
+	}
+	return removed
+}
+
+// Parameters and JSON body properties, keyed by "<location> <name>", such as "query _limit"
+// or "body machine_type.name"
+func inputFields(o *opWithPath) map[string]*field {
+	fields := map[string]*field{}
+
+	byName := map[string][]*v3.Parameter{}
+	var parameters []*v3.Parameter
+	// operation parameters override the ones of the path with the same name and location
+	for _, p := range append(slices.Clone(o.op.Parameters), o.pathItem.Parameters...) {
+		if p == nil || slices.ContainsFunc(parameters, func(other *v3.Parameter) bool { return other.Name == p.Name && other.In == p.In }) {
+			continue
+		}
+		if p.In == "header" && strings.HasPrefix(strings.ToLower(p.Name), "content-") {
+			continue
+		}
+		parameters = append(parameters, p)
+		byName[p.Name] = append(byName[p.Name], p)
+	}
+
+	flags := map[string]bool{}
+	for _, p := range parameters {
+		name := nameExtension(p.Extensions, "")
+		if name == "" {
+			name = p.Name
+			if len(byName[p.Name]) > 1 {
+				name = p.In + "-" + p.Name
+			}
+		}
+		f := &field{id: p.In + " " + p.Name, flag: flagName(name), required: p.Required != nil && *p.Required}
+		f.types, f.enum = schemaType(p.Schema)
+		fields[f.id] = f
+		flags[f.flag] = true
+	}
+
+	if o.op.RequestBody == nil {
+		return fields
+	}
+	schema := jsonSchema(o.op.RequestBody.Content)
+	if schema == nil {
+		return fields
+	}
+	walkProperties(schema, func(path, flag string, required bool, prop *base.SchemaProxy) {
+		// top level properties conflicting with parameters are prefixed, see request_body_json.go
+		if !strings.Contains(path, ".") && flags[flag] {
+			flag = "req-" + flag
+		}
+		f := &field{id: "body " + path, flag: flag, required: required}
+		f.types, f.enum = schemaType(prop)
+		fields[f.id] = f
+	})
+	return fields
+}
+
+// Fields of the first JSON response with 2xx status, keyed by path, such as "results[].id"
+func outputFields(o *opWithPath) map[string]*field {
+	fields := map[string]*field{}
+	if o.op.Responses == nil || o.op.Responses.Codes == nil {
+		return fields
+	}
+
+	for pair := o.op.Responses.Codes.Oldest(); pair != nil; pair = pair.Next() {
+		if !strings.HasPrefix(pair.Key, "2") || pair.Value == nil {
+			continue
+		}
+		schema := jsonSchema(pair.Value.Content)
+		if schema == nil {
+			continue
+		}
+		walkSchema(schema, "", nil, func(path, _ string, _ *base.Schema, prop *base.SchemaProxy) bool {
+			f := &field{id: path}
+			f.types, _ = schemaType(prop)
+			fields[path] = f
+			return true
+		})
+		break
+	}
+	return fields
+}
+
+// Nested objects are flattened as the CLI flags, such as "--machine-type.name"
+// The flags of required properties are required, even if the body isn't
+func walkProperties(proxy *base.SchemaProxy, cb func(path, flag string, required bool, prop *base.SchemaProxy)) {
+	flags := map[string]string{}
+	requiredPaths := map[string]bool{"": true}
+	walkSchema(proxy, "", nil, func(path, parentPath string, parent *base.Schema, prop *base.SchemaProxy) bool {
+		// array items aren't flags, the whole array is given as JSON
+		if strings.HasSuffix(path, "[]") {
+			return false
+		}
+
+		name := path[strings.LastIndex(path, ".")+1:]
+		flag := flagName(nameExtension(resolvedExtensions(prop), name))
+		if parentFlag := flags[parentPath]; parentFlag != "" {
+			flag = parentFlag + "." + flag
+		}
+		flags[path] = flag
+
+		// nested properties are only required if their parents are
+		requiredPaths[path] = requiredPaths[parentPath] && slices.Contains(schemaRequired(parent), name)
+		cb(path, flag, requiredPaths[path], prop)
+		return true
+	})
+}
+
+const maxDepth = 8
+
+// Calls cb for every property and array items ("path[]") below the schema, with the path and
+// schema of their parent. cb returns whether to walk below it. References already being
+// walked are skipped, so recursive schemas end
+func walkSchema(proxy *base.SchemaProxy, path string, refs []string, cb func(path, parentPath string, parent *base.Schema, prop *base.SchemaProxy) bool) {
+	s := resolvedSchema(proxy)
+	if s == nil || len(refs) > maxDepth {
+		return
+	}
+	ref := ""
+	if proxy.IsReference() {
+		ref = proxy.GetReference()
+		if slices.Contains(refs, ref) {
+			return
+		}
+	}
+	refs = append(slices.Clone(refs), ref)
+
+	visit := func(childPath string, child *base.SchemaProxy) {
+		if cb(childPath, path, s, child) {
+			walkSchema(child, childPath, refs, cb)
+		}
+	}
+	if s.Items != nil && s.Items.IsA() {
+		visit(path+"[]", s.Items.A)
+	}
+	properties := schemaProperties(s)
+	for _, name := range sortedKeys(properties) {
+		visit(joinPath(path, name), properties[name])
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonSchema(content *orderedmap.Map[string, *v3.MediaType]) *base.SchemaProxy {
+	if content == nil {
+		return nil
+	}
+	for pair := content.Oldest(); pair != nil; pair = pair.Next() {
+		if strings.Contains(pair.Key, "json") && pair.Value != nil {
+			return pair.Value.Schema
+		}
+	}
+	return nil
+}
+
+func resolvedSchema(proxy *base.SchemaProxy) *base.Schema {
+	if proxy == nil {
+		return nil
+	}
+	return proxy.Schema()
+}
+
+func resolvedExtensions(proxy *base.SchemaProxy) *orderedmap.Map[string, *yaml.Node] {
+	if s := resolvedSchema(proxy); s != nil {
+		return s.Extensions
+	}
+	return nil
+}
+
+// Properties, including the ones of allOf
+func schemaProperties(s *base.Schema) map[string]*base.SchemaProxy {
+	result := map[string]*base.SchemaProxy{}
+	for _, sub := range s.AllOf {
+		if subSchema := resolvedSchema(sub); subSchema != nil {
+			for name, prop := range schemaProperties(subSchema) {
+				result[name] = prop
+			}
+		}
+	}
+	if s.Properties != nil {
+		for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			result[pair.Key] = pair.Value
+		}
+	}
+	return result
+}
+
+func schemaRequired(s *base.Schema) []string {
+	required := slices.Clone(s.Required)
+	for _, sub := range s.AllOf {
+		if subSchema := resolvedSchema(sub); subSchema != nil {
+			required = append(required, schemaRequired(subSchema)...)
+		}
+	}
+	return required
+}
+
+// Type such as "string", "array<integer>" or "oneOf<object|string>", and the enum values
+func schemaType(proxy *base.SchemaProxy) (string, []string) {
+	s := resolvedSchema(proxy)
+	if s == nil {
+		return "", nil
+	}
+
+	var enum []string
+	for _, node := range s.Enum {
+		if node != nil {
+			enum = append(enum, node.Value)
+		}
+	}
+
+	types := slices.Clone(s.Type)
+	slices.Sort(types)
+	result := strings.Join(types, "|")
+	switch {
+	case slices.Contains(s.Type, "array") && s.Items != nil && s.Items.IsA():
+		itemType, _ := schemaType(s.Items.A)
+		result = fmt.Sprintf("%s<%s>", result, itemType)
+	case result == "" && len(s.OneOf)+len(s.AnyOf) > 0:
+		var alternatives []string
+		for _, sub := range append(slices.Clone(s.OneOf), s.AnyOf...) {
+			t, _ := schemaType(sub)
+			alternatives = append(alternatives, t)
+		}
+		slices.Sort(alternatives)
+		result = fmt.Sprintf("oneOf<%s>", strings.Join(alternatives, "|"))
+	case result == "" && len(s.AllOf) > 0:
+		result = "object"
+	}
+	if s.Nullable != nil && *s.Nullable {
+		result += "|null"
+	}
+	return result, enum
+}
+
+func extensionString(extensions *orderedmap.Map[string, *yaml.Node], key string) string {
+	if extensions == nil {
+		return ""
+	}
+	if node, ok := extensions.Get(key); ok && node != nil && node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	return ""
+}
+
+func nameExtension(extensions *orderedmap.Map[string, *yaml.Node], def string) string {
+	if name := extensionString(extensions, extensionPrefix+"-name"); name != "" {
+		return name
+	}
+	return def
+}
@@ -0,0 +1,190 @@
+package breaking
+
+import (
+	"slices"
+	"testing"
+)
+
+const oldSpec = `
+openapi: 3.0.3
+info: {title: test, version: "1.0"}
+tags:
+  - name: instances
+  - name: snapshots
+paths:
+  /v1/instances:
+    get:
+      tags: [instances]
+      parameters:
+        - {name: _limit, in: query, schema: {type: integer}}
+        - {name: status, in: query, schema: {type: string, enum: [active, stopped, deleted]}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  results:
+                    type: array
+                    items: {$ref: "#/components/schemas/Instance"}
+    post:
+      tags: [instances]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                machine_type:
+                  type: object
+                  properties:
+                    name: {type: string}
+                    id: {type: string}
+      responses:
+        "200": {description: ok}
+  /v1/instances/{id}:
+    get:
+      tags: [instances]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: ok}
+  /v1/snapshots/{id}:
+    delete:
+      tags: [snapshots]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "204": {description: ok}
+components:
+  schemas:
+    Instance:
+      type: object
+      properties:
+        id: {type: string}
+        ip: {type: string}
+        parent: {$ref: "#/components/schemas/Instance"}
+`
+
+const newSpec = `
+openapi: 3.0.3
+info: {title: test, version: "1.1"}
+tags:
+  - name: instances
+    x-mgc-name: vms
+  - name: snapshots
+paths:
+  /v1/instances:
+    get:
+      tags: [instances]
+      parameters:
+        - {name: _limit, in: query, schema: {type: string}}
+        - {name: status, in: query, schema: {type: string, enum: [active, stopped]}}
+        - {name: zone, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  results:
+                    type: array
+                    items: {$ref: "#/components/schemas/Instance"}
+    post:
+      tags: [instances]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, machine_type]
+              properties:
+                name: {type: string, x-mgc-name: instance-name}
+                machine_type:
+                  type: object
+                  required: [name]
+                  properties:
+                    name: {type: string}
+                    id: {type: string}
+      responses:
+        "200": {description: ok}
+  /v1/instances/{id}:
+    get:
+      tags: [instances]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    Instance:
+      type: object
+      properties:
+        id: {type: string}
+        parent: {$ref: "#/components/schemas/Instance"}
+`
+
+func classify(t *testing.T, spec string) []Change {
+	t.Helper()
+	oldDoc, err := LoadDocument([]byte(oldSpec))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	newDoc, err := LoadDocument([]byte(spec))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return Classify("compute", oldDoc, newDoc)
+}
+
+func TestClassify(t *testing.T) {
+	changes := classify(t, newSpec)
+
+	expected := []struct {
+		kind     Kind
+		command  string
+		flag     string
+		breaking bool
+	}{
+		{OperationRemoved, "mgc compute snapshots delete", "", true},
+		{CommandRenamed, "mgc compute instances list", "", true},
+		{TypeChanged, "mgc compute instances list", "control.limit", true},
+		{EnumChanged, "mgc compute instances list", "status", true},
+		{ParameterAdded, "mgc compute instances list", "zone", false},
+		{ResponseFieldRemoved, "mgc compute instances list", "", true},
+		{FlagRenamed, "mgc compute instances create", "name", true},
+		{ParameterRequired, "mgc compute instances create", "machine-type", true},
+		{ParameterRequired, "mgc compute instances create", "machine-type.name", true},
+	}
+	for _, e := range expected {
+		found := slices.ContainsFunc(changes, func(c Change) bool {
+			return c.Kind == e.kind && slices.Contains(c.Commands, e.command) && c.Flag == e.flag && c.Breaking == e.breaking
+		})
+		if !found {
+			t.Errorf("expected %s on %s --%s, got:", e.kind, e.command, e.flag)
+			for _, c := range changes {
+				t.Log(c)
+			}
+		}
+	}
+
+	// optional nested fields of optional objects are not required
+	for _, c := range changes {
+		if c.Flag == "machine-type.id" {
+			t.Errorf("unexpected change %s", c)
+		}
+	}
+	if !HasBreaking(changes) {
+		t.Errorf("expected breaking changes")
+	}
+
+	if changes := classify(t, oldSpec); len(changes) > 0 {
+		t.Errorf("expected no changes for the same spec, got %v", changes)
+	}
+}
@@ -0,0 +1,94 @@
+package breaking
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/sdk/openapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/stoewer/go-strcase"
+)
+
+// Command names are given by mgc/sdk/openapi, so changes can be reported as the CLI commands they break
+
+const extensionPrefix = "x-mgc"
+
+var httpMethods = []string{"get", "post", "put", "patch", "delete"}
+
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// Adds the commands of the operations of a resource (OAPI Tag), by operationKey()
+func collectCommands(prefix []string, resourceName string, ops []*v3.Operation, refs []openapi.OperationRef, commands map[string][]string) {
+	for i, names := range openapi.OperationNames(resourceName, refs) {
+		names[len(names)-1] = nameExtension(ops[i].Extensions, names[len(names)-1])
+		command := slices.Clone(prefix)
+		for _, name := range names {
+			command = append(command, strcase.KebabCase(name))
+		}
+		key := operationKey(refs[i].Method, refs[i].Path)
+		commands[key] = append(commands[key], strings.Join(command, " "))
+	}
+}
+
+// CLI commands of each operation, by operationKey(). Operations with many tags have many commands
+func commandsByOperation(module string, doc *v3.Document) map[string][]string {
+	type resource struct {
+		ops  []*v3.Operation
+		refs []openapi.OperationRef
+	}
+	untagged := &resource{}
+	byTag := map[string]*resource{}
+	add := func(r *resource, op *v3.Operation, ref openapi.OperationRef) {
+		r.ops = append(r.ops, op)
+		r.refs = append(r.refs, ref)
+	}
+
+	if doc.Paths != nil {
+		for pair := doc.Paths.PathItems.Oldest(); pair != nil; pair = pair.Next() {
+			ops := pair.Value.GetOperations()
+			for _, method := range httpMethods {
+				op, ok := ops.Get(method)
+				if !ok || op == nil {
+					continue
+				}
+
+				ref := openapi.OperationRef{Method: method, Path: pair.Key, CliName: extensionString(op.Extensions, "x-cli-name")}
+				if len(op.Tags) == 0 {
+					add(untagged, op, ref)
+				}
+				for _, tag := range op.Tags {
+					if byTag[tag] == nil {
+						byTag[tag] = &resource{}
+					}
+					add(byTag[tag], op, ref)
+				}
+			}
+		}
+	}
+
+	commands := map[string][]string{}
+	collectCommands([]string{"mgc", module}, "", untagged.ops, untagged.refs, commands)
+	// like the CLI, operations with tags not listed in the document are not available
+	for _, tag := range doc.Tags {
+		r := byTag[tag.Name]
+		if r == nil {
+			continue
+		}
+		name := nameExtension(tag.Extensions, tag.Name)
+		collectCommands([]string{"mgc", module, strcase.KebabCase(name)}, tag.Name, r.ops, r.refs, commands)
+	}
+	for _, c := range commands {
+		slices.Sort(c)
+	}
+	return commands
+}
+
+// Flags of parameters starting with "_" are under "control.", such as "--control.limit"
+func flagName(name string) string {
+	if rest, ok := strings.CutPrefix(name, "_"); ok {
+		return "control." + strcase.KebabCase(rest)
+	}
+	return strcase.KebabCase(name)
+}
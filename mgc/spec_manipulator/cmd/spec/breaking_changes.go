@@ -0,0 +1,81 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MagaluCloud/magalu/mgc/spec_manipulator/cmd/spec/breaking"
+	"github.com/spf13/cobra"
+)
+
+var errBreakingChanges = errors.New("breaking changes found, review the affected commands before updating the spec")
+
+// "virtual-machine.jaxyendy.openapi.json" is the "virtual-machine" module
+func moduleFromFile(file string) string {
+	name, _, _ := strings.Cut(filepath.Base(file), ".")
+	return name
+}
+
+func classifySpecChanges(module, oldFile, newFile string, showAll bool) (bool, error) {
+	oldData, err := os.ReadFile(oldFile)
+	if err != nil {
+		return false, err
+	}
+	newData, err := os.ReadFile(newFile)
+	if err != nil {
+		return false, err
+	}
+
+	oldDoc, err := breaking.LoadDocument(oldData)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", oldFile, err)
+	}
+	newDoc, err := breaking.LoadDocument(newData)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", newFile, err)
+	}
+
+	changes := breaking.Classify(module, oldDoc, newDoc)
+	for _, c := range changes {
+		if c.Breaking || showAll {
+			fmt.Println(c)
+		}
+	}
+	return breaking.HasBreaking(changes), nil
+}
+
+func breakingChangesCmd() *cobra.Command {
+	var module string
+	var showAll bool
+
+	cmd := &cobra.Command{
+		Use:   "breaking-changes [old] [new]",
+		Short: "Classify the changes between two versions of a spec",
+		Long: `Compare two versions of a module spec and list the changes that break CLI commands:
+removed operations, newly required parameters, changed types and enums, removed response
+fields and renamed x-mgc-name values. Exits with an error if any is found.`,
+		Example: "breaking-changes cli_specs/network.openapi.yaml /tmp/network.openapi.yaml",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if module == "" {
+				module = moduleFromFile(args[0])
+			}
+			hasBreaking, err := classifySpecChanges(module, args[0], args[1], showAll)
+			if err != nil {
+				return err
+			}
+			if hasBreaking {
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				return errBreakingChanges
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&module, "module", "m", "", "CLI module of the spec, defaults to the first part of the file name")
+	cmd.Flags().BoolVarP(&showAll, "all", "a", false, "Also list the changes that don't break commands")
+	return cmd
+}
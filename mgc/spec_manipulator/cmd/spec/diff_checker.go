@@ -26,7 +26,7 @@ func diffCheckerCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "diff [dir] [menu]",
 		Short: "Download available spec",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			_ = verificarEAtualizarDiretorio(dir)
 
@@ -39,8 +39,9 @@ func diffCheckerCmd() *cobra.Command {
 				currentConfig, err = getConfigToRun()
 			}
 			if err != nil {
-				return
+				return nil
 			}
+			var breakingSpecs []string
 			spinner := tui.NewSpinner()
 			spinner.Start("Downloading ...")
 			for _, v := range currentConfig {
@@ -54,14 +55,14 @@ func diffCheckerCmd() *cobra.Command {
 				if !strings.Contains(v.Url, "gitlab.luizalabs.com") {
 					err = getAndSaveFile(v.Url, tmpFile, v.Menu)
 					if err != nil {
-						return
+						return nil
 					}
 				}
 
 				if strings.Contains(v.Url, "gitlab.luizalabs.com") {
 					err = downloadGitlab(v.Url, tmpFile)
 					if err != nil {
-						return
+						return nil
 					}
 				}
 
@@ -69,8 +70,22 @@ func diffCheckerCmd() *cobra.Command {
 
 				//
 				runMarkdownReport(filepath.Join(dir, v.File), tmpFile)
+
+				hasBreaking, err := classifySpecChanges(v.Menu, filepath.Join(dir, v.File), tmpFile, false)
+				if err != nil {
+					fmt.Printf("Erro ao classificar as mudanças de %s: %v\n", v.File, err)
+				} else if hasBreaking {
+					breakingSpecs = append(breakingSpecs, v.File)
+				}
 			}
 			spinner.Success("Specs downloaded successfully")
+
+			if len(breakingSpecs) > 0 {
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				return fmt.Errorf("%s: %w", strings.Join(breakingSpecs, ", "), errBreakingChanges)
+			}
+			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory to save the converted specs")
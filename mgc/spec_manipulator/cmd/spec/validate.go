@@ -1,10 +1,12 @@
 package spec
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/MagaluCloud/magalu/mgc/sdk/openapi"
 	"github.com/pb33f/libopenapi"
 
 	validator "github.com/pb33f/libopenapi-validator"
@@ -12,6 +14,20 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var errLintFindings = errors.New("problems found in the x-mgc extensions")
+
+// Print each finding as "file:line:column: [rule] message", returns how many were found
+func lintSpecFile(file string, data []byte) (int, error) {
+	findings, err := openapi.LintDocument(data, "x-mgc")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", file, err)
+	}
+	for _, f := range findings {
+		fmt.Printf("%s:%s\n", file, f)
+	}
+	return len(findings), nil
+}
+
 func justRunValidate(dir string, v specList) {
 	file := filepath.Join(dir, v.File)
 	fileBytes, err := os.ReadFile(file)
@@ -40,6 +56,10 @@ func justRunValidate(dir string, v specList) {
 		}
 	}
 
+	if _, err := lintSpecFile(file, fileBytes); err != nil {
+		fmt.Println(err)
+	}
+
 	fileBytes, _, _, errs := document.RenderAndReload()
 	if len(errs) > 0 {
 		panic(fmt.Sprintf("cannot re-render document: %d errors reported", len(errs)))
@@ -80,3 +100,37 @@ func validateSpec() *cobra.Command {
 	cmd.Flags().StringVarP(&menu, "menu", "m", "", "Menu to validate")
 	return cmd
 }
+
+func lintSpecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [files...]",
+		Short: "Check the x-mgc extensions of specs",
+		Long: `Check the x-mgc extensions the same way the SDK uses them: wait-termination queries and
+confirmable templates must compile, link runtime expressions must resolve against the operations
+and response schemas, extra-parameters must not collide and x-mgc-name renames must not produce
+duplicated commands. Exits with an error if any problem is found.`,
+		Example: "lint mgc/sdk/openapi/openapis/*.openapi.yaml",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			total := 0
+			for _, file := range args {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				count, err := lintSpecFile(file, data)
+				if err != nil {
+					return err
+				}
+				total += count
+			}
+			if total > 0 {
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				return errLintFindings
+			}
+			return nil
+		},
+	}
+	return cmd
+}
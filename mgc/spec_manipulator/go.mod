@@ -3,11 +3,13 @@ module github.com/MagaluCloud/magalu/mgc/spec_manipulator
 go 1.24.0
 
 require (
+	github.com/MagaluCloud/magalu/mgc/sdk v0.33.3
 	github.com/google/uuid v1.6.0
 	github.com/pb33f/libopenapi v0.22.2
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2
 	github.com/pterm/pterm v0.12.80
 	github.com/spf13/cobra v1.9.1
+	github.com/stoewer/go-strcase v1.3.0
 	golang.org/x/text v0.25.0
 	gopkg.in/yaml.v3 v3.0.1
 )